@@ -52,7 +52,11 @@ func init() {
 	}
 }
 
+// nil if there are no public addresses, only offline mode logins work then
 func getRandomAuthenticator() *Authenticator {
+	if len(authenticators) == 0 {
+		return nil
+	}
 	return authenticators[math_rand.Intn(len(authenticators))]
 }
 
@@ -0,0 +1,258 @@
+package potoq
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Craftserve/potoq/packets"
+	"github.com/Craftserve/potoq/utils"
+)
+
+// What happens with packets from upstream when player's connection uses more memory than MemoryBudget.
+// Memory counted is pooled buffers of packets read but not written yet and data queued for player
+// (fixed bufio and packet writer buffers aren't included).
+type MemoryPolicy int
+
+const (
+	// stop reading upstream until player receives queued data, upstream sees it as slow client
+	MemoryWait MemoryPolicy = iota
+	// drop packets marked in NonCriticalPackets, wait like MemoryWait for other ones
+	MemoryDropNonCritical
+	// disconnect player with ErrMemoryBudget
+	MemoryDisconnect
+)
+
+func (p MemoryPolicy) String() string {
+	switch p {
+	case MemoryWait:
+		return "wait"
+	case MemoryDropNonCritical:
+		return "drop"
+	case MemoryDisconnect:
+		return "disconnect"
+	default:
+		return "invalid"
+	}
+}
+
+// Budget of newly created handlers in bytes, see MemoryPolicy
+var MemoryBudget = 4 * 1024 * 1024
+
+// Policy of newly created handlers, can be changed per handler before handleProxy
+var DefaultMemoryPolicy = MemoryWait
+
+var ErrMemoryBudget = errors.New("connection memory budget exceeded")
+
+// Clientbound PLAY packet ids which can be dropped by MemoryDropNonCritical,
+// only cosmetic ones which don't change client state by default
+var NonCriticalPackets = utils.NewBitarray(packets.MaxPacketID)
+
+// how long closing handler waits for player to receive queued data (eg. kick message)
+const downstreamDrainTimeout = 5 * time.Second
+
+func init() {
+	for _, id := range []int{
+		0x05, // Entity Animation
+		0x08, // Block Break Animation
+		0x18, // Named Sound Effect
+		0x21, // Effect
+		0x22, // Particle
+		0x4E, // Time Update
+		0x50, // Entity Sound Effect
+		0x51, // Sound Effect
+	} {
+		NonCriticalPackets.Set(id, true)
+	}
+}
+
+// Current memory usage of connection in bytes, see MemoryPolicy
+func (handler *Handler) MemoryUsage() int {
+	return handler.memory.usage()
+}
+
+// Applies MemoryPolicy to packet from upstream, called by pump without handler lock before packet is accounted.
+func (handler *Handler) checkMemory(packet queuedPacket) (drop bool, err error) {
+	if !handler.memory.exceeded() {
+		return false, nil
+	}
+	switch handler.MemoryPolicy {
+	case MemoryDisconnect:
+		return false, ErrMemoryBudget
+	case MemoryDropNonCritical:
+		if NonCriticalPackets.Get(int(packet.packet.PacketID())) {
+			return true, nil
+		}
+	}
+	if !handler.memory.wait() {
+		return false, errPumpStopped
+	}
+	return false, nil
+}
+
+// returns pooled buffer of packet read by pump
+func (handler *Handler) releasePacket(queued queuedPacket) {
+	raw := queued.rawPacket()
+	if raw.Payload != nil {
+		handler.memory.release(len(raw.Payload))
+		packets.BufferPool.Put(raw.Payload)
+	}
+}
+
+type memoryBudget struct {
+	lock   sync.Mutex
+	cond   sync.Cond // broadcasted when memory is released or budget closed
+	limit  int
+	used   int
+	closed bool
+}
+
+func (m *memoryBudget) init(limit int) {
+	m.cond.L = &m.lock
+	m.limit = limit
+}
+
+func (m *memoryBudget) usage() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.used
+}
+
+func (m *memoryBudget) exceeded() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.limit > 0 && m.used >= m.limit
+}
+
+// never blocks, budget can be exceeded by data already read
+func (m *memoryBudget) acquire(n int) {
+	m.lock.Lock()
+	m.used += n
+	m.lock.Unlock()
+}
+
+func (m *memoryBudget) release(n int) {
+	m.lock.Lock()
+	m.used -= n
+	m.lock.Unlock()
+	m.cond.Broadcast()
+}
+
+// blocks until usage is below limit, false if budget was closed
+func (m *memoryBudget) wait() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for !m.closed && m.limit > 0 && m.used >= m.limit {
+		m.cond.Wait()
+	}
+	return !m.closed
+}
+
+// wakes up and fails all current and future waits
+func (m *memoryBudget) close() {
+	m.lock.Lock()
+	m.closed = true
+	m.lock.Unlock()
+	m.cond.Broadcast()
+}
+
+// Writes to conn from separate goroutine, so slow player blocks only this goroutine
+// and queued data is accounted in memoryBudget instead of stopping the writer.
+type queuedWriter struct {
+	conn   io.Writer
+	budget *memoryBudget
+	lock   sync.Mutex
+	cond   sync.Cond // signalled when queue changes or writer is closed
+	queue  [][]byte
+	err    error
+	closed bool
+	done   chan struct{}
+}
+
+func newQueuedWriter(conn io.Writer, budget *memoryBudget) *queuedWriter {
+	w := &queuedWriter{
+		conn:   conn,
+		budget: budget,
+		done:   make(chan struct{}),
+	}
+	w.cond.L = &w.lock
+	go w.loop()
+	return w
+}
+
+func (w *queuedWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	buf := packets.BufferPool.Get(len(p))
+	copy(buf, p)
+	w.budget.acquire(len(buf))
+	w.queue = append(w.queue, buf)
+	w.cond.Signal()
+	return len(p), nil
+}
+
+func (w *queuedWriter) loop() {
+	defer close(w.done)
+	var batch [][]byte
+	for {
+		w.lock.Lock()
+		for len(w.queue) == 0 && !w.closed {
+			w.cond.Wait()
+		}
+		if len(w.queue) == 0 { // closed and drained
+			w.lock.Unlock()
+			return
+		}
+		batch, w.queue = w.queue, batch[:0]
+		w.lock.Unlock()
+
+		for i, buf := range batch {
+			_, err := w.conn.Write(buf)
+			w.budget.release(len(buf))
+			packets.BufferPool.Put(buf)
+			batch[i] = nil
+			if err != nil {
+				w.fail(err, batch[i+1:])
+				return
+			}
+		}
+	}
+}
+
+// drops everything queued after write error, next Write returns err
+func (w *queuedWriter) fail(err error, rest [][]byte) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.err = err
+	for _, bufs := range [][][]byte{rest, w.queue} {
+		for _, buf := range bufs {
+			w.budget.release(len(buf))
+			packets.BufferPool.Put(buf)
+		}
+	}
+	w.queue = nil
+}
+
+// Stops writer, waits up to timeout until queued data is written. Underlying conn isn't closed.
+func (w *queuedWriter) Close(timeout time.Duration) error {
+	w.lock.Lock()
+	w.closed = true
+	w.cond.Signal()
+	w.lock.Unlock()
+
+	select {
+	case <-w.done:
+	case <-time.After(timeout):
+		return errors.New("queuedWriter: drain timeout")
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.err
+}
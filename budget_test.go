@@ -0,0 +1,151 @@
+package potoq
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Craftserve/potoq/packets"
+	"gopkg.in/tomb.v1"
+)
+
+type slowWriter struct {
+	bytes.Buffer
+	release chan struct{}
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.Buffer.Write(p)
+}
+
+func TestQueuedWriterAccounting(t *testing.T) {
+	var budget memoryBudget
+	budget.init(100)
+	conn := &slowWriter{release: make(chan struct{})}
+	w := newQueuedWriter(conn, &budget)
+
+	w.Write(make([]byte, 60))
+	w.Write(make([]byte, 60))
+	if !budget.exceeded() || budget.usage() != 120 {
+		t.Fatalf("queued data not accounted: %d", budget.usage())
+	}
+
+	waited := make(chan bool)
+	go func() { waited <- budget.wait() }()
+	select {
+	case <-waited:
+		t.Fatal("wait returned while budget exceeded")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	conn.release <- struct{}{}
+	if !<-waited {
+		t.Fatal("wait failed before close")
+	}
+
+	close(conn.release)
+	if err := w.Close(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if budget.usage() != 0 || conn.Len() != 120 {
+		t.Fatalf("usage %d after drain, written %d", budget.usage(), conn.Len())
+	}
+
+	budget.acquire(200)
+	budget.close()
+	if budget.wait() {
+		t.Fatal("wait succeeded after close")
+	}
+}
+
+// > MemoryPolicy
+
+const (
+	criticalPacket    = 0x0E // Chat Message
+	noncriticalPacket = 0x51 // Sound Effect
+)
+
+// uncompressed clientbound packets with given ids, each one with 600 bytes of data
+func memoryTestStream(ids ...packets.VarInt) []byte {
+	var stream bytes.Buffer
+	for _, id := range ids {
+		var payload bytes.Buffer
+		packets.WriteVarInt(&payload, id)
+		payload.Write(make([]byte, 600))
+		packets.WriteVarInt(&stream, packets.VarInt(payload.Len()))
+		payload.WriteTo(&stream)
+	}
+	return stream.Bytes()
+}
+
+// handler with 1000 bytes budget and player which doesn't read anything until release is closed
+func memoryTestHandler(policy MemoryPolicy) (*Handler, *slowWriter, *queuedWriter) {
+	handler := &Handler{Nickname: "budget", FlushPolicy: DefaultFlushPolicy, MemoryPolicy: policy}
+	handler.memory.init(1000)
+	conn := &slowWriter{release: make(chan struct{})}
+	out := newQueuedWriter(conn, &handler.memory)
+	handler.DownstreamW = packets.NewPacketWriter(out, packets.NoCompression)
+	return handler, conn, out
+}
+
+func receivedIDs(t *testing.T, data []byte) (ids []packets.VarInt) {
+	r := packets.NewPacketReader(bytes.NewReader(data), packets.NoCompression)
+	for {
+		raw, err := r.ReadPacket()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, raw.ID)
+	}
+}
+
+func TestMemoryDropNonCritical(t *testing.T) {
+	handler, conn, out := memoryTestHandler(MemoryDropNonCritical)
+	stream := memoryTestStream(criticalPacket, criticalPacket, noncriticalPacket, noncriticalPacket, criticalPacket)
+	tmb := &tomb.Tomb{}
+	go handler.pump(packets.NewPacketReader(bytes.NewReader(stream), packets.NoCompression), packets.ClientBound, tmb)
+
+	select {
+	case <-tmb.Dead():
+		t.Fatalf("critical packet didn't wait for memory: %v", tmb.Err())
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(conn.release)
+	<-tmb.Dead()
+	if err := tmb.Err(); err != io.EOF {
+		t.Fatal(err)
+	}
+	if err := out.Close(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	ids := receivedIDs(t, conn.Bytes())
+	if len(ids) != 3 || ids[0] != criticalPacket || ids[1] != criticalPacket || ids[2] != criticalPacket {
+		t.Fatalf("player received %v", ids)
+	}
+	if usage := handler.MemoryUsage(); usage != 0 {
+		t.Fatalf("%d bytes left in budget", usage)
+	}
+}
+
+func TestMemoryDisconnect(t *testing.T) {
+	handler, conn, out := memoryTestHandler(MemoryDisconnect)
+	stream := memoryTestStream(criticalPacket, criticalPacket, criticalPacket)
+	tmb := &tomb.Tomb{}
+	handler.pump(packets.NewPacketReader(bytes.NewReader(stream), packets.NoCompression), packets.ClientBound, tmb)
+	if err := tmb.Err(); !errors.Is(err, ErrMemoryBudget) {
+		t.Fatalf("pump error %v", err)
+	}
+	close(conn.release)
+	if err := out.Close(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if ids := receivedIDs(t, conn.Bytes()); len(ids) != 2 {
+		t.Fatalf("player received %v", ids)
+	}
+}
@@ -0,0 +1,142 @@
+package potoq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Craftserve/potoq/capture"
+	"github.com/Craftserve/potoq/packets"
+)
+
+// > binary session capture, see package capture and cmd/potoq-replay
+
+// Directory of capture files, each capture is written to <nickname>_<start time>.cap
+var CaptureDir = "capture"
+
+// Capture is stopped when its file grows over CaptureMaxSize bytes
+var CaptureMaxSize int64 = 512 * 1024 * 1024
+
+var errCaptureTooLarge = errors.New("capture file too large")
+
+// Starts recording PLAY packets read from both connections of player (before filters),
+// previous capture is stopped. Can be called from LoginHandler to capture whole session.
+// Safe to call from any goroutine.
+func (handler *Handler) StartCapture() (path string, err error) {
+	if err = os.MkdirAll(CaptureDir, 0755); err != nil {
+		return
+	}
+	now := time.Now()
+	path = filepath.Join(CaptureDir, fmt.Sprintf("%s_%s.cap", handler.Nickname, now.Format("20060102-150405.000")))
+	f, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	w, err := capture.NewWriter(&limitedFile{File: f, left: CaptureMaxSize}, capture.Header{
+		Start:    now,
+		Protocol: packets.ProtocolVersion,
+		Nickname: handler.Nickname,
+		UUID:     handler.UUID,
+	})
+	if err != nil {
+		f.Close()
+		return
+	}
+
+	// current state of connections, later changes are recorded when they happen
+	w.State(handler.State())
+	w.Compression(packets.ServerBound, normalizeThreshold(handler.CompressThreshold))
+	if conn, ok := handler.UpstreamC.(net.Conn); ok {
+		w.Upstream(handler.UpstreamName, conn.RemoteAddr().String())
+		w.Compression(packets.ClientBound, handler.upstream_threshold)
+	}
+	if err = w.Flush(); err != nil {
+		w.Close()
+		return
+	}
+
+	if old := handler.swapCapture(w); old != nil {
+		old.Close()
+	}
+	handler.Log().WithField("path", path).Info("Session capture started")
+	return path, nil
+}
+
+// Stops capture started with StartCapture, returns false if player wasn't captured
+func (handler *Handler) StopCapture() bool {
+	w := handler.swapCapture(nil)
+	if w == nil {
+		return false
+	}
+	if err := w.Close(); err != nil {
+		handler.Log().WithError(err).Error("Session capture close error")
+	}
+	handler.Log().Info("Session capture stopped")
+	return true
+}
+
+func (handler *Handler) captureWriter() *capture.Writer {
+	w, _ := handler.capture.Load().(*capture.Writer)
+	return w
+}
+
+func (handler *Handler) swapCapture(w *capture.Writer) (old *capture.Writer) {
+	handler.captureLock.Lock()
+	defer handler.captureLock.Unlock()
+	old = handler.captureWriter()
+	handler.capture.Store(w)
+	return
+}
+
+// stops capture after write error, player connection isn't affected
+func (handler *Handler) checkCapture(w *capture.Writer, err error) {
+	if err == nil {
+		return
+	}
+	handler.Log().WithError(err).Error("Session capture error, stopping capture")
+	handler.captureLock.Lock()
+	if handler.captureWriter() == w { // not replaced by new capture in meantime
+		handler.capture.Store((*capture.Writer)(nil))
+	}
+	handler.captureLock.Unlock()
+	w.Close()
+}
+
+// Reads whole packet data from payload and records it. Returned data is valid until packet is released:
+// streamed packets can't be forwarded after their payload was read, so they are replaced by uncompressed copy.
+func (handler *Handler) capturePacket(w *capture.Writer, direction packets.Direction, raw *packets.RawPacket, payload io.Reader) (data []byte, err error) {
+	var buf bytes.Buffer
+	packets.WriteVarInt(&buf, raw.ID)
+	id_size := buf.Len()
+	if _, err = buf.ReadFrom(payload); err != nil {
+		return
+	}
+	handler.checkCapture(w, w.Packet(direction, raw.ID, buf.Bytes()[id_size:]))
+
+	if raw.Streamed() {
+		packets.BufferPool.Put(raw.Payload)
+		copied := packets.BufferPool.Get(buf.Len())
+		copy(copied, buf.Bytes())
+		*raw = packets.NewRawPacket(raw.ID, copied)
+	}
+	return buf.Bytes()[id_size:], nil
+}
+
+// returns error after left bytes, so captures can't fill disk
+type limitedFile struct {
+	*os.File
+	left int64
+}
+
+func (f *limitedFile) Write(p []byte) (int, error) {
+	if int64(len(p)) > f.left {
+		return 0, errCaptureTooLarge
+	}
+	f.left -= int64(len(p))
+	return f.File.Write(p)
+}
@@ -0,0 +1,308 @@
+// Package capture implements binary recording of proxied sessions, used to reproduce problems of single player
+// with cmd/potoq-replay.
+//
+// File starts with header:
+//
+//	"POTOQCAP" version:uint8 start:int64(unix nanoseconds) protocol:VarInt nickname:String uuid:[16]byte
+//
+// followed by records, each starting with kind:uint8 and time since start:VarLong (microseconds):
+//
+//	KindPacket       direction:uint8 id:VarInt length:VarInt data   (uncompressed data after packet id)
+//	KindState        state:VarInt
+//	KindCompression  direction:uint8 threshold:VarInt               (threshold of connection packets are read from)
+//	KindUpstream     name:String addr:String
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Craftserve/potoq/packets"
+	"github.com/google/uuid"
+)
+
+const magic = "POTOQCAP"
+const version = 1
+
+// Packets with more data are rejected by Reader, it's more than any valid packet can have
+const MaxDataLength = 8 * 1024 * 1024
+
+type Kind uint8
+
+const (
+	KindPacket      Kind = 1
+	KindState       Kind = 2
+	KindCompression Kind = 3
+	KindUpstream    Kind = 4
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case KindPacket:
+		return "packet"
+	case KindState:
+		return "state"
+	case KindCompression:
+		return "compression"
+	case KindUpstream:
+		return "upstream"
+	default:
+		return fmt.Sprintf("kind(%d)", uint8(kind))
+	}
+}
+
+type Header struct {
+	Start    time.Time
+	Protocol packets.VarInt
+	Nickname string
+	UUID     uuid.UUID
+}
+
+// Single record, fields not used by Kind are zero
+type Record struct {
+	Kind      Kind
+	Time      time.Duration // since Header.Start
+	Direction packets.Direction
+	ID        packets.VarInt
+	Data      []byte            // packet data after id
+	State     packets.ConnState // state of player connection, set by Reader for all records
+	Threshold int               // compression threshold, negative if disabled
+	Name      string            // upstream name
+	Addr      string            // upstream address
+}
+
+// Parses packet with packets.NewPacket, unknown packets are returned as RawPacket with error.
+// Data left after parsing is error too, so parser bugs are visible.
+func (rec *Record) Parse() (packets.Packet, error) {
+	packet := packets.NewPacket(rec.ID, rec.State, rec.Direction)
+	if packet == nil {
+		return rec.Raw(), fmt.Errorf("unknown packet %s %s_%02X", rec.State, rec.Direction, rec.ID)
+	}
+	reader := bytes.NewReader(rec.Data)
+	if err := packet.Parse(reader); err != nil {
+		return packet, fmt.Errorf("parse error at offset %d of %d: %w", len(rec.Data)-reader.Len(), len(rec.Data), err)
+	}
+	if reader.Len() > 0 {
+		return packet, fmt.Errorf("%d of %d bytes left after parsing", reader.Len(), len(rec.Data))
+	}
+	return packet, nil
+}
+
+// Returns packet as uncompressed RawPacket, which can be written with any PacketWriter
+func (rec *Record) Raw() packets.RawPacket {
+	var buf bytes.Buffer
+	packets.WriteVarInt(&buf, rec.ID)
+	buf.Write(rec.Data)
+	return packets.NewRawPacket(rec.ID, buf.Bytes())
+}
+
+// > Writer
+
+// Writer is safe for concurrent use, records are buffered until Flush or Close
+type Writer struct {
+	mu     sync.Mutex
+	out    *bufio.Writer
+	closer io.Closer // nil if output isn't io.Closer
+	start  time.Time
+	err    error // first write error, returned by all later calls
+}
+
+func NewWriter(out io.Writer, header Header) (*Writer, error) {
+	w := &Writer{out: bufio.NewWriterSize(out, 64*1024), start: header.Start}
+	w.closer, _ = out.(io.Closer)
+	w.out.WriteString(magic)
+	w.out.WriteByte(version)
+	packets.WriteLong(w.out, header.Start.UnixNano())
+	packets.WriteVarInt(w.out, header.Protocol)
+	packets.WriteMinecraftString(w.out, header.Nickname)
+	w.out.Write(header.UUID[:])
+	if err := w.out.Flush(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// writes record head and body under lock
+func (w *Writer) record(kind Kind, body func(out *bufio.Writer)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	w.out.WriteByte(byte(kind))
+	packets.WriteVarLong(w.out, packets.VarLong(time.Since(w.start)/time.Microsecond))
+	body(w.out)
+	// bufio.Writer keeps first error
+	if _, err := w.out.Write(nil); err != nil {
+		w.err = err
+	}
+	return w.err
+}
+
+func (w *Writer) Packet(direction packets.Direction, id packets.VarInt, data []byte) error {
+	return w.record(KindPacket, func(out *bufio.Writer) {
+		out.WriteByte(byte(direction))
+		packets.WriteVarInt(out, id)
+		packets.WriteVarInt(out, packets.VarInt(len(data)))
+		out.Write(data)
+	})
+}
+
+func (w *Writer) State(state packets.ConnState) error {
+	return w.record(KindState, func(out *bufio.Writer) {
+		packets.WriteVarInt(out, packets.VarInt(state))
+	})
+}
+
+func (w *Writer) Compression(direction packets.Direction, threshold int) error {
+	return w.record(KindCompression, func(out *bufio.Writer) {
+		out.WriteByte(byte(direction))
+		packets.WriteVarInt(out, packets.VarInt(threshold))
+	})
+}
+
+func (w *Writer) Upstream(name, addr string) error {
+	return w.record(KindUpstream, func(out *bufio.Writer) {
+		packets.WriteMinecraftString(out, name)
+		packets.WriteMinecraftString(out, addr)
+	})
+}
+
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = w.out.Flush()
+	}
+	return w.err
+}
+
+// Flushes records and closes output if it's io.Closer
+func (w *Writer) Close() error {
+	err := w.Flush()
+	if w.closer != nil {
+		if cerr := w.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// > Reader
+
+var ErrInvalidHeader = errors.New("not a potoq capture file")
+
+type Reader struct {
+	Header Header
+	in     *bufio.Reader
+	state  packets.ConnState
+}
+
+func NewReader(in io.Reader) (*Reader, error) {
+	r := &Reader{in: bufio.NewReader(in)}
+	head := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(r.in, head); err != nil {
+		return nil, err
+	}
+	if string(head[:len(magic)]) != magic {
+		return nil, ErrInvalidHeader
+	}
+	if head[len(magic)] != version {
+		return nil, fmt.Errorf("unsupported capture version %d", head[len(magic)])
+	}
+	start, err := packets.ReadLong(r.in)
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Start = time.Unix(0, start)
+	if r.Header.Protocol, err = packets.ReadVarInt(r.in); err != nil {
+		return nil, err
+	}
+	if r.Header.Nickname, err = packets.ReadMinecraftString(r.in, 64); err != nil {
+		return nil, err
+	}
+	if _, err = io.ReadFull(r.in, r.Header.UUID[:]); err != nil {
+		return nil, err
+	}
+	r.state = packets.PLAY
+	return r, nil
+}
+
+// Returns next record, io.EOF at end of capture. Captures are usually started in PLAY state,
+// so records before first KindState have State PLAY.
+func (r *Reader) Next() (rec *Record, err error) {
+	kind, err := r.in.ReadByte()
+	if err != nil {
+		return nil, err // io.EOF only here, at record boundary
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	rec = &Record{Kind: Kind(kind)}
+	t, err := packets.ReadVarLong(r.in)
+	if err != nil {
+		return nil, err
+	}
+	rec.Time = time.Duration(t) * time.Microsecond
+
+	switch rec.Kind {
+	case KindPacket:
+		var length packets.VarInt
+		if err = r.readDirection(rec); err != nil {
+			return
+		}
+		if rec.ID, err = packets.ReadVarInt(r.in); err != nil {
+			return
+		}
+		if length, err = packets.ReadVarInt(r.in); err != nil {
+			return
+		}
+		if length < 0 || length > MaxDataLength {
+			return nil, fmt.Errorf("invalid packet length %d", length)
+		}
+		rec.Data = make([]byte, length)
+		_, err = io.ReadFull(r.in, rec.Data)
+	case KindState:
+		var state packets.VarInt
+		state, err = packets.ReadVarInt(r.in)
+		r.state = packets.ConnState(state)
+	case KindCompression:
+		var threshold packets.VarInt
+		if err = r.readDirection(rec); err != nil {
+			return
+		}
+		threshold, err = packets.ReadVarInt(r.in)
+		rec.Threshold = int(threshold)
+	case KindUpstream:
+		if rec.Name, err = packets.ReadMinecraftString(r.in, 256); err != nil {
+			return
+		}
+		rec.Addr, err = packets.ReadMinecraftString(r.in, 256)
+	default:
+		return nil, fmt.Errorf("unknown record kind %d", kind)
+	}
+	rec.State = r.state
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (r *Reader) readDirection(rec *Record) error {
+	b, err := r.in.ReadByte()
+	if err != nil {
+		return err
+	}
+	rec.Direction = packets.Direction(b)
+	if rec.Direction != packets.ServerBound && rec.Direction != packets.ClientBound {
+		return fmt.Errorf("invalid direction %d", b)
+	}
+	return nil
+}
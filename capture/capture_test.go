@@ -0,0 +1,90 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Craftserve/potoq/packets"
+	"github.com/google/uuid"
+)
+
+func TestCaptureRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	header := Header{Start: time.Unix(1600000000, 123), Protocol: packets.ProtocolVersion, Nickname: "tester", UUID: uuid.New()}
+	w, err := NewWriter(&buf, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chat bytes.Buffer
+	(&packets.ChatMessagePacketSB{Message: "hello"}).Serialize(&chat)
+	w.State(packets.LOGIN)
+	w.Compression(packets.ServerBound, 256)
+	w.State(packets.PLAY)
+	w.Upstream("lobby", "127.0.0.1:25566")
+	w.Packet(packets.ServerBound, 0x03, chat.Bytes())
+	w.Packet(packets.ServerBound, 0x03, append(chat.Bytes(), 0xFF))
+	w.Packet(packets.ServerBound, 0x03, chat.Bytes()[:3])
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Header != header {
+		t.Fatalf("header: got %+v, want %+v", r.Header, header)
+	}
+	var records []*Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 7 {
+		t.Fatalf("%d records", len(records))
+	}
+	if rec := records[1]; rec.Kind != KindCompression || rec.State != packets.LOGIN || rec.Threshold != 256 {
+		t.Fatalf("compression record: %+v", rec)
+	}
+	if rec := records[3]; rec.Name != "lobby" || rec.Addr != "127.0.0.1:25566" || rec.State != packets.PLAY {
+		t.Fatalf("upstream record: %+v", rec)
+	}
+
+	packet, err := records[4].Parse()
+	if err != nil || packet.(*packets.ChatMessagePacketSB).Message != "hello" {
+		t.Fatalf("parsed %#v: %v", packet, err)
+	}
+	if _, err := records[5].Parse(); err == nil || !strings.Contains(err.Error(), "1 of 7 bytes left") {
+		t.Fatalf("trailing data: %v", err)
+	}
+	if _, err := records[6].Parse(); err == nil || !strings.Contains(err.Error(), "offset 3 of 3") {
+		t.Fatalf("truncated packet: %v", err)
+	}
+}
+
+func TestCaptureTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, Header{Start: time.Now()})
+	w.Packet(packets.ClientBound, 0x1F, make([]byte, 8))
+	w.Close()
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-4]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Next(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("got %v, want io.ErrUnexpectedEOF", err)
+	}
+	if _, err := NewReader(strings.NewReader("POTOQXXX\x01")); err != ErrInvalidHeader {
+		t.Fatalf("got %v, want ErrInvalidHeader", err)
+	}
+}
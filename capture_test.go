@@ -0,0 +1,94 @@
+package potoq
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Craftserve/potoq/capture"
+	"github.com/Craftserve/potoq/packets"
+	"gopkg.in/tomb.v1"
+)
+
+func TestSessionCapture(t *testing.T) {
+	dir, err := ioutil.TempDir("", "capture")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	CaptureDir = dir
+	defer func() { CaptureDir = "capture" }()
+
+	// streamed packet without filters, it must be forwarded after its payload was captured
+	big := make([]byte, packets.BufferedPacketSize*2)
+	for i := range big {
+		big[i] = byte(i * 7)
+	}
+	var stream bytes.Buffer
+	w := packets.NewPacketWriter(&stream, packets.CompressThreshold)
+	w.WritePacket(&packets.ChatMessagePacketSB{Message: "hello"}, false)
+	w.WritePacket((&capture.Record{ID: 0x2E, Data: big}).Raw(), false)
+	w.WritePacket(&packets.HeldItemChangePacketSB{Slot: 2}, true)
+
+	var out bytes.Buffer
+	handler := &Handler{
+		Nickname:  "tester",
+		UpstreamW: packets.NewPacketWriter(&out, packets.NoCompression),
+	}
+	handler.setState(packets.PLAY)
+	handler.AddReadOnlyPacketFilter(&packets.ChatMessagePacketSB{}, func(*Handler, packets.Packet) error { return nil })
+	path, err := handler.StartCapture()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.pump(packets.NewPacketReader(&stream, packets.CompressThreshold), packets.ServerBound, &tomb.Tomb{})
+	handler.UpstreamW.Flush()
+	if !handler.StopCapture() {
+		t.Fatal("capture not running")
+	}
+
+	// forwarded packets
+	reader := packets.NewPacketReader(&out, packets.NoCompression)
+	for _, want := range []packets.VarInt{0x03, 0x2E, 0x25} {
+		raw, err := reader.ReadPacket()
+		if err != nil || raw.ID != want {
+			t.Fatalf("forwarded packet %02X, want %02X: %v", raw.ID, want, err)
+		}
+		payload, _ := reader.Payload()
+		data, _ := ioutil.ReadAll(payload)
+		if want == 0x2E && !bytes.Equal(data, big) {
+			t.Fatalf("streamed packet changed, %d bytes", len(data))
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := capture.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var captured []*capture.Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Kind == capture.KindPacket {
+			captured = append(captured, rec)
+		}
+	}
+	if len(captured) != 3 || captured[0].ID != 0x03 || !bytes.Equal(captured[1].Data, big) || captured[2].ID != 0x25 {
+		t.Fatalf("captured %d packets", len(captured))
+	}
+	if packet, err := captured[0].Parse(); err != nil || packet.(*packets.ChatMessagePacketSB).Message != "hello" {
+		t.Fatalf("captured %#v: %v", packet, err)
+	}
+}
@@ -0,0 +1,348 @@
+// Command potoq-replay decodes session captures recorded with Handler.StartCapture and replays them.
+//
+//	potoq-replay print [-dir sb|cb] [-errors] file.cap
+//	potoq-replay inspect [-all] file.cap
+//	potoq-replay replay [-addr localhost:25565] [-speed 1] file.cap
+//
+// print parses every packet with packets.NewPacket and prints it as JSON, parse errors are printed with offset
+// of failure. inspect shows offsets of fields of packets which can't be parsed (eg. dumps from DumpDir) and
+// hex dump of their data. replay logs in to addr as captured player (offline mode, with bungeecord forwarding for upstreams)
+// and sends captured serverbound PLAY packets with original timing, clientbound packets are parsed, so both
+// proxy and parser errors can be reproduced locally. Keep alives are answered live instead of replayed.
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Craftserve/potoq/capture"
+	"github.com/Craftserve/potoq/packets"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "print":
+		err = printCmd(os.Args[2:])
+	case "inspect":
+		err = inspectCmd(os.Args[2:])
+	case "replay":
+		err = replayCmd(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	log.Fatal("usage: potoq-replay print [-dir sb|cb] [-errors] file.cap\n" +
+		"       potoq-replay inspect [-all] file.cap\n" +
+		"       potoq-replay replay [-addr host:port] [-speed 1] [-wait 5s] file.cap")
+}
+
+func openCapture(flags *flag.FlagSet) (*capture.Reader, io.Closer, error) {
+	if flags.NArg() != 1 {
+		usage()
+	}
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := capture.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return r, f, nil
+}
+
+// > print
+
+func printCmd(args []string) error {
+	flags := flag.NewFlagSet("print", flag.ExitOnError)
+	dir := flags.String("dir", "", "print only packets of direction: sb or cb")
+	only_errors := flags.Bool("errors", false, "print only packets which can't be parsed")
+	flags.Parse(args)
+	r, f, err := openCapture(flags)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Printf("# %s %s protocol %d, started %s\n", r.Header.Nickname, r.Header.UUID, r.Header.Protocol, r.Header.Start.Format(time.RFC3339Nano))
+	var count, failed int
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("after %d packets: %w", count, err)
+		}
+		if rec.Kind != capture.KindPacket {
+			if !*only_errors {
+				printRecord(rec)
+			}
+			continue
+		}
+		count++
+		if *dir != "" && !strings.EqualFold(*dir, rec.Direction.String()) {
+			continue
+		}
+		packet, perr := rec.Parse()
+		if perr != nil {
+			failed++
+		} else if *only_errors {
+			continue
+		}
+		printPacket(rec, packet, perr)
+	}
+	fmt.Printf("# %d packets, %d parse errors\n", count, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func printRecord(rec *capture.Record) {
+	switch rec.Kind {
+	case capture.KindState:
+		fmt.Printf("%10.3f state %s\n", rec.Time.Seconds(), rec.State)
+	case capture.KindCompression:
+		fmt.Printf("%10.3f compression %s %d\n", rec.Time.Seconds(), rec.Direction, rec.Threshold)
+	case capture.KindUpstream:
+		fmt.Printf("%10.3f upstream %s %s\n", rec.Time.Seconds(), rec.Name, rec.Addr)
+	}
+}
+
+func printPacket(rec *capture.Record, packet packets.Packet, perr error) {
+	fmt.Printf("%10.3f %s %d bytes\n", rec.Time.Seconds(), packets.ToString(packet, rec.Direction), len(rec.Data))
+	if perr != nil {
+		fmt.Printf("%10s ERROR %s\n%10s data %X\n", "", perr, "", rec.Data)
+	}
+}
+
+// > inspect
+
+func inspectCmd(args []string) error {
+	flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	all := flags.Bool("all", false, "inspect all packets, not only ones which can't be parsed")
+	flags.Parse(args)
+	r, f, err := openCapture(flags)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Printf("# %s %s protocol %d (proxy protocol %d), recorded %s\n", r.Header.Nickname, r.Header.UUID,
+		r.Header.Protocol, packets.ProtocolVersion, r.Header.Start.Format(time.RFC3339Nano))
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if rec.Kind != capture.KindPacket {
+			printRecord(rec)
+			continue
+		}
+		packet, perr := rec.Parse()
+		if perr != nil || *all {
+			printPacket(rec, packet, perr)
+			inspectPacket(rec)
+		}
+	}
+}
+
+// prints where fields start according to reflection codec, and hex dump of data
+func inspectPacket(rec *capture.Record) {
+	packet := packets.NewPacket(rec.ID, rec.State, rec.Direction)
+	if packet != nil {
+		offsets, err := packets.ParseFieldOffsets(packet, rec.Data)
+		fmt.Printf("%10s fields of %T:\n", "", packet)
+		for i, field := range offsets {
+			end := len(rec.Data)
+			if i+1 < len(offsets) {
+				end = offsets[i+1].Offset
+			}
+			value := fmt.Sprintf("%X", rec.Data[field.Offset:end])
+			if len(value) > 64 {
+				value = value[:64] + "..."
+			}
+			fmt.Printf("%10s 0x%04X %-24s %s\n", "", field.Offset, field.Name, value)
+		}
+		if err != nil && len(offsets) > 0 {
+			last := offsets[len(offsets)-1]
+			fmt.Printf("%10s failed in field %s at offset 0x%04X: %s\n", "", last.Name, last.Offset, err)
+		} else if err != nil {
+			fmt.Printf("%10s failed: %s\n", "", err)
+		}
+	}
+	fmt.Print(hex.Dump(rec.Data))
+}
+
+// > replay
+
+func replayCmd(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:25565", "server or proxy to replay serverbound packets to")
+	speed := flags.Float64("speed", 1, "replay speed multiplier, 0 sends packets without delays")
+	wait := flags.Duration("wait", 5*time.Second, "time to wait for server response after last packet")
+	forward_ip := flags.String("ip", "127.0.0.1", "player address sent with bungeecord forwarding")
+	flags.Parse(args)
+	r, f, err := openCapture(flags)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	conn, err := net.Dial("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	reader, writer, err := login(conn, r.Header, *forward_ip)
+	if err != nil {
+		return err
+	}
+
+	var lock sync.Mutex // writer is shared with keep alive responses
+	done := make(chan error, 1)
+	go func() { done <- readServer(reader, writer, &lock) }()
+
+	var first time.Duration = -1
+	start := time.Now()
+	var sent int
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if rec.Kind != capture.KindPacket || rec.Direction != packets.ServerBound || rec.State != packets.PLAY ||
+			rec.ID == (&packets.KeepAlivePacketSB{}).PacketID() {
+			continue
+		}
+		if first < 0 {
+			first = rec.Time
+		}
+		if *speed > 0 {
+			time.Sleep(time.Duration(float64(rec.Time-first)/(*speed)) - time.Since(start))
+		}
+
+		lock.Lock()
+		err = writer.WritePacket(rec.Raw(), true)
+		lock.Unlock()
+		if err != nil {
+			return fmt.Errorf("write of packet %d (%s_%02X): %w", sent, rec.Direction, rec.ID, err)
+		}
+		sent++
+		select {
+		case err := <-done:
+			return fmt.Errorf("connection closed after %d packets: %v", sent, err)
+		default:
+		}
+	}
+
+	log.Printf("sent %d packets, waiting %s", sent, *wait)
+	select {
+	case err := <-done:
+		return fmt.Errorf("connection closed: %v", err)
+	case <-time.After(*wait):
+	}
+	return nil
+}
+
+// logs in like proxy does it with upstream, see Handler.connectUpstream
+func login(conn net.Conn, header capture.Header, forward_ip string) (packets.PacketReader, packets.PacketWriter, error) {
+	host, port, _ := net.SplitHostPort(conn.RemoteAddr().String())
+	var port_num int
+	fmt.Sscan(port, &port_num)
+	w := packets.NewPacketWriter(conn, packets.NoCompression)
+	handshake := &packets.HandshakePacket{
+		Protocol:  header.Protocol,
+		Host:      host + "\x00" + forward_ip + "\x00" + header.UUID.String(),
+		Port:      int16(port_num),
+		NextState: packets.VarInt(packets.LOGIN),
+	}
+	if err := w.WritePacket(handshake, false); err != nil {
+		return nil, nil, err
+	}
+	if err := w.WritePacket(&packets.LoginStartPacket{Nickname: header.Nickname}, true); err != nil {
+		return nil, nil, err
+	}
+
+	r := packets.NewPacketReader(conn, packets.NoCompression)
+	var success packets.LoginSuccessPacket
+	for {
+		packet, err := packets.ParsePackets(r, &packets.LoginCompressionPacket{}, &packets.LoginKickPacket{}, &success)
+		if err != nil {
+			return nil, nil, fmt.Errorf("login: %w", err)
+		}
+		switch p := packet.(type) {
+		case *packets.LoginCompressionPacket:
+			log.Printf("compression threshold %d", p.Threshold)
+			r = packets.NewPacketReader(bufio.NewReader(conn), int(p.Threshold))
+			w = packets.NewPacketWriter(conn, int(p.Threshold))
+		case *packets.LoginKickPacket:
+			return nil, nil, fmt.Errorf("login kick: %s", p.Message)
+		case *packets.LoginSuccessPacket:
+			log.Printf("logged in as %s %s", p.Username, p.UID)
+			return r, w, nil
+		}
+	}
+}
+
+// parses clientbound packets and answers keep alives until connection ends
+func readServer(r packets.PacketReader, w packets.PacketWriter, lock *sync.Mutex) error {
+	for {
+		raw, err := r.ReadPacket()
+		if err != nil {
+			return err
+		}
+		payload, err := r.Payload()
+		if err != nil {
+			return err
+		}
+		rec := &capture.Record{Kind: capture.KindPacket, Direction: packets.ClientBound, State: packets.PLAY, ID: raw.ID}
+		if rec.Data, err = ioutil.ReadAll(payload); err != nil {
+			return err
+		}
+		packet, perr := rec.Parse()
+		if perr != nil {
+			printPacket(rec, packet, perr)
+			continue
+		}
+		switch p := packet.(type) {
+		case *packets.KeepAlivePacketCB:
+			lock.Lock()
+			err = w.WritePacket(&packets.KeepAlivePacketSB{ID: p.ID}, true)
+			lock.Unlock()
+			if err != nil {
+				return err
+			}
+		case *packets.KickPacketCB:
+			return fmt.Errorf("kicked: %s", p.Message)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Command potoq-testserver runs stand-in Minecraft server (see package testserver), which can be used
+// as upstream of proxy instead of Java server:
+//
+//	potoq-testserver [-addr localhost:25566] [-compression 256] [-forwarding] [-keepalive 15s] [-kick-after 1m -kick-message text]
+//
+// Players spawn in void world, their chat is echoed back and "/kick [reason]" kicks them.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+
+	"github.com/Craftserve/potoq/testserver"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:25566", "address to listen on")
+	compression := flag.Int("compression", 256, "compression threshold, negative disables compression, 0 compresses all packets")
+	forwarding := flag.Bool("forwarding", false, "require bungeecord forwarding data in handshake")
+	keepalive := flag.Duration("keepalive", 0, "keep alive interval, 0 means 15s")
+	kick_after := flag.Duration("kick-after", 0, "kick players after this time, 0 disables")
+	kick_message := flag.String("kick-message", "Kicked by test server", "message of -kick-after kick")
+	flag.Parse()
+
+	server := &testserver.Server{
+		Threshold:         *compression,
+		RequireForwarding: *forwarding,
+		KeepAliveInterval: *keepalive,
+		KickAfter:         *kick_after,
+		KickMessage:       *kick_message,
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("listening on %s", listener.Addr())
+	log.Fatal(server.Serve(listener))
+}
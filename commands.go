@@ -3,6 +3,7 @@ package potoq
 import (
 	"fmt"
 	"io"
+	"runtime/debug"
 	"time"
 
 	"github.com/Craftserve/potoq/packets"
@@ -16,11 +17,31 @@ type HandlerCommand interface {
 
 type HandlerCommandChan chan HandlerCommand
 
+// runs command in handler goroutine, panics are returned as *PanicError
+func (handler *Handler) executeCommand(cmd HandlerCommand) (err error) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		perr := &PanicError{Value: v, Stack: debug.Stack(), Command: cmd}
+		handler.Log().WithFields(logrus.Fields{
+			"command": fmt.Sprintf("%T", cmd),
+			"panic":   v,
+			"stack":   string(perr.Stack),
+		}).Error("Handler command panic")
+		err = perr
+	}()
+	return cmd.Execute(handler)
+}
+
 // >> ReconnectCommand -> causes handler to reconnect to another upstream and fake client world change
 
 type ReconnectCommand struct {
 	Name string
 	Addr string
+	// expected compression threshold of upstream (negative if disabled), nil accepts any
+	CompressThreshold *int
 }
 
 func (cmd *ReconnectCommand) Execute(handler *Handler) (err error) {
@@ -33,13 +54,15 @@ func (cmd *ReconnectCommand) Execute(handler *Handler) (err error) {
 		handler.UpstreamC = nil
 		handler.UpstreamW = nil
 	}
+	// per-handler filters are bound to upstream session
+	handler.ClearPacketFilters()
 
 	// handler.upstream_tomb.Wait() // wait for read_packets end
 	// if handler.ClientSettings == nil { // ???
 	// return fmt.Errorf("Reconnect error: ClientSettings is nil! %#v", handler.ClientSettings)
 	// }
 
-	err = handler.connectUpstream(cmd.Name, cmd.Addr)
+	upstream_r, err := handler.connectUpstream(cmd)
 	if err != nil { // TODO: brzydkie bledy beda jak sektor pelny chyba
 		handler.Log().
 			WithField("name", cmd.Name).
@@ -50,26 +73,21 @@ func (cmd *ReconnectCommand) Execute(handler *Handler) (err error) {
 		return
 	}
 
-	// hijack Join Game packet
-	var p packets.Packet
-	select {
-	case <-handler.UpstreamTomb.Dead():
-		return handler.UpstreamTomb.Err()
-	case p = <-handler.UpstreamPackets:
-	}
-
-	join, ok := p.(*packets.JoinGamePacketCB)
-	if !ok {
-		err = fmt.Errorf("Packet other than JoinGamePacketCB while reconnecting upstream: %#v", p)
+	// hijack Join Game packet, upstream pump is started after it
+	join := &packets.JoinGamePacketCB{}
+	p, err := packets.ParsePackets(upstream_r, join)
+	if err != nil {
+		err = fmt.Errorf("Packet other than JoinGamePacketCB while reconnecting upstream: %#v: %w", p, err)
 		return
 	}
+	handler.startUpstreamPump(upstream_r)
 	handler.Log().WithFields(logrus.Fields{
 		"join": join,
 	}).Debug("Reconnect Join")
 
 	// used for scoreboard
 	if derr := handler.dispatchPacket(join); derr != nil {
-		return fmt.Errorf("Error in JoinGamePacketCB packet hook: %s", err)
+		return fmt.Errorf("Error in JoinGamePacketCB packet hook: %w", derr)
 	}
 
 	// packets.WritePacket(handler.Upstream, handler.ClientSettings, true, handler.compress_threshold)
@@ -206,14 +224,12 @@ func (cmd *injectPacketCommand) Execute(handler *Handler) (err error) {
 	if cmd.Payload == nil {
 		panic("Nil payload!")
 	}
+	tracer := handler.tracer()
 	for _, packet := range cmd.Payload {
-		if handler.PacketTrace != nil {
-			_, err = io.WriteString(handler.PacketTrace, "inject: "+packets.ToString(packet, cmd.Direction)+"\n")
-			if err != nil {
-				return
-			}
+		if tracer != nil && tracer.match(cmd.Direction, packet.PacketID(), true) {
+			handler.tracePacket(tracer, packet, cmd.Direction, traceRecord{Injected: true})
 		}
-		w.WritePacket(packet, false)
+		err = w.WritePacket(packet, false)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,94 @@
+package potoq
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Craftserve/potoq/capture"
+	"github.com/Craftserve/potoq/packets"
+)
+
+// > dumps of packets which can't be parsed
+
+// Packets which can't be parsed are dumped to DumpDir as single packet captures (see package capture),
+// they can be inspected with "potoq-replay inspect". Empty DumpDir disables dumps.
+var DumpDir = "dumps"
+
+// Oldest dumps are removed when there are more than DumpMaxFiles of them or they take more than DumpMaxSize bytes
+var DumpMaxFiles = 1000
+var DumpMaxSize int64 = 256 * 1024 * 1024
+
+var dumpLock sync.Mutex // pumps of all handlers dump to one directory
+
+// Writes dump and returns its path. Data is uncompressed packet data after id, nil if it isn't available.
+func (handler *Handler) dumpPacket(direction packets.Direction, id packets.VarInt, data []byte) (string, error) {
+	if DumpDir == "" {
+		return "", fmt.Errorf("dumps disabled")
+	}
+	dumpLock.Lock()
+	defer dumpLock.Unlock()
+	if err := os.MkdirAll(DumpDir, 0755); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	path := filepath.Join(DumpDir, fmt.Sprintf("%s_%s_%02X_%s.cap", handler.Nickname, direction, id, now.Format("20060102-150405.000000")))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	w, err := capture.NewWriter(f, capture.Header{
+		Start:    now,
+		Protocol: packets.ProtocolVersion,
+		Nickname: handler.Nickname,
+		UUID:     handler.UUID,
+	})
+	if err != nil {
+		f.Close()
+		return "", err
+	}
+	w.State(handler.State())
+	if direction == packets.ClientBound {
+		w.Upstream(handler.UpstreamName, "")
+		w.Compression(direction, handler.upstream_threshold)
+	} else {
+		w.Compression(direction, normalizeThreshold(handler.CompressThreshold))
+	}
+	w.Packet(direction, id, data)
+	if err = w.Close(); err != nil {
+		return path, err
+	}
+	return path, pruneDumps()
+}
+
+// removes oldest dumps over DumpMaxFiles and DumpMaxSize
+func pruneDumps() error {
+	infos, err := ioutil.ReadDir(DumpDir)
+	if err != nil {
+		return err
+	}
+	var dumps []os.FileInfo
+	for _, info := range infos {
+		if info.Mode().IsRegular() && strings.HasSuffix(info.Name(), ".cap") {
+			dumps = append(dumps, info)
+		}
+	}
+	sort.Slice(dumps, func(i, j int) bool { return dumps[i].ModTime().After(dumps[j].ModTime()) })
+
+	var size int64
+	for i, info := range dumps { // newest first
+		size += info.Size()
+		if i >= DumpMaxFiles || size > DumpMaxSize {
+			if err := os.Remove(filepath.Join(DumpDir, info.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
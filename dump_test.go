@@ -0,0 +1,71 @@
+package potoq
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Craftserve/potoq/capture"
+	"github.com/Craftserve/potoq/packets"
+	"gopkg.in/tomb.v1"
+)
+
+func TestParseFailureDump(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dumps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	DumpDir, DumpMaxFiles = dir, 2
+	defer func() { DumpDir, DumpMaxFiles = "dumps", 1000 }()
+
+	// string longer than max_length, padded to get compressed
+	bad := append([]byte{0xAC, 0x02}, bytes.Repeat([]byte("x"), packets.CompressThreshold)...)
+	for i := 0; i < 3; i++ {
+		var stream bytes.Buffer
+		w := packets.NewPacketWriter(&stream, packets.CompressThreshold)
+		w.WritePacket((&capture.Record{ID: 0x03, Data: bad}).Raw(), true)
+
+		handler := &Handler{Nickname: "tester", UpstreamW: packets.NewPacketWriter(ioutil.Discard, packets.NoCompression)}
+		handler.setState(packets.PLAY)
+		handler.AddReadOnlyPacketFilter(&packets.ChatMessagePacketSB{}, func(*Handler, packets.Packet) error { return nil })
+		tmb := &tomb.Tomb{}
+		handler.pump(packets.NewPacketReader(&stream, packets.CompressThreshold), packets.ServerBound, tmb)
+		if err := tmb.Err(); err == nil || !strings.Contains(err.Error(), "dump: "+dir) {
+			t.Fatalf("pump error: %v", err)
+		}
+	}
+
+	dumps, _ := filepath.Glob(filepath.Join(dir, "tester_SB_03_*.cap"))
+	if len(dumps) != 2 {
+		t.Fatalf("%d dumps after pruning: %v", len(dumps), dumps)
+	}
+	f, err := os.Open(dumps[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := capture.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		rec, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Kind != capture.KindPacket {
+			continue
+		}
+		if rec.State != packets.PLAY || rec.Direction != packets.ServerBound || !bytes.Equal(rec.Data, bad) {
+			t.Fatalf("dumped %s %s %d bytes", rec.State, rec.Direction, len(rec.Data))
+		}
+		if _, err := rec.Parse(); err == nil {
+			t.Fatal("dumped packet can be parsed")
+		}
+		break
+	}
+}
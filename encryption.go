@@ -13,7 +13,7 @@ import (
 	"github.com/Craftserve/potoq/packets"
 )
 
-func (handler *Handler) establishEncryptionAsServer() error {
+func (handler *Handler) establishEncryptionAsServer(threshold int) error {
 	auth := handler.Authenticator
 	// S->C Encryption Request
 	original_token := make([]byte, 4)
@@ -27,7 +27,7 @@ func (handler *Handler) establishEncryptionAsServer() error {
 		PublicKey: auth.PublicKey,
 		Token:     original_token,
 	}
-	err = handler.DownstreamW.WritePacket(keyrequest, true)
+	err = handler.sendStatePacket(packets.LOGIN, handler.DownstreamW, keyrequest, true)
 	if err != nil {
 		return err
 	}
@@ -38,6 +38,10 @@ func (handler *Handler) establishEncryptionAsServer() error {
 	if err != nil {
 		return err
 	}
+	err = handler.inspectStatePacket(packets.LOGIN, &keyresponse)
+	if err != nil {
+		return err
+	}
 
 	decrypted_token, err := rsa.DecryptPKCS1v15(rand.Reader, auth.ServerKey, keyresponse.Token)
 	if err != nil {
@@ -57,12 +61,12 @@ func (handler *Handler) establishEncryptionAsServer() error {
 	if err != nil {
 		return err
 	}
-	writer, err := packets.NewEncryptedWriter(handler.DownstreamC, symmetric_key)
+	writer, err := packets.NewEncryptedWriter(handler.downstream_out, symmetric_key)
 	if err != nil {
 		return err
 	}
-	handler.DownstreamR = packets.NewPacketReader(bufio.NewReaderSize(reader, 128*1024), packets.CompressThreshold)
-	handler.DownstreamW = packets.NewPacketWriter(bufio.NewWriterSize(writer, 128*1024), packets.CompressThreshold)
+	handler.DownstreamR = packets.NewPacketReaderSize(bufio.NewReaderSize(reader, 128*1024), threshold, MaxServerboundPacketSize)
+	handler.DownstreamW = packets.NewPacketWriter(bufio.NewWriterSize(writer, 128*1024), threshold)
 
 	// Verify player with session.minecraft.net
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -17,6 +17,7 @@ const BIND_ADDR = "0.0.0.0:25565"
 
 func main() {
 	go func() {
+		http.Handle("/potoq/trace", potoq.TraceHTTPHandler)
 		http.ListenAndServe("localhost:6060", nil)
 	}()
 
@@ -2,40 +2,198 @@ package potoq
 
 import (
 	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+
 	"github.com/Craftserve/potoq/packets"
 	"github.com/Craftserve/potoq/utils"
+	"github.com/sirupsen/logrus"
 )
 
 type PacketFilter func(handler *Handler, packet packets.Packet) error
 
 var ErrDropPacket = errors.New("ErrDropPacket") // can be returned from PacketFilter
 
-// first half of both arrays is CB, second SB, see RegisterPacketFilter()
-var packetFilters [packets.MaxPacketID * 2][]PacketFilter
-var packetsParsed = utils.NewBitarray(packets.MaxPacketID * 2)
+// Filter is disabled for all players after panicking this many times, 0 means never
+var FilterPanicLimit uint32 = 0
+
+// Message shown to player disconnected because of panic in filter or command
+var PanicKickMessage = "Internal proxy error, please reconnect"
+
+// both arrays are indexed by state, direction and packet id, see packetFilterIndex()
+var packetFilters [connStates * 2 * packets.MaxPacketID][]*filterEntry
+var packetsParsed = utils.NewBitarray(connStates * 2 * packets.MaxPacketID)
+
+const connStates = int(packets.PLAY) + 1
+
+type filterEntry struct {
+	callback PacketFilter
+	readOnly bool   // filter promises not to modify packet, see RegisterReadOnlyPacketFilter()
+	panics   uint32 // atomic
+	disabled uint32 // atomic, set after FilterPanicLimit panics
+}
+
+// Returned from dispatchPacket and command execution if filter or command panicked
+type PanicError struct {
+	Value   interface{}
+	Stack   []byte
+	Packet  packets.Packet // nil if panic was raised in HandlerCommand
+	Command HandlerCommand // nil if panic was raised in PacketFilter
+}
+
+func (e *PanicError) Error() string {
+	if e.Command != nil {
+		return fmt.Sprintf("panic in command %T: %v", e.Command, e.Value)
+	}
+	return fmt.Sprintf("panic in filter for %T: %v", e.Packet, e.Value)
+}
 
 func init() {
-	RegisterPacketFilter(&packets.ClientSettingsPacketSB{}, saveClientSettings)
-	RegisterPacketFilter(&packets.PluginMessagePacketSB{}, saveClientSettings)
-	RegisterPacketFilter(&packets.PlayerListItemPacketCB{}, savePlayerList)
+	RegisterReadOnlyPacketFilter(&packets.ClientSettingsPacketSB{}, saveClientSettings)
+	RegisterReadOnlyPacketFilter(&packets.PluginMessagePacketSB{}, saveClientSettings)
+	RegisterReadOnlyPacketFilter(&packets.PlayerListItemPacketCB{}, savePlayerList)
 }
 
+// Registers filter for PLAY state packets
 func RegisterPacketFilter(packet_type packets.Packet, callback PacketFilter) {
-	packet_id := packet_type.PacketID()
-	switch packet_type.Direction() {
-	case packets.ServerBound:
-		var i = packet_id
-		packetFilters[i] = append(packetFilters[i], callback)
-		packetsParsed.Set(int(i), true)
-	case packets.ClientBound:
-		var i = packet_id + 256
-		packetFilters[i] = append(packetFilters[i], callback)
-		packetsParsed.Set(int(i), true)
+	RegisterStatePacketFilter(packets.PLAY, packet_type, callback)
+}
+
+// Registers PLAY state filter which never modifies packet (it can still drop it or inject other packets).
+// Packets seen only by read-only filters are forwarded byte-for-byte, without serializing and compressing them again.
+func RegisterReadOnlyPacketFilter(packet_type packets.Packet, callback PacketFilter) {
+	registerPacketFilter(packets.PLAY, packet_type, &filterEntry{callback: callback, readOnly: true})
+}
+
+// Registers filter for packets of given connection state. Filters for HANDSHAKING, STATUS and LOGIN
+// see both packets exchanged with player and packets proxy exchanges with upstream while connecting to it
+// (handshake with forwarded address, LoginStartPacket, LoginCompressionPacket, LoginSuccessPacket...).
+// Packets which are consumed by proxy itself (eg. upstream's LoginSuccessPacket) or which proxy has to send
+// (LoginCompressionPacket and EncryptionRequestPacket sent to player) can't be dropped.
+func RegisterStatePacketFilter(state packets.ConnState, packet_type packets.Packet, callback PacketFilter) {
+	registerPacketFilter(state, packet_type, &filterEntry{callback: callback})
+}
+
+func registerPacketFilter(state packets.ConnState, packet_type packets.Packet, filter *filterEntry) {
+	i := packetFilterIndex(state, packet_type.Direction(), packet_type.PacketID())
+	packetFilters[i] = append(packetFilters[i], filter)
+	packetsParsed.Set(i, true)
+}
+
+func packetFilterIndex(state packets.ConnState, direction packets.Direction, packet_id packets.VarInt) int {
+	if state < packets.HANDSHAKING || state > packets.PLAY {
+		panic("invalid state")
+	}
+	switch direction {
+	case packets.ServerBound, packets.ClientBound:
+		return (int(state)*2+int(direction-1))*packets.MaxPacketID + int(packet_id)
 	default:
 		panic("invalid direction")
 	}
 }
 
+// runs single filter, panics are returned as *PanicError
+func (handler *Handler) runFilter(filter *filterEntry, packet packets.Packet) (err error) {
+	if atomic.LoadUint32(&filter.disabled) != 0 {
+		return nil
+	}
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		perr := &PanicError{Value: v, Stack: debug.Stack(), Packet: packet}
+		handler.Log().WithFields(logrus.Fields{
+			"packet": packets.ToString(packet, packet.Direction()),
+			"panic":  v,
+			"stack":  string(perr.Stack),
+		}).Error("Packet filter panic")
+
+		n := atomic.AddUint32(&filter.panics, 1)
+		if FilterPanicLimit > 0 && n >= FilterPanicLimit && atomic.CompareAndSwapUint32(&filter.disabled, 0, 1) {
+			handler.Log().WithFields(logrus.Fields{
+				"packet": fmt.Sprintf("%T", packet),
+				"panics": n,
+			}).Error("Packet filter disabled after repeated panics")
+		}
+		err = perr
+	}()
+	return filter.callback(handler, packet)
+}
+
+// > per-handler filters, see Handler.AddPacketFilter()
+
+// copy-on-write map, never modified after being stored in Handler.filters
+type handlerFilterSet map[int][]*filterEntry
+
+// Registers PLAY state filter dispatched only for this handler, after global ones. Safe to call from any goroutine.
+// Filters are removed automatically when player switches upstream or handler closes,
+// returned func can be used to remove filter earlier.
+func (handler *Handler) AddPacketFilter(packet_type packets.Packet, callback PacketFilter) (remove func()) {
+	return handler.AddStatePacketFilter(packets.PLAY, packet_type, callback)
+}
+
+// Same as AddPacketFilter, but filter can't modify packet, see RegisterReadOnlyPacketFilter()
+func (handler *Handler) AddReadOnlyPacketFilter(packet_type packets.Packet, callback PacketFilter) (remove func()) {
+	return handler.addPacketFilter(packets.PLAY, packet_type, &filterEntry{callback: callback, readOnly: true})
+}
+
+// Same as AddPacketFilter, but for packets of given connection state, see RegisterStatePacketFilter()
+func (handler *Handler) AddStatePacketFilter(state packets.ConnState, packet_type packets.Packet, callback PacketFilter) (remove func()) {
+	return handler.addPacketFilter(state, packet_type, &filterEntry{callback: callback})
+}
+
+func (handler *Handler) addPacketFilter(state packets.ConnState, packet_type packets.Packet, filter *filterEntry) (remove func()) {
+	i := packetFilterIndex(state, packet_type.Direction(), packet_type.PacketID())
+
+	handler.filtersLock.Lock()
+	defer handler.filtersLock.Unlock()
+	old := handler.handlerFilters()
+	set := make(handlerFilterSet, len(old)+1)
+	for k, v := range old {
+		set[k] = v
+	}
+	set[i] = append(old[i][:len(old[i]):len(old[i])], filter)
+	handler.filters.Store(set)
+
+	return func() { handler.removePacketFilter(i, filter) }
+}
+
+func (handler *Handler) removePacketFilter(i int, filter *filterEntry) {
+	handler.filtersLock.Lock()
+	defer handler.filtersLock.Unlock()
+	old := handler.handlerFilters()
+	set := make(handlerFilterSet, len(old))
+	for k, v := range old {
+		set[k] = v
+	}
+	var bucket []*filterEntry
+	for _, f := range old[i] {
+		if f != filter {
+			bucket = append(bucket, f)
+		}
+	}
+	if len(bucket) > 0 {
+		set[i] = bucket
+	} else {
+		delete(set, i)
+	}
+	handler.filters.Store(set)
+}
+
+// Removes all filters added with AddPacketFilter
+func (handler *Handler) ClearPacketFilters() {
+	handler.filtersLock.Lock()
+	handler.filters.Store(handlerFilterSet(nil))
+	handler.filtersLock.Unlock()
+}
+
+func (handler *Handler) handlerFilters() handlerFilterSet {
+	set, _ := handler.filters.Load().(handlerFilterSet)
+	return set
+}
+
 func saveClientSettings(handler *Handler, packet packets.Packet) error {
 	switch p := packet.(type) {
 	case *packets.ClientSettingsPacketSB:
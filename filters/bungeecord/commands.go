@@ -39,6 +39,10 @@ func ChatCommands(handler *potoq.Handler, packet packets.Packet) error {
 		resp = proxystatsCmd(handler, words[1:])
 	case "/greload":
 		resp = reloadupstreamsCmd(handler, words[1:])
+	case "/ptrace":
+		resp = ptraceCmd(handler, words[1:])
+	case "/pcapture":
+		resp = pcaptureCmd(handler, words[1:])
 	case "/bungee":
 		resp = packets.COLOR_RED + "To nie bungee, czego tu szukasz? :P"
 	default:
@@ -275,6 +279,63 @@ func findCmd(handler *potoq.Handler, args []string) string {
 	return fmt.Sprintf("%sPlayer %s is on %s", packets.COLOR_GREEN, player.Nickname, player.UpstreamName)
 }
 
+// /ptrace <nickname> [stop | cb|sb parsed 0x0E,0x22...] - packet trace of player, see potoq.ParseTraceOptions
+func ptraceCmd(handler *potoq.Handler, args []string) string {
+	if !handler.HasPermission("bungeecord.command.ptrace") {
+		return INSUFFICIENT_PERMS
+	}
+	if len(args) < 1 {
+		return packets.COLOR_RED + "Bad command syntax! /ptrace <nickname> [stop | cb|sb parsed <ids...>]"
+	}
+
+	player := potoq.Players.GetByNickname(args[0])
+	if player == nil {
+		return packets.COLOR_RED + "Player not found!"
+	}
+	if len(args) == 2 && strings.ToLower(args[1]) == "stop" {
+		if !player.StopTrace() {
+			return packets.COLOR_RED + "Player " + player.Nickname + " isn't traced"
+		}
+		return packets.COLOR_GREEN + "Trace of " + player.Nickname + " stopped"
+	}
+
+	opts, err := potoq.ParseTraceOptions(args[1:])
+	if err != nil {
+		return packets.COLOR_RED + err.Error()
+	}
+	status, err := player.StartTrace(opts)
+	if err != nil {
+		return packets.COLOR_RED + "Trace error: " + err.Error()
+	}
+	return fmt.Sprintf("%sTracing %s (%s) to %s", packets.COLOR_GREEN, player.Nickname, status.Options, status.Path)
+}
+
+// /pcapture <nickname> [stop] - binary capture of player session for potoq-replay
+func pcaptureCmd(handler *potoq.Handler, args []string) string {
+	if !handler.HasPermission("bungeecord.command.pcapture") {
+		return INSUFFICIENT_PERMS
+	}
+	if len(args) < 1 || len(args) > 2 || (len(args) == 2 && strings.ToLower(args[1]) != "stop") {
+		return packets.COLOR_RED + "Bad command syntax! /pcapture <nickname> [stop]"
+	}
+
+	player := potoq.Players.GetByNickname(args[0])
+	if player == nil {
+		return packets.COLOR_RED + "Player not found!"
+	}
+	if len(args) == 2 {
+		if !player.StopCapture() {
+			return packets.COLOR_RED + "Player " + player.Nickname + " isn't captured"
+		}
+		return packets.COLOR_GREEN + "Capture of " + player.Nickname + " stopped"
+	}
+	path, err := player.StartCapture()
+	if err != nil {
+		return packets.COLOR_RED + "Capture error: " + err.Error()
+	}
+	return fmt.Sprintf("%sCapturing %s to %s", packets.COLOR_GREEN, player.Nickname, path)
+}
+
 func serverNames() []string {
 	var names []string
 	potoq.UpstreamLock.Lock()
@@ -0,0 +1,30 @@
+package bungeecord
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzReadJavaUTF(f *testing.F) {
+	for _, s := range []string{"", "BungeeCord", "zażółć \x00 ￿"} {
+		var buf bytes.Buffer
+		WriteJavaUTF(&buf, s)
+		f.Add(buf.Bytes())
+	}
+	f.Add([]byte{0x00, 0x02, 0xED, 0xA0}) // truncated rune
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s, err := ReadJavaUTF(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		// overlong forms are accepted, so only decoded string has to survive round trip
+		var buf bytes.Buffer
+		if err := WriteJavaUTF(&buf, s); err != nil {
+			t.Fatalf("write of %q: %v", s, err)
+		}
+		again, err := ReadJavaUTF(&buf)
+		if err != nil || again != s {
+			t.Fatalf("round trip of %q: got %q, %v", s, again, err)
+		}
+	})
+}
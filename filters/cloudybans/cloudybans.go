@@ -30,8 +30,8 @@ func RegisterFilters(dbmap_ *gorp.DbMap, redis_ radix.Client) {
 	dbmap.AddTableWithName(Login{}, "cloudyBans_logins").SetKeys(true, "Id")
 	redis = redis_
 
-	potoq.RegisterPacketFilter(&packets.ChatMessagePacketSB{}, MuteFilter)
-	potoq.RegisterPacketFilter(&packets.ChatMessagePacketSB{}, CommandsFilter)
+	potoq.RegisterReadOnlyPacketFilter(&packets.ChatMessagePacketSB{}, MuteFilter)
+	potoq.RegisterReadOnlyPacketFilter(&packets.ChatMessagePacketSB{}, CommandsFilter)
 	//potoq.RegisterPacketFilter(&packets.HandshakePacket{}, HandshakeFilter)
 }
 
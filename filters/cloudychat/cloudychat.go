@@ -69,7 +69,7 @@ func RegisterFilters(a radix.Client) {
 
 	globalChatLimiter = rate.NewLimiter(rate.Limit(globalChatConfig.RateLimitHz), globalChatConfig.RateLimitBurst)
 
-	potoq.RegisterPacketFilter(&packets.JoinGamePacketCB{}, resFilter)
+	potoq.RegisterReadOnlyPacketFilter(&packets.JoinGamePacketCB{}, resFilter)
 
 	go automessage()
 }
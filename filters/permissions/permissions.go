@@ -21,7 +21,7 @@ var Groups map[string]PermSet // dlaczego nie prywatne?
 var Users map[string]PermSet
 
 func RegisterFilters() {
-	potoq.RegisterPacketFilter(&packets.ChatMessagePacketSB{}, onChatMessage)
+	potoq.RegisterReadOnlyPacketFilter(&packets.ChatMessagePacketSB{}, onChatMessage)
 	load_err := LoadPermissions()
 	if load_err != nil {
 		panic(load_err)
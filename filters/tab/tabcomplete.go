@@ -10,7 +10,7 @@ import (
 )
 
 func RegisterFilters() {
-	potoq.RegisterPacketFilter(&packets.TabCompletePacketSB{}, tabFilter)
+	potoq.RegisterReadOnlyPacketFilter(&packets.TabCompletePacketSB{}, tabFilter)
 }
 
 func tabFilter(handler *potoq.Handler, packet packets.Packet) error {
@@ -0,0 +1,124 @@
+package potoq
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/Craftserve/potoq/packets"
+)
+
+func dropPacket(*Handler, packets.Packet) error {
+	return ErrDropPacket
+}
+
+func TestHandlerFilters(t *testing.T) {
+	first, second := new(Handler), new(Handler)
+	chat := &packets.ChatMessagePacketSB{Message: "filtered"}
+	remove := first.AddPacketFilter(chat, func(handler *Handler, packet packets.Packet) error {
+		if handler != first {
+			t.Error("filter called for other handler")
+		}
+		return ErrDropPacket
+	})
+	if err := first.dispatchPacket(chat); err != ErrDropPacket {
+		t.Fatalf("filter of handler not dispatched: %v", err)
+	}
+	if err := second.dispatchPacket(chat); err != nil {
+		t.Fatalf("filter dispatched for other handler: %v", err)
+	}
+	remove()
+	remove()
+	if err := first.dispatchPacket(chat); err != nil {
+		t.Fatalf("removed filter dispatched: %v", err)
+	}
+
+	first.AddPacketFilter(chat, dropPacket)
+	first.AddPacketFilter(&packets.TabCompletePacketSB{}, dropPacket)
+	first.ClearPacketFilters()
+	if filters := first.handlerFilters(); len(filters) != 0 {
+		t.Fatalf("filters left after clear: %v", filters)
+	}
+	if err := first.dispatchPacket(chat); err != nil {
+		t.Fatalf("cleared filter dispatched: %v", err)
+	}
+}
+
+func TestHandlerFiltersClearedOnClose(t *testing.T) {
+	client, server := net.Pipe()
+	handler := NewHandler(server)
+	handler.AddPacketFilter(&packets.ChatMessagePacketSB{}, func(*Handler, packets.Packet) error { return nil })
+	handler.AddStatePacketFilter(packets.LOGIN, &packets.LoginStartPacket{}, func(*Handler, packets.Packet) error { return nil })
+	if len(handler.handlerFilters()) != 2 {
+		t.Fatalf("filters not added: %v", handler.handlerFilters())
+	}
+	client.Close()
+	handler.Handle()
+	if filters := handler.handlerFilters(); len(filters) != 0 {
+		t.Fatalf("filters left after close: %v", filters)
+	}
+}
+
+// > panics
+
+type panicCommand struct{}
+
+func (panicCommand) Execute(*Handler) error {
+	panic("command")
+}
+
+func TestFilterPanic(t *testing.T) {
+	defer func(limit uint32) { FilterPanicLimit = limit }(FilterPanicLimit)
+	FilterPanicLimit = 2
+	handler := new(Handler)
+	chat := &packets.ChatMessagePacketSB{Message: "boom"}
+	handler.AddPacketFilter(chat, func(_ *Handler, packet packets.Packet) error {
+		panic("filter " + packet.(*packets.ChatMessagePacketSB).Message)
+	})
+	for i := 0; i < 2; i++ {
+		err := handler.dispatchPacket(chat)
+		if perr, ok := err.(*PanicError); !ok || perr.Value != "filter boom" || perr.Packet != chat || perr.Command != nil || len(perr.Stack) == 0 {
+			t.Fatalf("panic %d returned %#v", i, err)
+		}
+	}
+	if err := handler.dispatchPacket(chat); err != nil {
+		t.Fatalf("filter not disabled after FilterPanicLimit panics: %v", err)
+	}
+
+	err := handler.executeCommand(panicCommand{})
+	if perr, ok := err.(*PanicError); !ok || perr.Value != "command" || perr.Command != (panicCommand{}) || perr.Packet != nil {
+		t.Fatalf("command panic returned %#v", err)
+	}
+}
+
+// > state filters
+
+func TestStateFilters(t *testing.T) {
+	handler := new(Handler)
+	handler.AddStatePacketFilter(packets.LOGIN, &packets.LoginCompressionPacket{}, func(_ *Handler, packet packets.Packet) error {
+		packet.(*packets.LoginCompressionPacket).Threshold = 512
+		return ErrDropPacket
+	})
+	compression := &packets.LoginCompressionPacket{Threshold: 256}
+	if err := handler.dispatchPacket(compression); err != nil {
+		t.Fatalf("LOGIN filter dispatched in PLAY state: %v", err)
+	}
+	if err := handler.dispatchStatePacket(packets.LOGIN, compression); err != ErrDropPacket || compression.Threshold != 512 {
+		t.Fatalf("LOGIN filter not dispatched: %v", err)
+	}
+
+	// packets proxy has to send can be modified by filters, but not dropped
+	var buf bytes.Buffer
+	writer := packets.NewPacketWriter(&buf, 256)
+	compression.Threshold = 256
+	if err := handler.sendStatePacket(packets.LOGIN, writer, compression, true); err != nil {
+		t.Fatal(err)
+	}
+	var sent packets.LoginCompressionPacket
+	if _, err := packets.ParsePackets(packets.NewPacketReader(&buf, 256), &sent); err != nil || sent.Threshold != 512 {
+		t.Fatalf("sent %+v: %v", sent, err)
+	}
+	if err := handler.writeStatePacket(packets.LOGIN, writer, compression, true); err != nil || buf.Len() != 0 {
+		t.Fatalf("dropped packet written: %x, %v", buf.Bytes(), err)
+	}
+}
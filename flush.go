@@ -0,0 +1,57 @@
+package potoq
+
+import "time"
+
+// FlushPolicy decides when packets buffered in handler's writers are flushed to socket.
+//
+// Go enables TCP_NODELAY on every TCP connection, so kernel never delays small segments (Nagle's
+// algorithm is off) and every flush is a write syscall sending at least one segment. Flushing after
+// each packet gives lowest latency, but costs a syscall and a mostly empty TCP segment per packet,
+// which hurts on busy servers sending hundreds of small entity packets per tick. We keep NoDelay on
+// and batch in userspace instead: packets are flushed when no other packet is waiting for the same
+// direction, or when one of the limits below is hit. Policy never waits for packets which have not
+// arrived yet, so idle connections are never delayed - only bursts are coalesced.
+type FlushPolicy struct {
+	MaxPackets int           // flush after this many unflushed packets, <= 1 means flush every packet
+	MaxBytes   int           // flush after this many unflushed bytes, 0 means no limit
+	MaxLatency time.Duration // flush when oldest unflushed packet waits this long, 0 means no limit
+}
+
+// Copied into every new Handler, can be changed per handler in PreLoginHandler
+var DefaultFlushPolicy = FlushPolicy{
+	MaxPackets: 64,
+	MaxBytes:   32 * 1024,
+	MaxLatency: 5 * time.Millisecond,
+}
+
+// unflushed data in one direction
+type flushState struct {
+	packets int
+	bytes   int
+	first   time.Time
+}
+
+// Registers packet of given size written to buffer. queued is number of packets already read from the
+// other side and waiting to be handled. Returns true if writer should be flushed now.
+func (s *flushState) add(policy *FlushPolicy, size int, queued int, now time.Time) bool {
+	if s.packets == 0 {
+		s.first = now
+	}
+	s.packets++
+	s.bytes += size
+
+	flush := queued <= 0 ||
+		s.packets >= policy.MaxPackets ||
+		(policy.MaxBytes > 0 && s.bytes >= policy.MaxBytes) ||
+		(policy.MaxLatency > 0 && now.Sub(s.first) >= policy.MaxLatency)
+	if flush {
+		s.reset()
+	}
+	return flush
+}
+
+// called after writer was flushed outside of add (eg. by command)
+func (s *flushState) reset() {
+	s.packets = 0
+	s.bytes = 0
+}
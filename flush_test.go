@@ -0,0 +1,113 @@
+package potoq
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestFlushStateFlushesWhenQueueIsEmpty(t *testing.T) {
+	var s flushState
+	now := time.Now()
+	if s.add(&DefaultFlushPolicy, 100, 3, now) {
+		t.Error("flushed with queued packets")
+	}
+	if !s.add(&DefaultFlushPolicy, 100, 0, now) {
+		t.Error("not flushed with empty queue")
+	}
+}
+
+func TestFlushStateLimits(t *testing.T) {
+	now := time.Now()
+	policy := FlushPolicy{MaxPackets: 3, MaxBytes: 1000, MaxLatency: time.Millisecond}
+
+	var s flushState
+	if s.add(&policy, 10, 5, now) || s.add(&policy, 10, 5, now) || !s.add(&policy, 10, 5, now) {
+		t.Error("MaxPackets not respected")
+	}
+	if s.add(&policy, 600, 5, now) || !s.add(&policy, 600, 5, now) {
+		t.Error("MaxBytes not respected")
+	}
+	if s.add(&policy, 10, 5, now) || !s.add(&policy, 10, 5, now.Add(time.Millisecond)) {
+		t.Error("MaxLatency not respected")
+	}
+}
+
+// > benchmarks, simulated traffic - compare syscalls (flushes) and added latency per packet
+
+type simPacket struct {
+	arrival time.Duration
+	size    int
+}
+
+// bursts of packets, like chunk and entity updates sent every server tick
+func simulatedTraffic(n int) []simPacket {
+	rnd := rand.New(rand.NewSource(1))
+	var res []simPacket
+	var t time.Duration
+	for len(res) < n {
+		burst := 1 + rnd.Intn(40)
+		for i := 0; i < burst && len(res) < n; i++ {
+			res = append(res, simPacket{arrival: t, size: 10 + rnd.Intn(300)})
+			t += time.Microsecond
+		}
+		t += time.Duration(rnd.Intn(50)) * time.Millisecond / 10
+	}
+	return res
+}
+
+// handling single packet costs this much simulated time
+const simPacketCost = 2 * time.Microsecond
+
+func runFlushSimulation(b *testing.B, decide func(i int, p simPacket, queued int, now time.Duration) bool) {
+	traffic := simulatedTraffic(10000)
+	var flushes, handled int
+	var latency time.Duration
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var now time.Duration
+		var unflushed []time.Duration
+		for i, p := range traffic {
+			if now < p.arrival {
+				now = p.arrival
+			}
+			now += simPacketCost
+			queued := 0 // packets which already arrived while we were busy
+			for j := i + 1; j < len(traffic) && traffic[j].arrival <= now; j++ {
+				queued++
+			}
+			unflushed = append(unflushed, p.arrival)
+			if decide(i, p, queued, now) {
+				flushes++
+				for _, a := range unflushed {
+					latency += now - a
+				}
+				unflushed = unflushed[:0]
+			}
+			handled++
+		}
+	}
+	b.ReportMetric(float64(flushes)/float64(handled), "syscalls/packet")
+	b.ReportMetric(float64(latency.Nanoseconds())/float64(handled), "latency-ns/packet")
+}
+
+// handler before FlushPolicy: channel was unbuffered, so len(chan) was always 0 and every packet was flushed
+func BenchmarkFlushLegacyHeuristic(b *testing.B) {
+	var last time.Duration
+	runFlushSimulation(b, func(i int, p simPacket, _ int, now time.Duration) bool {
+		queued := 0 // len(unbuffered chan)
+		flush := queued == 0 || now-last > 50*time.Millisecond
+		if flush {
+			last = now
+		}
+		return flush
+	})
+}
+
+func BenchmarkFlushPolicy(b *testing.B) {
+	var s flushState
+	t0 := time.Now()
+	runFlushSimulation(b, func(i int, p simPacket, queued int, now time.Duration) bool {
+		return s.add(&DefaultFlushPolicy, p.size, queued, t0.Add(now))
+	})
+}
@@ -0,0 +1,100 @@
+package potoq
+
+import (
+	"bytes"
+	"compress/zlib"
+	"strings"
+	"testing"
+
+	"github.com/Craftserve/potoq/packets"
+)
+
+// > forwarding of original packet data
+
+// reads single packet and forwards it like pump does
+func forwardOnce(t *testing.T, handler *Handler, input []byte, threshold int) []byte {
+	t.Helper()
+	reader := packets.NewPacketReader(bytes.NewReader(input), threshold)
+	var out bytes.Buffer
+	writer := packets.NewPacketWriter(&out, threshold)
+	packet, err := handler.readPacket(reader, packets.ServerBound)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = handler.handlePacket(packet, packets.ServerBound, writer, true); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func serializePacket(t *testing.T, packet packets.Packet, threshold int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := packets.NewPacketWriter(&buf, threshold).WritePacket(packet, true); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// packet with data (id and fields) compressed at given zlib level
+func compressedFrame(t *testing.T, data []byte, level int) []byte {
+	t.Helper()
+	var body bytes.Buffer
+	packets.WriteVarInt(&body, packets.VarInt(len(data)))
+	zw, err := zlib.NewWriterLevel(&body, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw.Write(data)
+	zw.Close()
+	var frame bytes.Buffer
+	packets.WriteVarInt(&frame, packets.VarInt(body.Len()))
+	body.WriteTo(&frame)
+	return frame.Bytes()
+}
+
+// Packets seen only by read-only filters are forwarded as they were received, packets seen by
+// other filters are serialized (and compressed) again. Input isn't what proxy would write itself:
+// string length is non-minimal VarInt or data is compressed with other zlib level.
+func TestForwardOriginalData(t *testing.T) {
+	message := strings.Repeat("original data ", 10)
+	chat := &packets.ChatMessagePacketSB{Message: message}
+
+	data := []byte{0x03, 0x80 | byte(len(message)&0x7F), 0x80 | byte(len(message)>>7), 0x00}
+	data = append(data, message...)
+	uncompressed := append([]byte{byte(len(data)&0x7F | 0x80), byte(len(data) >> 7)}, data...)
+	compressed := compressedFrame(t, append([]byte{0x03, byte(len(message)&0x7F | 0x80), byte(len(message) >> 7)}, message...), zlib.NoCompression)
+
+	for _, c := range []struct {
+		name      string
+		input     []byte
+		threshold int
+	}{
+		{"uncompressed", uncompressed, packets.NoCompression},
+		{"compressed", compressed, 64},
+	} {
+		canonical := serializePacket(t, chat, c.threshold)
+		if bytes.Equal(canonical, c.input) {
+			t.Fatalf("%s: input is same as serialized packet", c.name)
+		}
+		handler := &Handler{Nickname: "forwarder"}
+
+		if out := forwardOnce(t, handler, c.input, c.threshold); !bytes.Equal(out, c.input) {
+			t.Errorf("%s: unparsed packet changed: %x", c.name, out)
+		}
+
+		remove := handler.AddReadOnlyPacketFilter(chat, func(*Handler, packets.Packet) error { return nil })
+		if out := forwardOnce(t, handler, c.input, c.threshold); !bytes.Equal(out, c.input) {
+			t.Errorf("%s: packet seen by read-only filter changed: %x", c.name, out)
+		}
+		remove()
+
+		handler.AddPacketFilter(chat, func(_ *Handler, packet packets.Packet) error {
+			packet.(*packets.ChatMessagePacketSB).Message = message // same value, but filter could change it
+			return nil
+		})
+		if out := forwardOnce(t, handler, c.input, c.threshold); !bytes.Equal(out, canonical) {
+			t.Errorf("%s: packet seen by modifying filter not serialized again: %x", c.name, out)
+		}
+	}
+}
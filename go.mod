@@ -1,17 +1,11 @@
 module github.com/Craftserve/potoq
 
-go 1.13
+go 1.18
 
 require (
-	github.com/RyanW02/NamedBinaryTagParser v0.0.0-20200914135952-901a864cf141
-	github.com/go-gorp/gorp v2.2.0+incompatible // indirect
-	github.com/go-sql-driver/mysql v1.5.0 // indirect
 	github.com/google/uuid v1.1.1
-	github.com/lib/pq v1.8.0 // indirect
 	github.com/libp2p/go-buffer-pool v0.0.2
-	github.com/mattn/go-sqlite3 v1.14.3 // indirect
 	github.com/mediocregopher/radix/v3 v3.5.2
-	github.com/poy/onpar v1.0.0 // indirect
 	github.com/sirupsen/logrus v1.5.0
 	github.com/ziutek/mymysql v1.5.4
 	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
@@ -19,3 +13,13 @@ require (
 	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7
 	gopkg.in/yaml.v2 v2.2.5
 )
+
+require (
+	github.com/go-gorp/gorp v2.2.0+incompatible // indirect
+	github.com/go-sql-driver/mysql v1.5.0 // indirect
+	github.com/lib/pq v1.8.0 // indirect
+	github.com/mattn/go-sqlite3 v1.14.3 // indirect
+	github.com/poy/onpar v1.0.0 // indirect
+	golang.org/x/sys v0.0.0-20191026070338-33540a1f6037 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+)
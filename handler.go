@@ -2,6 +2,8 @@ package potoq
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"io"
@@ -19,6 +21,9 @@ import (
 	"gopkg.in/tomb.v1"
 )
 
+// Packets from players bigger than this are rejected, packets from upstream can have up to packets.MaxPacketSize
+var MaxServerboundPacketSize = 128 * 1024
+
 type Permissible interface {
 	HasPermission(name string) bool
 }
@@ -30,14 +35,12 @@ type Handler struct {
 	DownstreamR packets.PacketReader
 	DownstreamW packets.PacketWriter
 
-	downstream_packets     chan packets.Packet
 	downstream_tomb        *tomb.Tomb
 	DownstreamAddr         string
 	DownstreamPacketsCount uint64
 
 	UpstreamW            packets.PacketWriter
 	UpstreamC            io.Closer
-	UpstreamPackets      chan packets.Packet
 	UpstreamTomb         *tomb.Tomb
 	UpstreamName         string
 	UpstreamPacketsCount uint64
@@ -55,49 +58,107 @@ type Handler struct {
 	FilterData     sync.Map
 
 	// packet dispatcher and other hooks
+	state       int32      // atomic packets.ConnState of player connection, see State()
+	lock        sync.Mutex // held by pumps while handling packet and during command execution
 	commandChan HandlerCommandChan
 	CloseHooks  []func()
+	filtersLock sync.Mutex
+	filters     atomic.Value // handlerFilterSet, see AddPacketFilter()
+
+	// compression threshold for player connection, negative disables compression, see ListenerConfig
+	CompressThreshold int
+
+	// see FlushPolicy, changes after handleProxy started have no effect
+	FlushPolicy FlushPolicy
+	upflush     flushState // packets from upstream
+	downflush   flushState // packets from downstream
+	last_packet int64      // atomic unix timestamp, used for idle timeout
+
+	// see MemoryPolicy, changes after handleProxy started have no effect
+	MemoryPolicy   MemoryPolicy
+	memory         memoryBudget
+	downstream_out *queuedWriter // writes to DownstreamC during login and play, see handleProxy
 
 	// startup packets, public because contents may be needed in filters etc
 	ClientSettings packets.Packet
 	MCBrand        packets.Packet
 
-	// logging
-	PacketTrace io.WriteCloser
-	t0          time.Time
+	// packet trace, see StartTrace
+	trace     atomic.Value // *packetTracer
+	traceLock sync.Mutex
+
+	// session capture, see StartCapture
+	capture            atomic.Value // *capture.Writer
+	captureLock        sync.Mutex
+	upstream_threshold int // compression threshold of current upstream, packets.NoCompression if disabled
 }
 
-func NewHandler(downsock *net.TCPConn) *Handler {
+// packet read by pump, raw holds original data of parsed packets until they are written
+type queuedPacket struct {
+	packet packets.Packet
+	raw    packets.RawPacket
+}
+
+// original packet data, empty if packet was created by proxy
+func (queued queuedPacket) rawPacket() packets.RawPacket {
+	if raw, ok := queued.packet.(packets.RawPacket); ok {
+		return raw
+	}
+	return queued.raw
+}
+
+// size of original packet data, used for flushing decisions
+func (queued queuedPacket) size() int {
+	return queued.rawPacket().Size()
+}
+
+// Player connection is usually *net.TCPConn, any other net.Conn (eg. from net.Pipe in tests) works too
+func NewHandler(downsock net.Conn) *Handler {
 	h := &Handler{}
 
 	h.playerList = make(map[uuid.UUID]packets.PlayerListItem)
 
 	h.DownstreamC = downsock
-	h.DownstreamR = packets.NewPacketReader(h.DownstreamC, 0)
-	h.DownstreamW = packets.NewPacketWriter(h.DownstreamC, 0)
-	h.DownstreamAddr = downsock.RemoteAddr().(*net.TCPAddr).IP.String()
+	h.DownstreamR = packets.NewPacketReaderSize(h.DownstreamC, packets.NoCompression, MaxServerboundPacketSize)
+	h.DownstreamW = packets.NewPacketWriter(h.DownstreamC, packets.NoCompression)
+	if addr, ok := downsock.RemoteAddr().(*net.TCPAddr); ok {
+		h.DownstreamAddr = addr.IP.String()
+	} else {
+		h.DownstreamAddr = downsock.RemoteAddr().String()
+	}
 
 	h.Authenticator = getRandomAuthenticator()
 
 	h.commandChan = make(HandlerCommandChan, 10)
-
-	h.t0 = time.Now()
+	h.CompressThreshold = DefaultListenerConfig.CompressThreshold
+	h.FlushPolicy = DefaultFlushPolicy
+	h.MemoryPolicy = DefaultMemoryPolicy
+	h.memory.init(MemoryBudget)
 	return h
 }
 
 func (handler *Handler) Handle() {
 	_, err := packets.ParsePackets(handler.DownstreamR, &handler.Handshake)
+	if err == nil {
+		err = handler.dispatchStatePacket(packets.HANDSHAKING, &handler.Handshake)
+	}
 
 	if err == nil {
-		switch packets.ConnState(handler.Handshake.NextState) {
+		next := packets.ConnState(handler.Handshake.NextState)
+		switch next {
 		case packets.STATUS:
+			handler.setState(next)
 			err = handler.handleStatus()
 		case packets.LOGIN:
+			handler.setState(next)
 			err = handler.handleProxy()
 		default:
 			err = fmt.Errorf("Invalid handshake.NextState! %d", handler.Handshake.NextState)
 		}
 	}
+	if err == ErrDropPacket { // dropped handshake, just close connection
+		err = nil
+	}
 
 	handler.Tomb.Kill(err)
 
@@ -106,6 +167,11 @@ func (handler *Handler) Handle() {
 		handler.UpstreamC = nil
 		handler.UpstreamW = nil
 	}
+	if handler.downstream_out != nil {
+		if err := handler.downstream_out.Close(downstreamDrainTimeout); err != nil {
+			handler.Log().WithError(err).Debug("Downstream queue close error")
+		}
+	}
 	if handler.DownstreamC != nil {
 		handler.DownstreamC.Close()
 		handler.DownstreamR = nil
@@ -117,6 +183,9 @@ func (handler *Handler) Handle() {
 	}
 
 	handler.Tomb.Done()
+	handler.ClearPacketFilters()
+	handler.StopTrace()
+	handler.StopCapture()
 
 	err = handler.Tomb.Err()
 	if err != nil {
@@ -134,13 +203,17 @@ func (handler *Handler) handleStatus() error {
 	if err != nil {
 		return err
 	}
+	err = handler.dispatchStatePacket(packets.STATUS, &request)
+	if err != nil {
+		return err
+	}
 
 	status := PingHandler(&handler.Handshake)
 	json, err := status.Serialize()
 	if err != nil {
 		return err
 	}
-	err = handler.DownstreamW.WritePacket(&packets.StatusResponsePacketCB{json}, true)
+	err = handler.writeStatePacket(packets.STATUS, handler.DownstreamW, &packets.StatusResponsePacketCB{json}, true)
 	if err != nil {
 		return err
 	}
@@ -150,61 +223,91 @@ func (handler *Handler) handleStatus() error {
 	if err != nil {
 		return nil
 	}
+	err = handler.dispatchStatePacket(packets.STATUS, &ping)
+	if err != nil {
+		return err
+	}
 
-	return handler.DownstreamW.WritePacket(&packets.StatusPingPacketCB{Time: ping.Time}, true)
+	return handler.writeStatePacket(packets.STATUS, handler.DownstreamW, &packets.StatusPingPacketCB{Time: ping.Time}, true)
 }
 
-func (handler *Handler) connectUpstream(name string, addr string) (err error) {
+// Connects and logs in to upstream. Returned reader is positioned at first PLAY packet,
+// caller should start upstream pump with startUpstreamPump after reading packets it needs.
+func (handler *Handler) connectUpstream(upstream *ReconnectCommand) (upstream_r packets.PacketReader, err error) {
+	name, addr := upstream.Name, upstream.Addr
 	handler.Log().WithFields(logrus.Fields{
 		"address": addr,
 	}).Info("Connecting to upstream")
-	upsock, err := net.Dial("tcp", addr)
+	upsock, err := DialUpstream(addr)
 	if err != nil {
-		return err
+		return
 	}
-	handler.UpstreamW = packets.NewPacketWriter(upsock, 0)
+	handler.UpstreamW = packets.NewPacketWriter(upsock, packets.NoCompression)
 	handler.UpstreamC = upsock
 	handler.UpstreamName = name
 
 	handshake := handler.Handshake
 	// Escape handshake host data separator character
 	handshake.Host = strings.Replace(handshake.Host, "\u0000", "\\u0000", -1)
-	// niestety nie da sie tego zaimplementowac w filtrach, bo nie robimy dispatch w state roznym niz PLAY
+	// bungeecord legacy forwarding, HANDSHAKING filters can rewrite it further
 	handshake.Host = handshake.Host + "\u0000" + handler.DownstreamAddr + "\u0000" + handler.UUID.String()
 
-	handler.UpstreamW.WritePacket(&handshake, false)
-	err = handler.UpstreamW.WritePacket(&packets.LoginStartPacket{handler.Nickname}, true)
+	err = handler.writeStatePacket(packets.HANDSHAKING, handler.UpstreamW, &handshake, false)
+	if err != nil {
+		return
+	}
+	err = handler.writeStatePacket(packets.LOGIN, handler.UpstreamW, &packets.LoginStartPacket{handler.Nickname}, true)
 	if err != nil {
 		return
 	}
 
-	upstream_r := packets.NewPacketReader(upsock, 0)
-	packet, err := packets.ParsePackets(upstream_r, &packets.LoginCompressionPacket{}, &packets.LoginKickPacket{})
+	// upstream with compression disabled sends LoginSuccessPacket right away
+	var success packets.LoginSuccessPacket
+	var threshold = packets.NoCompression
+	upstream_r = packets.NewPacketReader(upsock, packets.NoCompression)
+	packet, err := packets.ParsePackets(upstream_r, &packets.LoginCompressionPacket{}, &packets.LoginKickPacket{}, &success)
 	if err != nil {
-		return err
+		return
+	}
+	err = handler.inspectStatePacket(packets.LOGIN, packet)
+	if err != nil {
+		return
 	}
 	switch p := packet.(type) {
 	case *packets.LoginCompressionPacket:
-		if int(p.Threshold) != packets.CompressThreshold {
-			return fmt.Errorf("connectUpstream: bad compression threshold %d", p.Threshold)
-		}
+		threshold = int(p.Threshold)
 	case *packets.LoginKickPacket:
 		handler.Log().WithFields(logrus.Fields{
 			"address": addr,
 			"message": p.Message,
 		}).Info("Upstream connect kick")
-		return fmt.Errorf(p.Message)
+		return nil, fmt.Errorf(p.Message)
+	case *packets.LoginSuccessPacket:
 	default:
 		panic("unexpected packet type")
 	}
+	if upstream.CompressThreshold != nil && normalizeThreshold(*upstream.CompressThreshold) != normalizeThreshold(threshold) {
+		return nil, fmt.Errorf("connectUpstream: compression threshold %d, expected %d", threshold, *upstream.CompressThreshold)
+	}
+	threshold = normalizeThreshold(threshold)
+	handler.upstream_threshold = threshold
+	if w := handler.captureWriter(); w != nil {
+		handler.checkCapture(w, w.Upstream(name, addr))
+		handler.checkCapture(w, w.Compression(packets.ClientBound, threshold))
+	}
 
-	upstream_r = packets.NewPacketReader(bufio.NewReaderSize(upsock, packets.MaxPacketSize), packets.CompressThreshold)
-	handler.UpstreamW = packets.NewPacketWriter(bufio.NewWriterSize(upsock, packets.MaxPacketSize), packets.CompressThreshold)
+	upstream_r = packets.NewPacketReader(bufio.NewReaderSize(upsock, 128*1024), threshold)
+	handler.UpstreamW = packets.NewPacketWriter(bufio.NewWriterSize(upsock, 128*1024), threshold)
 
-	var success packets.LoginSuccessPacket
-	_, err = packets.ParsePackets(upstream_r, &success)
-	if err != nil {
-		return
+	if packet != &success {
+		_, err = packets.ParsePackets(upstream_r, &success)
+		if err != nil {
+			return
+		}
+		err = handler.inspectStatePacket(packets.LOGIN, &success)
+		if err != nil {
+			return
+		}
 	}
 
 	handler.Log().WithFields(logrus.Fields{
@@ -213,85 +316,179 @@ func (handler *Handler) connectUpstream(name string, addr string) (err error) {
 		"success": success,
 	}).Info("Upstream connected")
 
-	handler.UpstreamPackets = make(chan packets.Packet)
+	return upstream_r, nil
+}
+
+func (handler *Handler) startUpstreamPump(upstream_r packets.PacketReader) {
 	handler.UpstreamTomb = &tomb.Tomb{}
+	handler.upflush.reset()
 	atomic.StoreUint64(&handler.UpstreamPacketsCount, 0)
-	go handler.read_packets(upstream_r, handler.UpstreamPackets, handler.UpstreamTomb, packets.ClientBound)
-
-	return nil
+	go handler.pump(upstream_r, packets.ClientBound, handler.UpstreamTomb)
 }
 
-func (handler *Handler) read_packets(reader packets.PacketReader, packet_chan chan<- packets.Packet, tmb *tomb.Tomb, direction packets.Direction) {
-	var err error
-	var raw packets.RawPacket
+var errPumpStopped = errors.New("pump stopped")
 
-loop:
-	for tmb.Err() == tomb.ErrStillAlive {
-		raw, err = reader.ReadPacket()
-		if err != nil {
-			break
+// Reads packets from one side of connection and passes them to the other side. Packets are read and parsed
+// without any lock, handler lock is held only while running filters and writing, so filters and commands
+// never run concurrently. Pump ends when tmb is killed or connection fails, error is reported with tmb.
+func (handler *Handler) pump(reader packets.PacketReader, direction packets.Direction, tmb *tomb.Tomb) {
+	defer tmb.Done()
+	for {
+		packet, err := handler.readPacket(reader, direction)
+		var drop bool
+		if err == nil && direction == packets.ClientBound {
+			drop, err = handler.checkMemory(packet)
 		}
-
-		var packet packets.Packet
-		should_parse := packetsParsed.Get(int(direction-1)*256 + int(raw.ID))
-		if should_parse {
-			packet = packets.NewPacket(raw.ID, packets.PLAY, direction)
-			if packet == nil {
-				err = fmt.Errorf("Not implemented packet -> %v", raw.ID)
-				break
+		if err == nil {
+			handler.memory.acquire(len(packet.rawPacket().Payload))
+			if drop {
+				handler.releasePacket(packet)
+			} else {
+				err = handler.forwardPacket(packet, reader, direction, tmb)
+				handler.flushTrace()
 			}
+		}
+		switch err {
+		case nil:
+			continue
+		case errPumpStopped:
+		case io.EOF:
+			tmb.Kill(err)
+		default:
+			tmb.Killf("%s %w", direction, err)
+		}
+		return
+	}
+}
 
-			var payload io.Reader
-			payload, err = reader.Payload()
-			if err != nil {
-				break
-			}
+// reads single packet and parses it if any filter needs it
+func (handler *Handler) readPacket(reader packets.PacketReader, direction packets.Direction) (packet queuedPacket, err error) {
+	raw, err := reader.ReadPacket()
+	if err != nil {
+		return
+	}
 
-			err = packet.Parse(payload)
-			if err != nil { // dump packet to file
-				var fname string = "WTF"
-				f, ferr := ioutil.TempFile("trace/", fmt.Sprintf("%s_%02X_*.packet", handler.Nickname, raw.ID))
-				if ferr != nil {
-					fname = "FERR: " + ferr.Error()
-					handler.Log().WithError(ferr).Error("read_packets: packet dump tempfile error")
-				} else {
-					fname = f.Name()
-					packets.NewPacketWriter(f, 0).WritePacket(raw, true)
-					f.Close()
-				}
-				err = fmt.Errorf("Error parsing packet %02X: %w, dump: %s", raw.ID, err, fname)
-				// err = fmt.Errorf("Error parsing packet %02X: %s, dump: %s, n: %d/%d %d", raw.ID, err, fname, payload_lim.N, len(raw.Payload), raw.DataLength)
-				break
-			}
+	filter_index := packetFilterIndex(packets.PLAY, direction, raw.ID)
+	should_parse := packetsParsed.Get(filter_index) || len(handler.handlerFilters()[filter_index]) > 0
+	capture_w := handler.captureWriter()
+	if !should_parse && capture_w == nil {
+		packet.packet = raw
+		return
+	}
 
-			packets.BufferPool.Put(raw.Payload)
-			raw.Payload = nil
-		} else {
-			packet = raw
+	if should_parse {
+		packet.packet = packets.NewPacket(raw.ID, packets.PLAY, direction)
+		if packet.packet == nil {
+			err = fmt.Errorf("Not implemented packet -> %v", raw.ID)
+			return
 		}
+	}
 
-		select {
-		case packet_chan <- packet:
-		case <-tmb.Dying():
-			break loop
+	var payload io.Reader
+	payload, err = reader.Payload()
+	if err != nil {
+		return
+	}
+	var data []byte // whole data of streamed or captured packet, kept for dump
+	if capture_w != nil {
+		data, err = handler.capturePacket(capture_w, direction, &raw, payload)
+	} else if raw.Streamed() && should_parse {
+		data, err = ioutil.ReadAll(payload)
+	}
+	if err != nil {
+		return
+	}
+	if data != nil {
+		payload = bytes.NewReader(data)
+	}
+	if !should_parse {
+		packet.packet = raw
+		return
+	}
+
+	err = packet.packet.Parse(payload)
+	if err != nil {
+		if data == nil {
+			data, _ = raw.Data()
+		}
+		fname, derr := handler.dumpPacket(direction, raw.ID, data)
+		if derr != nil {
+			handler.Log().WithError(derr).Error("readPacket: packet dump error")
 		}
+		err = fmt.Errorf("Error parsing packet %02X: %w, dump: %s", raw.ID, err, fname)
+		return
 	}
-	close(packet_chan)
-	if err == io.EOF {
-		tmb.Kill(err)
+
+	// keep original data, it's forwarded as is if filters don't modify packet,
+	// streamed packets were consumed by Payload() and must be serialized again
+	if raw.Streamed() {
+		packets.BufferPool.Put(raw.Payload)
 	} else {
-		tmb.Killf("%s %w", direction, err)
+		packet.raw = raw
 	}
-	tmb.Done()
+	return
 }
 
-func (handler *Handler) handlePacket(packet packets.Packet, direction packets.Direction, writer packets.PacketWriter, flush bool) error {
+// runs filters and writes packet to the other side, holding handler lock
+func (handler *Handler) forwardPacket(packet queuedPacket, reader packets.PacketReader, direction packets.Direction, tmb *tomb.Tomb) error {
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+
+	if tmb.Err() != tomb.ErrStillAlive { // killed while we were reading, writers may be already gone
+		handler.releasePacket(packet)
+		return errPumpStopped
+	}
+	atomic.StoreInt64(&handler.last_packet, time.Now().Unix())
+
+	// don't flush if next packet can be read without waiting for network
+	var queued int
+	if reader.PacketBuffered() {
+		queued = 1
+	}
+
+	var flush bool
+	var writer packets.PacketWriter
+	switch direction {
+	case packets.ServerBound:
+		atomic.AddUint64(&handler.DownstreamPacketsCount, 1)
+		flush = handler.downflush.add(&handler.FlushPolicy, packet.size(), queued, time.Now())
+		writer = handler.UpstreamW
+	case packets.ClientBound:
+		atomic.AddUint64(&handler.UpstreamPacketsCount, 1)
+		flush = handler.upflush.add(&handler.FlushPolicy, packet.size(), queued, time.Now())
+		writer = handler.DownstreamW
+	default:
+		panic("invalid direction")
+	}
+	return handler.handlePacket(packet, direction, writer, flush)
+}
+
+func (handler *Handler) handlePacket(queued queuedPacket, direction packets.Direction, writer packets.PacketWriter, flush bool) error {
 	var drop bool
+	packet := queued.packet
 	raw, is_raw := packet.(packets.RawPacket)
+	if !is_raw {
+		raw = queued.raw
+	}
+	defer handler.releasePacket(queued)
+
+	var trace_record traceRecord
+	var fields_before []byte
+	tracer := handler.tracer()
+	if tracer != nil && !tracer.match(direction, packet.PacketID(), !is_raw) {
+		tracer = nil
+	}
+	if tracer != nil {
+		trace_record.Size = queued.size() // streamed packets are consumed by writing
+		if !is_raw {
+			fields_before, _ = packets.MarshalFieldsJSON(packet)
+		}
+	}
+	parsed := packet
 
 	if !is_raw {
 		// run packet handlers
-		err := handler.dispatchPacket(packet)
+		modified, err := handler.dispatch(packets.PLAY, packet)
 		switch err {
 		case nil:
 		case ErrDropPacket:
@@ -299,6 +496,17 @@ func (handler *Handler) handlePacket(packet packets.Packet, direction packets.Di
 		default:
 			return fmt.Errorf("[%s] dispatch error: %w", direction, err)
 		}
+		if !modified && raw.Payload != nil {
+			packet = raw // no need to serialize it again
+		}
+		if tracer != nil && modified {
+			fields_after, _ := packets.MarshalFieldsJSON(parsed)
+			trace_record.Modified = !bytes.Equal(fields_before, fields_after)
+		}
+	}
+	if tracer != nil {
+		trace_record.Dropped = drop
+		handler.tracePacket(tracer, parsed, direction, trace_record)
 	}
 
 	// pass it to other side if needed
@@ -312,9 +520,12 @@ func (handler *Handler) handlePacket(packet packets.Packet, direction packets.Di
 			"direction": direction,
 			"packet":    packet,
 		}).Debug("dropping")
-	}
-	if is_raw {
-		packets.BufferPool.Put(raw.Payload)
+		if flush { // earlier packets may be waiting in buffer
+			err := writer.Flush()
+			if err != nil {
+				return fmt.Errorf("[%s] flush error %w", direction, err)
+			}
+		}
 	}
 	return nil
 }
@@ -322,7 +533,7 @@ func (handler *Handler) handlePacket(packet packets.Packet, direction packets.Di
 func (handler *Handler) handleProxy() (err error) {
 	if handler.Handshake.Protocol != packets.ProtocolVersion {
 		kick := packets.NewLoginKick(&packets.ChatMessage{Text: "Server version:" + packets.GameVersion.Name})
-		return handler.DownstreamW.WritePacket(kick, true)
+		return handler.writeStatePacket(packets.LOGIN, handler.DownstreamW, kick, true)
 	}
 
 	var login_start packets.LoginStartPacket
@@ -330,19 +541,28 @@ func (handler *Handler) handleProxy() (err error) {
 	if err != nil {
 		return
 	}
+	err = handler.dispatchStatePacket(packets.LOGIN, &login_start)
+	if err != nil {
+		return
+	}
 	if !ValidateNickname(login_start.Nickname) {
 		kick := packets.NewLoginKick(&packets.ChatMessage{Text: "Invalid nickname"})
-		return handler.DownstreamW.WritePacket(kick, true)
+		return handler.writeStatePacket(packets.LOGIN, handler.DownstreamW, kick, true)
 	}
 	handler.Nickname = login_start.Nickname
 
-	compression := &packets.LoginCompressionPacket{packets.VarInt(packets.CompressThreshold)}
-	err = handler.DownstreamW.WritePacket(compression, true)
-	if err != nil {
-		return
+	threshold := normalizeThreshold(handler.CompressThreshold)
+	if threshold >= 0 {
+		compression := &packets.LoginCompressionPacket{Threshold: packets.VarInt(threshold)}
+		err = handler.sendStatePacket(packets.LOGIN, handler.DownstreamW, compression, true)
+		if err != nil {
+			return
+		}
+		threshold = normalizeThreshold(int(compression.Threshold)) // filters may have changed it
 	}
-	handler.DownstreamW = packets.NewPacketWriter(handler.DownstreamC, packets.CompressThreshold)
-	handler.DownstreamR = packets.NewPacketReader(handler.DownstreamC, packets.CompressThreshold)
+	handler.downstream_out = newQueuedWriter(handler.DownstreamC, &handler.memory)
+	handler.DownstreamW = packets.NewPacketWriter(handler.downstream_out, threshold)
+	handler.DownstreamR = packets.NewPacketReaderSize(handler.DownstreamC, threshold, MaxServerboundPacketSize)
 
 	// key exchange is quite costly so we look for upstream here, kick non-whitelisted users, set capabilities etc
 	err = PreLoginHandler(handler)
@@ -351,16 +571,18 @@ func (handler *Handler) handleProxy() (err error) {
 		if kick, _ = err.(*packets.LoginKickPacket); kick == nil {
 			kick = packets.NewLoginKick(&packets.ChatMessage{Text: err.Error()})
 		}
-		return handler.DownstreamW.WritePacket(kick, true)
+		return handler.writeStatePacket(packets.LOGIN, handler.DownstreamW, kick, true)
 	}
 
 	if handler.Authenticator == nil {
 		handler.Log().Debug("Non-premium login")
 		handler.UUID = OfflinePlayerUUID(handler.Nickname)
+		handler.DownstreamR = packets.NewPacketReaderSize(bufio.NewReaderSize(handler.DownstreamC, 128*1024), threshold, MaxServerboundPacketSize)
+		handler.DownstreamW = packets.NewPacketWriter(bufio.NewWriterSize(handler.downstream_out, 128*1024), threshold)
 		err = ErrUnauthenticated
 	} else {
 		handler.Log().Debug("Checking minecraft account")
-		err = handler.establishEncryptionAsServer()
+		err = handler.establishEncryptionAsServer(threshold)
 	}
 
 	err = LoginHandler(handler, err)
@@ -369,10 +591,10 @@ func (handler *Handler) handleProxy() (err error) {
 		if kick, _ = err.(*packets.LoginKickPacket); kick == nil {
 			kick = packets.NewLoginKick(&packets.ChatMessage{Text: err.Error()})
 		}
-		return handler.DownstreamW.WritePacket(kick, true)
+		return handler.writeStatePacket(packets.LOGIN, handler.DownstreamW, kick, true)
 	}
 
-	err = handler.DownstreamW.WritePacket(&packets.LoginSuccessPacket{
+	err = handler.writeStatePacket(packets.LOGIN, handler.DownstreamW, &packets.LoginSuccessPacket{
 		UID:      handler.UUID,
 		Username: handler.Nickname,
 	}, true)
@@ -381,6 +603,7 @@ func (handler *Handler) handleProxy() (err error) {
 	}
 
 	// STATE IS PLAY FROM NOW
+	handler.setState(packets.PLAY)
 
 	UpstreamLock.Lock()
 	target, ok := UpstreamServerMap[handler.UpstreamName]
@@ -390,7 +613,7 @@ func (handler *Handler) handleProxy() (err error) {
 		return handler.DownstreamW.WritePacket(kick, true)
 	}
 
-	err = handler.connectUpstream(target.Name, target.Addr)
+	upstream_r, err := handler.connectUpstream(target)
 	if err != nil {
 		kick := packets.NewIngameKickTxt("Login error: upstream error: " + err.Error())
 		return handler.DownstreamW.WritePacket(kick, true)
@@ -403,73 +626,49 @@ func (handler *Handler) handleProxy() (err error) {
 	}
 	defer Players.Unregister(handler)
 
-	handler.downstream_packets = make(chan packets.Packet)
+	atomic.StoreInt64(&handler.last_packet, time.Now().Unix())
 	handler.downstream_tomb = &tomb.Tomb{}
-	go handler.read_packets(handler.DownstreamR, handler.downstream_packets, handler.downstream_tomb, packets.ServerBound)
+	go handler.pump(handler.DownstreamR, packets.ServerBound, handler.downstream_tomb)
+	handler.startUpstreamPump(upstream_r)
 
-	var last_packet_ts int64 = time.Now().Unix()
 	idle_timeout := make(chan struct{})
 
 	go func() {
 		const max_idle = 30 * time.Second
 		for {
 			time.Sleep(max_idle / 10)
-			if time.Now().Unix()-atomic.LoadInt64(&last_packet_ts) > int64(max_idle/time.Second) {
+			if time.Now().Unix()-atomic.LoadInt64(&handler.last_packet) > int64(max_idle/time.Second) {
 				close(idle_timeout)
 				break
 			}
 		}
 	}()
 
-	var upflush, downflush = time.Now(), time.Now()
-
 MainLoop:
 	for {
-		atomic.StoreInt64(&last_packet_ts, time.Now().Unix())
 		select {
 		case command := <-handler.commandChan:
+			handler.lock.Lock()
 			t1 := time.Now()
-			err = command.Execute(handler)
+			atomic.StoreInt64(&handler.last_packet, t1.Unix())
+			err = handler.executeCommand(command)
+			handler.upflush.reset() // commands always flush writers they use
+			handler.downflush.reset()
+			handler.lock.Unlock()
+			handler.flushTrace()
 			handler.Log().WithFields(logrus.Fields{
 				"command": command,
 				"time":    time.Since(t1),
 			}).WithError(err).Debug("Executed command")
-		case packet, ok := <-handler.downstream_packets:
-			if ok {
-				if handler.PacketTrace != nil {
-					err = handler.packetTrace(packet, packets.ServerBound)
-					if err != nil {
-						break MainLoop
-					}
-				}
-				atomic.AddUint64(&handler.DownstreamPacketsCount, 1)
-				// TODO: zamienic ten flushing na atomic licznik ile pakietow jest juz zparsowanych
-				flush := len(handler.downstream_packets) == 0 || time.Since(downflush) > 50*time.Millisecond
-				if flush {
-					downflush = time.Now()
-				}
-				err = handler.handlePacket(packet, packets.ServerBound, handler.UpstreamW, flush)
-			} else {
-				handler.downstream_tomb.Wait()
-				err = handler.downstream_tomb.Err()
+		case <-handler.downstream_tomb.Dead():
+			err = handler.downstream_tomb.Err()
+			if err == nil {
+				err = io.EOF
 			}
-		case packet, ok := <-handler.UpstreamPackets:
-			if ok {
-				if handler.PacketTrace != nil {
-					err = handler.packetTrace(packet, packets.ClientBound)
-					if err != nil {
-						break MainLoop
-					}
-				}
-				atomic.AddUint64(&handler.UpstreamPacketsCount, 1)
-				flush := len(handler.UpstreamPackets) == 0 || time.Since(upflush) > 50*time.Millisecond
-				if flush {
-					upflush = time.Now()
-				}
-				err = handler.handlePacket(packet, packets.ClientBound, handler.DownstreamW, flush)
-			} else {
-				handler.UpstreamTomb.Wait()
-				err = handler.UpstreamTomb.Err()
+		case <-handler.UpstreamTomb.Dead(): // replaced by ReconnectCommand, so it's always current upstream
+			err = handler.UpstreamTomb.Err()
+			if err == nil {
+				err = io.EOF
 			}
 		case <-idle_timeout:
 			handler.Log().Error("Idle timeout in handleProxy MainLoop")
@@ -480,33 +679,102 @@ MainLoop:
 		}
 	}
 
-	if err != nil && err != io.EOF {
-		err = fmt.Errorf("Error in handler's MainLoop: %w", err)
-		if handler.PacketTrace != nil {
-			err_pt := handler.PacketTrace.Close()
-			if err_pt != nil {
-				handler.Log().WithError(err).Error("PacketTrace close error")
-			}
+	// stop both pumps, they check their tombs under handler lock before touching writers
+	handler.lock.Lock()
+	handler.UpstreamTomb.Kill(nil)
+	handler.downstream_tomb.Kill(nil)
+	handler.lock.Unlock()
+	handler.memory.close() // upstream pump may be waiting for memory
+
+	var panic_err *PanicError
+	if errors.As(err, &panic_err) {
+		// don't leak panic details to player
+		kick := packets.NewIngameKickTxt(PanicKickMessage)
+		if kerr := handler.DownstreamW.WritePacket(kick, true); kerr != nil {
+			handler.Log().WithError(kerr).Debug("Panic kick write error")
 		}
 	}
 
-	// end both read_packets goroutines
-	handler.UpstreamTomb.Kill(nil)
-	handler.downstream_tomb.Kill(nil)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("Error in handler's MainLoop: %w", err)
+	}
 
 	handler.Log().Info("Closing play handler")
 	return err
 }
 
 func (handler *Handler) dispatchPacket(packet packets.Packet) error {
-	var bucket = packetFilters[int(packet.Direction()-1)*256+int(packet.PacketID())]
-	for _, proc := range bucket {
-		err := proc(handler, packet)
+	return handler.dispatchStatePacket(packets.PLAY, packet)
+}
+
+func (handler *Handler) dispatchStatePacket(state packets.ConnState, packet packets.Packet) error {
+	_, err := handler.dispatch(state, packet)
+	return err
+}
+
+// modified is false if packet was seen only by read-only filters
+func (handler *Handler) dispatch(state packets.ConnState, packet packets.Packet) (modified bool, err error) {
+	i := packetFilterIndex(state, packet.Direction(), packet.PacketID())
+	for _, filter := range packetFilters[i] {
+		modified = modified || !filter.readOnly
+		err = handler.runFilter(filter, packet)
 		if err != nil {
-			return err
+			return
 		}
 	}
-	return nil
+	for _, filter := range handler.handlerFilters()[i] {
+		modified = modified || !filter.readOnly
+		err = handler.runFilter(filter, packet)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// dispatches packet consumed by proxy itself, so it can't be dropped
+func (handler *Handler) inspectStatePacket(state packets.ConnState, packet packets.Packet) error {
+	err := handler.dispatchStatePacket(state, packet)
+	if err == ErrDropPacket {
+		err = nil
+	}
+	return err
+}
+
+// dispatches packet which proxy has to send outside of PLAY state, so it can't be dropped, and writes it
+func (handler *Handler) sendStatePacket(state packets.ConnState, writer packets.PacketWriter, packet packets.Packet, flush bool) error {
+	if err := handler.inspectStatePacket(state, packet); err != nil {
+		return err
+	}
+	return writer.WritePacket(packet, flush)
+}
+
+// dispatches packet generated by proxy outside of PLAY state and writes it if not dropped
+func (handler *Handler) writeStatePacket(state packets.ConnState, writer packets.PacketWriter, packet packets.Packet, flush bool) error {
+	err := handler.dispatchStatePacket(state, packet)
+	switch err {
+	case nil:
+		return writer.WritePacket(packet, flush)
+	case ErrDropPacket:
+		if flush {
+			return writer.Flush()
+		}
+		return nil
+	default:
+		return err
+	}
+}
+
+// Returns state of player connection. Upstream connection may be in different state, eg. during reconnect.
+func (handler *Handler) State() packets.ConnState {
+	return packets.ConnState(atomic.LoadInt32(&handler.state))
+}
+
+func (handler *Handler) setState(state packets.ConnState) {
+	atomic.StoreInt32(&handler.state, int32(state))
+	if w := handler.captureWriter(); w != nil {
+		handler.checkCapture(w, w.State(state))
+	}
 }
 
 func (handler *Handler) HasPermission(perm string) bool {
@@ -579,8 +847,3 @@ func (handler *Handler) InjectPackets(direction packets.Direction, raise error,
 		return fmt.Errorf("commandChan is full")
 	}
 }
-
-func (handler *Handler) packetTrace(packet packets.Packet, direction packets.Direction) error {
-	_, err := fmt.Fprintf(handler.PacketTrace, "%4.4f %s\n", time.Since(handler.t0).Seconds(), packets.ToString(packet, direction))
-	return err
-}
@@ -0,0 +1,286 @@
+package potoq_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Craftserve/potoq"
+	"github.com/Craftserve/potoq/packets"
+	"github.com/Craftserve/potoq/potoqtest"
+	"github.com/Craftserve/potoq/testserver"
+)
+
+// > per-handler filters
+
+func dropChat(handler *potoq.Handler, packet packets.Packet) error {
+	return potoq.ErrDropPacket
+}
+
+func expectChat(t *testing.T, server *potoqtest.UpstreamConn, want string) {
+	t.Helper()
+	var chat packets.ChatMessagePacketSB
+	server.Expect(t, &chat)
+	if chat.Message != want {
+		t.Fatalf("upstream got %q, expected %q", chat.Message, want)
+	}
+}
+
+func TestHandlerFilterOnlyForItsPlayer(t *testing.T) {
+	upstream := potoqtest.NewUpstream("filters")
+	defer upstream.Close()
+
+	var seen int32
+	filtered := potoqtest.Connect(t, upstream, "filtered", func(handler *potoq.Handler) {
+		handler.AddPacketFilter(&packets.ChatMessagePacketSB{}, func(h *potoq.Handler, packet packets.Packet) error {
+			if h != handler {
+				t.Errorf("filter of %s called for %s", handler.Nickname, h.Nickname)
+			}
+			atomic.AddInt32(&seen, 1)
+			return potoq.ErrDropPacket
+		})
+	})
+	defer filtered.Close()
+	filtered_server := upstream.Accept(t)
+	other := potoqtest.Connect(t, upstream, "other")
+	defer other.Close()
+	other_server := upstream.Accept(t)
+
+	filtered.Send(t, &packets.ChatMessagePacketSB{Message: "dropped"})
+	filtered.Send(t, &packets.KeepAlivePacketSB{ID: 1})
+	filtered_server.Expect(t, &packets.KeepAlivePacketSB{})
+	other.Send(t, &packets.ChatMessagePacketSB{Message: "passed"})
+	expectChat(t, other_server, "passed")
+	if n := atomic.LoadInt32(&seen); n != 1 {
+		t.Fatalf("filter called %d times", n)
+	}
+}
+
+// filter is removed from other goroutine while player is sending packets, no packet can be dropped after that
+func TestHandlerFilterRemove(t *testing.T) {
+	upstream := potoqtest.NewUpstream("filters")
+	defer upstream.Close()
+
+	var seen int32
+	var remove func()
+	player := potoqtest.Connect(t, upstream, "remover", func(handler *potoq.Handler) {
+		remove = handler.AddPacketFilter(&packets.ChatMessagePacketSB{}, func(*potoq.Handler, packets.Packet) error {
+			atomic.AddInt32(&seen, 1)
+			return potoq.ErrDropPacket
+		})
+	})
+	defer player.Close()
+	server := upstream.Accept(t)
+
+	removed := make(chan struct{})
+	go func() {
+		defer close(removed)
+		for atomic.LoadInt32(&seen) < 50 {
+			runtime.Gosched()
+		}
+		remove()
+	}()
+	const n = 500
+	for i := 0; i < n; i++ {
+		player.Send(t, &packets.ChatMessagePacketSB{Message: strconv.Itoa(i)})
+	}
+	<-removed
+	player.Send(t, &packets.ChatMessagePacketSB{Message: "end"})
+
+	var chat packets.ChatMessagePacketSB
+	server.Expect(t, &chat)
+	first := chat.Message
+	if first != "end" {
+		i, err := strconv.Atoi(first)
+		if err != nil || i < 50 {
+			t.Fatalf("first forwarded message %q", first)
+		}
+		for i++; i < n; i++ {
+			expectChat(t, server, strconv.Itoa(i))
+		}
+		expectChat(t, server, "end")
+	}
+	if dropped := atomic.LoadInt32(&seen); first != "end" && first != strconv.Itoa(int(dropped)) {
+		t.Fatalf("%d messages dropped, first forwarded %s", dropped, first)
+	}
+}
+
+func TestHandlerFiltersClearedOnReconnect(t *testing.T) {
+	lobby := potoqtest.NewUpstream("lobby")
+	defer lobby.Close()
+	game := potoqtest.NewUpstream("game")
+	defer game.Close()
+
+	player := potoqtest.Connect(t, lobby, "reconnecting", func(handler *potoq.Handler) {
+		handler.AddPacketFilter(&packets.ChatMessagePacketSB{}, dropChat)
+	})
+	defer player.Close()
+	lobby_server := lobby.Accept(t)
+	player.Send(t, &packets.ChatMessagePacketSB{Message: "dropped"})
+	player.Send(t, &packets.KeepAlivePacketSB{ID: 1})
+	lobby_server.Expect(t, &packets.KeepAlivePacketSB{})
+
+	player.Handler.PushCommand(&potoq.ReconnectCommand{Name: game.Name, Addr: game.Addr}, true)
+	game_server := game.Accept(t)
+	game_server.Send(t, testserver.NewJoinGame())
+	player.Expect(t, &packets.JoinGamePacketCB{})
+
+	player.Send(t, &packets.ChatMessagePacketSB{Message: "passed"})
+	expectChat(t, game_server, "passed")
+}
+
+// > panics
+
+type panicCommand struct{}
+
+func (panicCommand) Execute(*potoq.Handler) error {
+	panic("command")
+}
+
+// player has to be kicked with PanicKickMessage and handler has to end with *PanicError
+func expectPanicKick(t *testing.T, player *potoqtest.Player) *potoq.PanicError {
+	t.Helper()
+	var kick packets.KickPacketCB
+	player.Expect(t, &kick)
+	if !strings.Contains(kick.Message, potoq.PanicKickMessage) {
+		t.Fatalf("kick message %s", kick.Message)
+	}
+	player.ExpectClosed(t)
+	if err := player.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var perr *potoq.PanicError
+	if !errors.As(player.Handler.Err(), &perr) {
+		t.Fatalf("handler error %v", player.Handler.Err())
+	}
+	return perr
+}
+
+func TestFilterPanic(t *testing.T) {
+	upstream := potoqtest.NewUpstream("panics")
+	defer upstream.Close()
+	panicking := potoqtest.Connect(t, upstream, "panicking", func(handler *potoq.Handler) {
+		handler.AddPacketFilter(&packets.ChatMessagePacketSB{}, func(_ *potoq.Handler, packet packets.Packet) error {
+			panic("filter " + packet.(*packets.ChatMessagePacketSB).Message)
+		})
+	})
+	upstream.Accept(t)
+	other := potoqtest.Connect(t, upstream, "other")
+	defer other.Close()
+	other_server := upstream.Accept(t)
+
+	panicking.Send(t, &packets.ChatMessagePacketSB{Message: "boom"})
+	perr := expectPanicKick(t, panicking)
+	if _, ok := perr.Packet.(*packets.ChatMessagePacketSB); !ok || perr.Value != "filter boom" || perr.Command != nil {
+		t.Fatalf("panic error %#v", perr)
+	}
+
+	other.Send(t, &packets.ChatMessagePacketSB{Message: "still here"})
+	expectChat(t, other_server, "still here")
+}
+
+func TestFilterPanicLimit(t *testing.T) {
+	defer func(limit uint32) { potoq.FilterPanicLimit = limit }(potoq.FilterPanicLimit)
+	potoq.FilterPanicLimit = 2
+	// global filters can't be removed, message is unique so filters from previous runs (-count) don't panic
+	message := fmt.Sprintf("panic limit %d", time.Now().UnixNano())
+	potoq.RegisterPacketFilter(&packets.ChatMessagePacketSB{}, func(handler *potoq.Handler, packet packets.Packet) error {
+		if packet.(*packets.ChatMessagePacketSB).Message == message {
+			panic("limit")
+		}
+		return nil
+	})
+	upstream := potoqtest.NewUpstream("panics")
+	defer upstream.Close()
+
+	for _, nickname := range []string{"first", "second"} {
+		player := potoqtest.Connect(t, upstream, nickname)
+		upstream.Accept(t)
+		player.Send(t, &packets.ChatMessagePacketSB{Message: message})
+		expectPanicKick(t, player)
+	}
+
+	player := potoqtest.Connect(t, upstream, "third")
+	defer player.Close()
+	server := upstream.Accept(t)
+	player.Send(t, &packets.ChatMessagePacketSB{Message: message})
+	expectChat(t, server, message)
+}
+
+func TestCommandPanic(t *testing.T) {
+	upstream := potoqtest.NewUpstream("panics")
+	defer upstream.Close()
+	player := potoqtest.Connect(t, upstream, "commanded")
+	upstream.Accept(t)
+	other := potoqtest.Connect(t, upstream, "other")
+	defer other.Close()
+	other_server := upstream.Accept(t)
+
+	player.Handler.PushCommand(panicCommand{}, true)
+	perr := expectPanicKick(t, player)
+	if _, ok := perr.Command.(panicCommand); !ok || perr.Value != "command" || perr.Packet != nil {
+		t.Fatalf("panic error %#v", perr)
+	}
+
+	other.Send(t, &packets.ChatMessagePacketSB{Message: "still here"})
+	expectChat(t, other_server, "still here")
+}
+
+// > state filters
+
+func TestStateFilters(t *testing.T) {
+	upstream := potoqtest.NewUpstream("states")
+	defer upstream.Close()
+	upstream.Threshold = 64
+
+	var lock sync.Mutex
+	var seen []string
+	record := func(handler *potoq.Handler, packet packets.Packet) error {
+		lock.Lock()
+		seen = append(seen, fmt.Sprintf("%T", packet))
+		lock.Unlock()
+		return nil
+	}
+	player := potoqtest.Connect(t, upstream, "stateful", func(handler *potoq.Handler) {
+		handler.CompressThreshold = 256
+		handler.AddStatePacketFilter(packets.HANDSHAKING, &packets.HandshakePacket{}, func(_ *potoq.Handler, packet packets.Packet) error {
+			if handshake := packet.(*packets.HandshakePacket); strings.Contains(handshake.Host, "\x00") { // sent to upstream
+				handshake.Host = strings.Replace(handshake.Host, "localhost\x00", "backend.example\x00", 1)
+			}
+			return nil
+		})
+		for _, packet := range []packets.Packet{
+			&packets.LoginStartPacket{}, &packets.LoginCompressionPacket{}, &packets.LoginSuccessPacket{},
+		} {
+			handler.AddStatePacketFilter(packets.LOGIN, packet, record)
+		}
+	})
+	defer player.Close()
+	server := upstream.Accept(t)
+	if !strings.HasPrefix(server.Handshake.Host, "backend.example\x00127.0.0.1\x00") {
+		t.Fatalf("upstream handshake host %q", server.Handshake.Host)
+	}
+
+	// upstream's LoginSuccessPacket is dispatched before player's packets are forwarded
+	player.Send(t, &packets.KeepAlivePacketSB{ID: 1})
+	server.Expect(t, &packets.KeepAlivePacketSB{})
+	lock.Lock()
+	defer lock.Unlock()
+	want := []string{
+		"*packets.LoginStartPacket",       // from player
+		"*packets.LoginCompressionPacket", // to player
+		"*packets.LoginSuccessPacket",
+		"*packets.LoginStartPacket", // to upstream
+		"*packets.LoginCompressionPacket",
+		"*packets.LoginSuccessPacket",
+	}
+	if strings.Join(seen, " ") != strings.Join(want, " ") {
+		t.Fatalf("LOGIN filters saw %v", seen)
+	}
+}
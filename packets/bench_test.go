@@ -0,0 +1,149 @@
+package packets
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// > allocation benchmarks, run with: go test -bench . -benchmem ./packets
+
+// bytes.Reader starting again from beginning after reaching end
+type loopReader struct {
+	bytes.Reader
+	data []byte
+}
+
+func newLoopReader(data []byte) *loopReader {
+	l := &loopReader{data: data}
+	l.Reset(data)
+	return l
+}
+
+func (l *loopReader) Read(p []byte) (int, error) {
+	if l.Len() == 0 {
+		l.Reset(l.data)
+	}
+	return l.Reader.Read(p)
+}
+
+func (l *loopReader) ReadByte() (byte, error) {
+	if l.Len() == 0 {
+		l.Reset(l.data)
+	}
+	return l.Reader.ReadByte()
+}
+
+// hides io.ByteReader implementation
+type plainReader struct {
+	r io.Reader
+}
+
+func (p plainReader) Read(b []byte) (int, error) {
+	return p.r.Read(b)
+}
+
+var benchPosition = &PlayerPositionAndLookPacketCB{X: 1.5, Y: 64, Z: -3.25, Yaw: 90, Pitch: 10, Flags: 1, TeleportID: 1234}
+
+var benchHandshake = &HandshakePacket{Protocol: ProtocolVersion, Host: "mc.example.com", Port: 25565, NextState: 2}
+
+func BenchmarkReadVarInt(b *testing.B) {
+	r := newLoopReader([]byte{0xff, 0xff, 0x7f})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadVarInt(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadVarIntNoByteReader(b *testing.B) {
+	r := plainReader{newLoopReader([]byte{0xff, 0xff, 0x7f})}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadVarInt(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteVarInt(b *testing.B) {
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := WriteVarInt(&buf, 2097151); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkWriteStruct(b *testing.B, packet Packet) {
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := packet.Serialize(&buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkReadStruct(b *testing.B, packet Packet) {
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		b.Fatal(err)
+	}
+	r := newLoopReader(buf.Bytes())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := packet.Parse(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteStructPosition(b *testing.B)  { benchmarkWriteStruct(b, benchPosition) }
+func BenchmarkWriteStructHandshake(b *testing.B) { benchmarkWriteStruct(b, benchHandshake) }
+func BenchmarkReadStructPosition(b *testing.B)   { benchmarkReadStruct(b, benchPosition) }
+func BenchmarkReadStructHandshake(b *testing.B)  { benchmarkReadStruct(b, benchHandshake) }
+
+func BenchmarkWritePacket(b *testing.B) {
+	w := NewPacketWriter(ioutil.Discard, CompressThreshold)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := w.WritePacket(benchPosition, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// ReadPacket and Payload of compressed packet, as done for packets parsed by filters
+func BenchmarkReadCompressedPacket(b *testing.B) {
+	var buf bytes.Buffer
+	chat := &ChatMessagePacketCB{Message: strings.Repeat("compressed chat message ", 100)}
+	if err := NewPacketWriter(&buf, CompressThreshold).WritePacket(chat, false); err != nil {
+		b.Fatal(err)
+	}
+	r := NewPacketReader(newLoopReader(buf.Bytes()), CompressThreshold)
+	b.SetBytes(int64(len(chat.Message)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		raw, err := r.ReadPacket()
+		if err != nil {
+			b.Fatal(err)
+		}
+		payload, err := r.Payload()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err = io.Copy(ioutil.Discard, payload); err != nil {
+			b.Fatal(err)
+		}
+		BufferPool.Put(raw.Payload)
+	}
+}
@@ -0,0 +1,5897 @@
+// Code generated by packetgen; DO NOT EDIT.
+
+package packets
+
+import (
+	"github.com/Craftserve/potoq/packets/nbt"
+	"io"
+)
+
+func (packet *AcknowledgePlayerDiggingPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Block, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Status, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Successful, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *AcknowledgePlayerDiggingPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Block); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Status); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.Successful); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *AdvancementTabPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Action, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Action == 0 {
+		if packet.TabID, err = ReadIdentifier(br); err != nil {
+			return
+		}
+	} else {
+		packet.TabID = ""
+	}
+	return
+}
+
+func (packet *AdvancementTabPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Action); err != nil {
+		return
+	}
+	if packet.Action == 0 {
+		if err = WriteMinecraftString(writer, string(packet.TabID)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *AdvancementsPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if packet.Reset, err = ReadBool(br); err != nil {
+		return
+	}
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.Advancements = make([]Advancement, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 Advancement
+		if item0.Key, err = ReadIdentifier(br); err != nil {
+			return
+		}
+		if item0.HasParent, err = ReadBool(br); err != nil {
+			return
+		}
+		if item0.HasParent {
+			if item0.Parent, err = ReadIdentifier(br); err != nil {
+				return
+			}
+		} else {
+			item0.Parent = ""
+		}
+		if item0.HasDisplay, err = ReadBool(br); err != nil {
+			return
+		}
+		if item0.HasDisplay {
+			if item0.Display.Title, err = ReadMinecraftString(br, 262144); err != nil {
+				return
+			}
+			if item0.Display.Description, err = ReadMinecraftString(br, 262144); err != nil {
+				return
+			}
+			if item0.Display.Icon.Present, err = ReadBool(br); err != nil {
+				return
+			}
+			if item0.Display.Icon.Present {
+				if item0.Display.Icon.ItemID, err = ReadVarIntFrom(br); err != nil {
+					return
+				}
+			} else {
+				item0.Display.Icon.ItemID = 0
+			}
+			if item0.Display.Icon.Present {
+				if item0.Display.Icon.Count, err = ReadByte(br); err != nil {
+					return
+				}
+			} else {
+				item0.Display.Icon.Count = 0
+			}
+			if item0.Display.Icon.Present {
+				if item0.Display.Icon.NBT, err = readNBTField(br); err != nil {
+					return
+				}
+			} else {
+				item0.Display.Icon.NBT = nil
+			}
+			if item0.Display.FrameType, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+			if item0.Display.Flags, err = ReadInt(br); err != nil {
+				return
+			}
+			if item0.Display.Flags == 1 || item0.Display.Flags == 3 || item0.Display.Flags == 5 || item0.Display.Flags == 7 {
+				if item0.Display.BackgroundTexture, err = ReadIdentifier(br); err != nil {
+					return
+				}
+			} else {
+				item0.Display.BackgroundTexture = ""
+			}
+			if item0.Display.X, err = ReadFloat(br); err != nil {
+				return
+			}
+			if item0.Display.Y, err = ReadFloat(br); err != nil {
+				return
+			}
+		} else {
+			item0.Display = AdvancementDisplay{}
+		}
+		if item0.Criteria, err = ReadIdentifierArray(br); err != nil {
+			return
+		}
+		if n, err = readCount(br, "VarInt", 1024); err != nil {
+			return
+		}
+		item0.Requirements = make([][]string, 0, countCapacity(n))
+		for i1, n1 := 0, n; i1 < n1; i1++ {
+			var item1 []string
+			if n, err = readCount(br, "VarInt", 1024); err != nil {
+				return
+			}
+			item1 = make([]string, 0, countCapacity(n))
+			for i2, n2 := 0, n; i2 < n2; i2++ {
+				var item2 string
+				if item2, err = ReadMinecraftString(br, 32767); err != nil {
+					return
+				}
+				item1 = append(item1, item2)
+			}
+			item0.Requirements = append(item0.Requirements, item1)
+		}
+		packet.Advancements = append(packet.Advancements, item0)
+	}
+	if packet.Removed, err = ReadIdentifierArray(br); err != nil {
+		return
+	}
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.Progress = make([]AdvancementProgress, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 AdvancementProgress
+		if item0.Key, err = ReadIdentifier(br); err != nil {
+			return
+		}
+		if n, err = readCount(br, "VarInt", 1024); err != nil {
+			return
+		}
+		item0.Criteria = make([]CriterionProgress, 0, countCapacity(n))
+		for i1, n1 := 0, n; i1 < n1; i1++ {
+			var item1 CriterionProgress
+			if item1.Criterion, err = ReadIdentifier(br); err != nil {
+				return
+			}
+			if item1.Achieved, err = ReadBool(br); err != nil {
+				return
+			}
+			if item1.Achieved {
+				if item1.Date, err = ReadLong(br); err != nil {
+					return
+				}
+			} else {
+				item1.Date = 0
+			}
+			item0.Criteria = append(item0.Criteria, item1)
+		}
+		packet.Progress = append(packet.Progress, item0)
+	}
+	return
+}
+
+func (packet *AdvancementsPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBool(writer, packet.Reset); err != nil {
+		return
+	}
+	if err = writeCount(writer, "VarInt", 65536, len(packet.Advancements)); err != nil {
+		return
+	}
+	for i0 := range packet.Advancements {
+		item0 := &packet.Advancements[i0]
+		if err = WriteMinecraftString(writer, string(item0.Key)); err != nil {
+			return
+		}
+		if err = WriteBool(writer, item0.HasParent); err != nil {
+			return
+		}
+		if item0.HasParent {
+			if err = WriteMinecraftString(writer, string(item0.Parent)); err != nil {
+				return
+			}
+		}
+		if err = WriteBool(writer, item0.HasDisplay); err != nil {
+			return
+		}
+		if item0.HasDisplay {
+			if err = WriteMinecraftString(writer, item0.Display.Title); err != nil {
+				return
+			}
+			if err = WriteMinecraftString(writer, item0.Display.Description); err != nil {
+				return
+			}
+			if err = WriteBool(writer, item0.Display.Icon.Present); err != nil {
+				return
+			}
+			if item0.Display.Icon.Present {
+				if err = WriteVarInt(writer, item0.Display.Icon.ItemID); err != nil {
+					return
+				}
+			}
+			if item0.Display.Icon.Present {
+				if err = WriteByte(writer, item0.Display.Icon.Count); err != nil {
+					return
+				}
+			}
+			if item0.Display.Icon.Present {
+				if err = writeNBTField(writer, item0.Display.Icon.NBT); err != nil {
+					return
+				}
+			}
+			if err = WriteVarInt(writer, item0.Display.FrameType); err != nil {
+				return
+			}
+			if err = WriteInt(writer, item0.Display.Flags); err != nil {
+				return
+			}
+			if item0.Display.Flags == 1 || item0.Display.Flags == 3 || item0.Display.Flags == 5 || item0.Display.Flags == 7 {
+				if err = WriteMinecraftString(writer, string(item0.Display.BackgroundTexture)); err != nil {
+					return
+				}
+			}
+			if err = WriteFloat(writer, item0.Display.X); err != nil {
+				return
+			}
+			if err = WriteFloat(writer, item0.Display.Y); err != nil {
+				return
+			}
+		}
+		if err = WriteIdentifierArray(writer, item0.Criteria); err != nil {
+			return
+		}
+		if err = writeCount(writer, "VarInt", 1024, len(item0.Requirements)); err != nil {
+			return
+		}
+		for i1 := range item0.Requirements {
+			if err = writeCount(writer, "VarInt", 1024, len(item0.Requirements[i1])); err != nil {
+				return
+			}
+			for i2 := range item0.Requirements[i1] {
+				if err = WriteMinecraftString(writer, item0.Requirements[i1][i2]); err != nil {
+					return
+				}
+			}
+		}
+	}
+	if err = WriteIdentifierArray(writer, packet.Removed); err != nil {
+		return
+	}
+	if err = writeCount(writer, "VarInt", 65536, len(packet.Progress)); err != nil {
+		return
+	}
+	for i0 := range packet.Progress {
+		item0 := &packet.Progress[i0]
+		if err = WriteMinecraftString(writer, string(item0.Key)); err != nil {
+			return
+		}
+		if err = writeCount(writer, "VarInt", 1024, len(item0.Criteria)); err != nil {
+			return
+		}
+		for i1 := range item0.Criteria {
+			item1 := &item0.Criteria[i1]
+			if err = WriteMinecraftString(writer, string(item1.Criterion)); err != nil {
+				return
+			}
+			if err = WriteBool(writer, item1.Achieved); err != nil {
+				return
+			}
+			if item1.Achieved {
+				if err = WriteLong(writer, item1.Date); err != nil {
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+func (packet *AnimationPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Hand, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *AnimationPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Hand); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *AttachEntityPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.AttachedEntityID, err = readEntityIDField(br, "int32"); err != nil {
+		return
+	}
+	if packet.HoldingEntityID, err = readEntityIDField(br, "int32"); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *AttachEntityPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "int32", packet.AttachedEntityID); err != nil {
+		return
+	}
+	if err = writeEntityIDField(writer, "int32", packet.HoldingEntityID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *BlockActionPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.ActionID, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.ActionParam, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.BlockType, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *BlockActionPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.ActionID); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.ActionParam); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.BlockType); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *BlockBreakAnimationPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.DestroyStage, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *BlockBreakAnimationPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.DestroyStage); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *BlockChangePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.BlockID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *BlockChangePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.BlockID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *BlockEntityDataPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Action, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.Data, err = readNBTField(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *BlockEntityDataPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.Action); err != nil {
+		return
+	}
+	if err = writeNBTField(writer, packet.Data); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *BossBarPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if _, err = io.ReadFull(br, packet.UUID[:]); err != nil {
+		return
+	}
+	if packet.Action, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Action == 0 || packet.Action == 3 {
+		if packet.Title, err = ReadMinecraftString(br, 262144); err != nil {
+			return
+		}
+	} else {
+		packet.Title = ""
+	}
+	if packet.Action == 0 || packet.Action == 2 {
+		if packet.Health, err = ReadFloat(br); err != nil {
+			return
+		}
+	} else {
+		packet.Health = 0
+	}
+	if packet.Action == 0 || packet.Action == 4 {
+		if packet.Color, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Color = 0
+	}
+	if packet.Action == 0 || packet.Action == 4 {
+		if packet.Division, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Division = 0
+	}
+	if packet.Action == 0 || packet.Action == 5 {
+		if packet.Flags, err = ReadUnsignedByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.Flags = 0
+	}
+	return
+}
+
+func (packet *BossBarPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if _, err = writer.Write(packet.UUID[:]); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Action); err != nil {
+		return
+	}
+	if packet.Action == 0 || packet.Action == 3 {
+		if err = WriteMinecraftString(writer, packet.Title); err != nil {
+			return
+		}
+	}
+	if packet.Action == 0 || packet.Action == 2 {
+		if err = WriteFloat(writer, packet.Health); err != nil {
+			return
+		}
+	}
+	if packet.Action == 0 || packet.Action == 4 {
+		if err = WriteVarInt(writer, packet.Color); err != nil {
+			return
+		}
+	}
+	if packet.Action == 0 || packet.Action == 4 {
+		if err = WriteVarInt(writer, packet.Division); err != nil {
+			return
+		}
+	}
+	if packet.Action == 0 || packet.Action == 5 {
+		if err = WriteUnsignedByte(writer, packet.Flags); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *CameraPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CameraPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.ID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ChatMessagePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Message, err = ReadMinecraftString(br, 32767); err != nil {
+		return
+	}
+	if packet.Position, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if _, err = io.ReadFull(br, packet.Sender[:]); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ChatMessagePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Message); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.Position); err != nil {
+		return
+	}
+	if _, err = writer.Write(packet.Sender[:]); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ChatMessagePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Message, err = ReadMinecraftString(br, 256); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ChatMessagePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Message); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ChunkDataPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if packet.ChunkX, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.ChunkZ, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.FullChunk, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.PrimaryBitMask, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Heightmaps, err = readNBTField(br); err != nil {
+		return
+	}
+	if packet.FullChunk {
+		if n, err = readCount(br, "VarInt", 1024); err != nil {
+			return
+		}
+		packet.Biomes = make([]VarInt, 0, countCapacity(n))
+		for i0, n0 := 0, n; i0 < n0; i0++ {
+			var item0 VarInt
+			if item0, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+			packet.Biomes = append(packet.Biomes, item0)
+		}
+	} else {
+		packet.Biomes = nil
+	}
+	if packet.Data, err = readBytesField(br, "VarInt", 2097151); err != nil {
+		return
+	}
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.BlockEntities = make([]nbt.TagCompound, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 nbt.TagCompound
+		if item0, err = readNBTField(br); err != nil {
+			return
+		}
+		packet.BlockEntities = append(packet.BlockEntities, item0)
+	}
+	return
+}
+
+func (packet *ChunkDataPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteInt(writer, packet.ChunkX); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.ChunkZ); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.FullChunk); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.PrimaryBitMask); err != nil {
+		return
+	}
+	if err = writeNBTField(writer, packet.Heightmaps); err != nil {
+		return
+	}
+	if packet.FullChunk {
+		if err = writeCount(writer, "VarInt", 1024, len(packet.Biomes)); err != nil {
+			return
+		}
+		for i0 := range packet.Biomes {
+			if err = WriteVarInt(writer, packet.Biomes[i0]); err != nil {
+				return
+			}
+		}
+	}
+	if err = writeBytesField(writer, "VarInt", 2097151, packet.Data); err != nil {
+		return
+	}
+	if err = writeCount(writer, "VarInt", 65536, len(packet.BlockEntities)); err != nil {
+		return
+	}
+	for i0 := range packet.BlockEntities {
+		if err = writeNBTField(writer, packet.BlockEntities[i0]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *ClickWindowButtonPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.ButtonID, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ClickWindowButtonPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.ButtonID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ClickWindowPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.Slot, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.Button, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.ActionNumber, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.Mode, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.ClickedItem.Present, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.ClickedItem.Present {
+		if packet.ClickedItem.ItemID, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.ClickedItem.ItemID = 0
+	}
+	if packet.ClickedItem.Present {
+		if packet.ClickedItem.Count, err = ReadByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.ClickedItem.Count = 0
+	}
+	if packet.ClickedItem.Present {
+		if packet.ClickedItem.NBT, err = readNBTField(br); err != nil {
+			return
+		}
+	} else {
+		packet.ClickedItem.NBT = nil
+	}
+	return
+}
+
+func (packet *ClickWindowPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteUnsignedByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.Slot); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Button); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.ActionNumber); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Mode); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.ClickedItem.Present); err != nil {
+		return
+	}
+	if packet.ClickedItem.Present {
+		if err = WriteVarInt(writer, packet.ClickedItem.ItemID); err != nil {
+			return
+		}
+	}
+	if packet.ClickedItem.Present {
+		if err = WriteByte(writer, packet.ClickedItem.Count); err != nil {
+			return
+		}
+	}
+	if packet.ClickedItem.Present {
+		if err = writeNBTField(writer, packet.ClickedItem.NBT); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *ClientSettingsPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Locale, err = ReadMinecraftString(br, 16); err != nil {
+		return
+	}
+	if packet.ViewDistance, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.ChatMode, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.ChatColors, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.SkinParts, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.Hand, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ClientSettingsPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Locale); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.ViewDistance); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.ChatMode); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.ChatColors); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.SkinParts); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Hand); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ClientStatusPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Action, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ClientStatusPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Action); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CloseWindowPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CloseWindowPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteUnsignedByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CloseWindowPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CloseWindowPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteUnsignedByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CollectItemPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.CollectedEntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.CollectorEntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.PickupItemCount, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CollectItemPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.CollectedEntityID); err != nil {
+		return
+	}
+	if err = writeEntityIDField(writer, "VarInt", packet.CollectorEntityID); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.PickupItemCount); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CombatEventPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Event, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Event == 1 {
+		if packet.Duration, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Duration = 0
+	}
+	if packet.Event == 2 {
+		if packet.PlayerID, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.PlayerID = 0
+	}
+	if packet.Event == 1 || packet.Event == 2 {
+		if packet.EntityID, err = readEntityIDField(br, "int32"); err != nil {
+			return
+		}
+	} else {
+		packet.EntityID = 0
+	}
+	if packet.Event == 2 {
+		if packet.Message, err = ReadMinecraftString(br, 262144); err != nil {
+			return
+		}
+	} else {
+		packet.Message = ""
+	}
+	return
+}
+
+func (packet *CombatEventPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Event); err != nil {
+		return
+	}
+	if packet.Event == 1 {
+		if err = WriteVarInt(writer, packet.Duration); err != nil {
+			return
+		}
+	}
+	if packet.Event == 2 {
+		if err = WriteVarInt(writer, packet.PlayerID); err != nil {
+			return
+		}
+	}
+	if packet.Event == 1 || packet.Event == 2 {
+		if err = writeEntityIDField(writer, "int32", packet.EntityID); err != nil {
+			return
+		}
+	}
+	if packet.Event == 2 {
+		if err = WriteMinecraftString(writer, packet.Message); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *CraftRecipeRequestPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.Recipe, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	if packet.MakeAll, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CraftRecipeRequestPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, string(packet.Recipe)); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.MakeAll); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CraftRecipeResponsePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.Recipe, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CraftRecipeResponsePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, string(packet.Recipe)); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *CreativeInventoryActionPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Slot, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.ClickedItem.Present, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.ClickedItem.Present {
+		if packet.ClickedItem.ItemID, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.ClickedItem.ItemID = 0
+	}
+	if packet.ClickedItem.Present {
+		if packet.ClickedItem.Count, err = ReadByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.ClickedItem.Count = 0
+	}
+	if packet.ClickedItem.Present {
+		if packet.ClickedItem.NBT, err = readNBTField(br); err != nil {
+			return
+		}
+	} else {
+		packet.ClickedItem.NBT = nil
+	}
+	return
+}
+
+func (packet *CreativeInventoryActionPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteShort(writer, packet.Slot); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.ClickedItem.Present); err != nil {
+		return
+	}
+	if packet.ClickedItem.Present {
+		if err = WriteVarInt(writer, packet.ClickedItem.ItemID); err != nil {
+			return
+		}
+	}
+	if packet.ClickedItem.Present {
+		if err = WriteByte(writer, packet.ClickedItem.Count); err != nil {
+			return
+		}
+	}
+	if packet.ClickedItem.Present {
+		if err = writeNBTField(writer, packet.ClickedItem.NBT); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *DestroyEntitiesPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.EntityIDs = make([]EntityID, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 EntityID
+		if item0, err = readEntityIDField(br, "VarInt"); err != nil {
+			return
+		}
+		packet.EntityIDs = append(packet.EntityIDs, item0)
+	}
+	return
+}
+
+func (packet *DestroyEntitiesPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeCount(writer, "VarInt", 65536, len(packet.EntityIDs)); err != nil {
+		return
+	}
+	for i0 := range packet.EntityIDs {
+		if err = writeEntityIDField(writer, "VarInt", packet.EntityIDs[i0]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *DisplayScoreboardPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Position, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.ScoreName, err = ReadMinecraftString(br, 16); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *DisplayScoreboardPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.Position); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.ScoreName); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EditBookPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.NewBook.Present, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.NewBook.Present {
+		if packet.NewBook.ItemID, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.NewBook.ItemID = 0
+	}
+	if packet.NewBook.Present {
+		if packet.NewBook.Count, err = ReadByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.NewBook.Count = 0
+	}
+	if packet.NewBook.Present {
+		if packet.NewBook.NBT, err = readNBTField(br); err != nil {
+			return
+		}
+	} else {
+		packet.NewBook.NBT = nil
+	}
+	if packet.IsSigning, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.Hand, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EditBookPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBool(writer, packet.NewBook.Present); err != nil {
+		return
+	}
+	if packet.NewBook.Present {
+		if err = WriteVarInt(writer, packet.NewBook.ItemID); err != nil {
+			return
+		}
+	}
+	if packet.NewBook.Present {
+		if err = WriteByte(writer, packet.NewBook.Count); err != nil {
+			return
+		}
+	}
+	if packet.NewBook.Present {
+		if err = writeNBTField(writer, packet.NewBook.NBT); err != nil {
+			return
+		}
+	}
+	if err = WriteBool(writer, packet.IsSigning); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Hand); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EffectPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EffectID, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Data, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.DisableRelativeVolume, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EffectPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteInt(writer, packet.EffectID); err != nil {
+		return
+	}
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.Data); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.DisableRelativeVolume); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EncryptionRequestPacket) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ServerID, err = ReadMinecraftString(br, 20); err != nil {
+		return
+	}
+	if packet.PublicKey, err = readBytesField(br, "VarInt", 2048); err != nil {
+		return
+	}
+	if packet.Token, err = readBytesField(br, "VarInt", 256); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EncryptionRequestPacket) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.ServerID); err != nil {
+		return
+	}
+	if err = writeBytesField(writer, "VarInt", 2048, packet.PublicKey); err != nil {
+		return
+	}
+	if err = writeBytesField(writer, "VarInt", 256, packet.Token); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EncryptionResponsePacket) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Secret, err = readBytesField(br, "VarInt", 1024); err != nil {
+		return
+	}
+	if packet.Token, err = readBytesField(br, "VarInt", 256); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EncryptionResponsePacket) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeBytesField(writer, "VarInt", 1024, packet.Secret); err != nil {
+		return
+	}
+	if err = writeBytesField(writer, "VarInt", 256, packet.Token); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityActionPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.ActionID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.JumpBoost, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityActionPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.ActionID); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.JumpBoost); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityAnimationPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.Animation, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityAnimationPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.Animation); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityEffectPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.EffectID, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.Amplifier, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.Duration, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Flags, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityEffectPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.EffectID); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Amplifier); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Duration); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Flags); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityHeadLookPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.HeadYaw, err = ReadAngle(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityHeadLookPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.HeadYaw); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityMovementPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityMovementPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityPositionAndRotationPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.DeltaX, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.DeltaY, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.DeltaZ, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.OnGround, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityPositionAndRotationPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.DeltaX); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.DeltaY); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.DeltaZ); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Pitch); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.OnGround); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityPositionPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.DeltaX, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.DeltaY, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.DeltaZ, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.OnGround, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityPositionPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.DeltaX); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.DeltaY); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.DeltaZ); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.OnGround); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityPropertiesPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if n, err = readCount(br, "int32", 1024); err != nil {
+		return
+	}
+	packet.Properties = make([]EntityProperty, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 EntityProperty
+		if item0.Key, err = ReadIdentifier(br); err != nil {
+			return
+		}
+		if item0.Value, err = ReadDouble(br); err != nil {
+			return
+		}
+		if n, err = readCount(br, "VarInt", 1024); err != nil {
+			return
+		}
+		item0.Modifiers = make([]AttributeModifier, 0, countCapacity(n))
+		for i1, n1 := 0, n; i1 < n1; i1++ {
+			var item1 AttributeModifier
+			if _, err = io.ReadFull(br, item1.UUID[:]); err != nil {
+				return
+			}
+			if item1.Amount, err = ReadDouble(br); err != nil {
+				return
+			}
+			if item1.Operation, err = ReadByte(br); err != nil {
+				return
+			}
+			item0.Modifiers = append(item0.Modifiers, item1)
+		}
+		packet.Properties = append(packet.Properties, item0)
+	}
+	return
+}
+
+func (packet *EntityPropertiesPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = writeCount(writer, "int32", 1024, len(packet.Properties)); err != nil {
+		return
+	}
+	for i0 := range packet.Properties {
+		item0 := &packet.Properties[i0]
+		if err = WriteMinecraftString(writer, string(item0.Key)); err != nil {
+			return
+		}
+		if err = WriteDouble(writer, item0.Value); err != nil {
+			return
+		}
+		if err = writeCount(writer, "VarInt", 1024, len(item0.Modifiers)); err != nil {
+			return
+		}
+		for i1 := range item0.Modifiers {
+			item1 := &item0.Modifiers[i1]
+			if _, err = writer.Write(item1.UUID[:]); err != nil {
+				return
+			}
+			if err = WriteDouble(writer, item1.Amount); err != nil {
+				return
+			}
+			if err = WriteByte(writer, item1.Operation); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (packet *EntityRotationPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.OnGround, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityRotationPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Pitch); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.OnGround); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntitySoundEffectPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.SoundID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Category, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.Volume, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadFloat(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntitySoundEffectPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.SoundID); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Category); err != nil {
+		return
+	}
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Volume); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Pitch); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityStatusPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "int32"); err != nil {
+		return
+	}
+	if packet.Status, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityStatusPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "int32", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Status); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityTeleportPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.OnGround, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityTeleportPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Pitch); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.OnGround); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityVelocityPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.VelocityX, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.VelocityY, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.VelocityZ, err = ReadShort(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *EntityVelocityPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.VelocityX); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.VelocityY); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.VelocityZ); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ExplosionPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if packet.X, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Y, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Z, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Strength, err = ReadFloat(br); err != nil {
+		return
+	}
+	if n, err = readCount(br, "int32", 65536); err != nil {
+		return
+	}
+	packet.Records = make([]ExplosionRecord, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 ExplosionRecord
+		if item0.X, err = ReadByte(br); err != nil {
+			return
+		}
+		if item0.Y, err = ReadByte(br); err != nil {
+			return
+		}
+		if item0.Z, err = ReadByte(br); err != nil {
+			return
+		}
+		packet.Records = append(packet.Records, item0)
+	}
+	if packet.PlayerMotionX, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.PlayerMotionY, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.PlayerMotionZ, err = ReadFloat(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ExplosionPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteFloat(writer, packet.X); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Y); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Z); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Strength); err != nil {
+		return
+	}
+	if err = writeCount(writer, "int32", 65536, len(packet.Records)); err != nil {
+		return
+	}
+	for i0 := range packet.Records {
+		item0 := &packet.Records[i0]
+		if err = WriteByte(writer, item0.X); err != nil {
+			return
+		}
+		if err = WriteByte(writer, item0.Y); err != nil {
+			return
+		}
+		if err = WriteByte(writer, item0.Z); err != nil {
+			return
+		}
+	}
+	if err = WriteFloat(writer, packet.PlayerMotionX); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.PlayerMotionY); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.PlayerMotionZ); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *FacePlayerPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.FeetEyes, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Target.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Target.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Target.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.IsEntity, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.IsEntity {
+		if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+			return
+		}
+	} else {
+		packet.EntityID = 0
+	}
+	if packet.IsEntity {
+		if packet.EntityFeetEyes, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.EntityFeetEyes = 0
+	}
+	return
+}
+
+func (packet *FacePlayerPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.FeetEyes); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Target.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Target.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Target.Z); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.IsEntity); err != nil {
+		return
+	}
+	if packet.IsEntity {
+		if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+			return
+		}
+	}
+	if packet.IsEntity {
+		if err = WriteVarInt(writer, packet.EntityFeetEyes); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *GameStateChangePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Reason, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.Value, err = ReadFloat(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *GameStateChangePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteUnsignedByte(writer, packet.Reason); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Value); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *GenerateStructurePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Levels, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.KeepJigsaws, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *GenerateStructurePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Levels); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.KeepJigsaws); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *HandshakePacket) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Protocol, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Host, err = ReadMinecraftString(br, 255); err != nil {
+		return
+	}
+	if packet.Port, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.NextState, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *HandshakePacket) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Protocol); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Host); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.Port); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.NextState); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *HeldItemChangePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Slot, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *HeldItemChangePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.Slot); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *HeldItemChangePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Slot, err = ReadShort(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *HeldItemChangePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteShort(writer, packet.Slot); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *InteractEntityPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.Type, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Type == 2 {
+		if packet.TargetX, err = ReadFloat(br); err != nil {
+			return
+		}
+	} else {
+		packet.TargetX = 0
+	}
+	if packet.Type == 2 {
+		if packet.TargetY, err = ReadFloat(br); err != nil {
+			return
+		}
+	} else {
+		packet.TargetY = 0
+	}
+	if packet.Type == 2 {
+		if packet.TargetZ, err = ReadFloat(br); err != nil {
+			return
+		}
+	} else {
+		packet.TargetZ = 0
+	}
+	if packet.Type == 0 || packet.Type == 2 {
+		if packet.Hand, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Hand = 0
+	}
+	if packet.Sneaking, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *InteractEntityPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Type); err != nil {
+		return
+	}
+	if packet.Type == 2 {
+		if err = WriteFloat(writer, packet.TargetX); err != nil {
+			return
+		}
+	}
+	if packet.Type == 2 {
+		if err = WriteFloat(writer, packet.TargetY); err != nil {
+			return
+		}
+	}
+	if packet.Type == 2 {
+		if err = WriteFloat(writer, packet.TargetZ); err != nil {
+			return
+		}
+	}
+	if packet.Type == 0 || packet.Type == 2 {
+		if err = WriteVarInt(writer, packet.Hand); err != nil {
+			return
+		}
+	}
+	if err = WriteBool(writer, packet.Sneaking); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *JoinGamePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.PlayerEntity, err = readEntityIDField(br, "int32"); err != nil {
+		return
+	}
+	if packet.IsHardcore, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.GameMode, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.PreviousGameMode, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.AllWorldNames, err = ReadIdentifierArray(br); err != nil {
+		return
+	}
+	if packet.DimensionCodec, err = readNBTField(br); err != nil {
+		return
+	}
+	if packet.Dimension, err = readNBTField(br); err != nil {
+		return
+	}
+	if packet.DimensionId, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	if packet.HashedSeed, err = ReadLong(br); err != nil {
+		return
+	}
+	if packet.MaxPlayers, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.ViewDistance, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.ReducedDebugInfo, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.EnableRespawnScreen, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.IsDebug, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.IsFlat, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *JoinGamePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "int32", packet.PlayerEntity); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.IsHardcore); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.GameMode); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.PreviousGameMode); err != nil {
+		return
+	}
+	if err = WriteIdentifierArray(writer, packet.AllWorldNames); err != nil {
+		return
+	}
+	if err = writeNBTField(writer, packet.DimensionCodec); err != nil {
+		return
+	}
+	if err = writeNBTField(writer, packet.Dimension); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, string(packet.DimensionId)); err != nil {
+		return
+	}
+	if err = WriteLong(writer, packet.HashedSeed); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.MaxPlayers); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.ViewDistance); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.ReducedDebugInfo); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.EnableRespawnScreen); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.IsDebug); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.IsFlat); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *KeepAlivePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ID, err = ReadLong(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *KeepAlivePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteLong(writer, packet.ID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *KeepAlivePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ID, err = ReadLong(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *KeepAlivePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteLong(writer, packet.ID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *KickPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Message, err = ReadMinecraftString(br, 262144); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *KickPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Message); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LockDifficultyPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Locked, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LockDifficultyPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBool(writer, packet.Locked); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LoginCompressionPacket) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Threshold, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LoginCompressionPacket) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Threshold); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LoginKickPacket) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Message, err = ReadMinecraftString(br, 256); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LoginKickPacket) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Message); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LoginStartPacket) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Nickname, err = ReadMinecraftString(br, 16); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LoginStartPacket) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Nickname); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LoginSuccessPacket) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if _, err = io.ReadFull(br, packet.UID[:]); err != nil {
+		return
+	}
+	if packet.Username, err = ReadMinecraftString(br, 16); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *LoginSuccessPacket) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if _, err = writer.Write(packet.UID[:]); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Username); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *MapDataPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var present bool
+	var n int
+	if packet.MapID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Scale, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.TrackingPosition, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.Locked, err = ReadBool(br); err != nil {
+		return
+	}
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.Icons = make([]MapIcon, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 MapIcon
+		if item0.Type, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+		if item0.X, err = ReadByte(br); err != nil {
+			return
+		}
+		if item0.Z, err = ReadByte(br); err != nil {
+			return
+		}
+		if item0.Direction, err = ReadByte(br); err != nil {
+			return
+		}
+		if present, err = ReadBool(br); err != nil {
+			return
+		}
+		if present {
+			if item0.DisplayName, err = ReadMinecraftString(br, 262144); err != nil {
+				return
+			}
+		} else {
+			item0.DisplayName = ""
+		}
+		packet.Icons = append(packet.Icons, item0)
+	}
+	if packet.Columns, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.Columns != 0 {
+		if packet.Rows, err = ReadUnsignedByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.Rows = 0
+	}
+	if packet.Columns != 0 {
+		if packet.X, err = ReadByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.X = 0
+	}
+	if packet.Columns != 0 {
+		if packet.Z, err = ReadByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.Z = 0
+	}
+	if packet.Columns != 0 {
+		if packet.Data, err = readBytesField(br, "VarInt", 16384); err != nil {
+			return
+		}
+	} else {
+		packet.Data = nil
+	}
+	return
+}
+
+func (packet *MapDataPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	var present bool
+	if err = WriteVarInt(writer, packet.MapID); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Scale); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.TrackingPosition); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.Locked); err != nil {
+		return
+	}
+	if err = writeCount(writer, "VarInt", 65536, len(packet.Icons)); err != nil {
+		return
+	}
+	for i0 := range packet.Icons {
+		item0 := &packet.Icons[i0]
+		if err = WriteVarInt(writer, item0.Type); err != nil {
+			return
+		}
+		if err = WriteByte(writer, item0.X); err != nil {
+			return
+		}
+		if err = WriteByte(writer, item0.Z); err != nil {
+			return
+		}
+		if err = WriteByte(writer, item0.Direction); err != nil {
+			return
+		}
+		present = item0.DisplayName != ""
+		if err = WriteBool(writer, present); err != nil {
+			return
+		}
+		if present {
+			if err = WriteMinecraftString(writer, item0.DisplayName); err != nil {
+				return
+			}
+		}
+	}
+	if err = WriteUnsignedByte(writer, packet.Columns); err != nil {
+		return
+	}
+	if packet.Columns != 0 {
+		if err = WriteUnsignedByte(writer, packet.Rows); err != nil {
+			return
+		}
+	}
+	if packet.Columns != 0 {
+		if err = WriteByte(writer, packet.X); err != nil {
+			return
+		}
+	}
+	if packet.Columns != 0 {
+		if err = WriteByte(writer, packet.Z); err != nil {
+			return
+		}
+	}
+	if packet.Columns != 0 {
+		if err = writeBytesField(writer, "VarInt", 16384, packet.Data); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *MultiBlockChangePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if packet.ChunkSectionPosition, err = ReadSectionPos(br); err != nil {
+		return
+	}
+	if packet.NoTrustEdges, err = ReadBool(br); err != nil {
+		return
+	}
+	if n, err = readCount(br, "VarInt", 4096); err != nil {
+		return
+	}
+	packet.Blocks = make([]VarLong, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 VarLong
+		if item0, err = ReadVarLong(br); err != nil {
+			return
+		}
+		packet.Blocks = append(packet.Blocks, item0)
+	}
+	return
+}
+
+func (packet *MultiBlockChangePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteSectionPos(writer, packet.ChunkSectionPosition); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.NoTrustEdges); err != nil {
+		return
+	}
+	if err = writeCount(writer, "VarInt", 4096, len(packet.Blocks)); err != nil {
+		return
+	}
+	for i0 := range packet.Blocks {
+		if err = WriteVarLong(writer, packet.Blocks[i0]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *NBTQueryResponsePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.TransactionID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.NBT, err = readNBTField(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *NBTQueryResponsePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.TransactionID); err != nil {
+		return
+	}
+	if err = writeNBTField(writer, packet.NBT); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *NameItemPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ItemName, err = ReadMinecraftString(br, 32767); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *NameItemPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.ItemName); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *NamedSoundEffectPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.SoundName, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	if packet.Category, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.X, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.Y, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.Z, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.Volume, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadFloat(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *NamedSoundEffectPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, string(packet.SoundName)); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Category); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.X); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.Y); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.Z); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Volume); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Pitch); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *OpenBookPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Hand, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *OpenBookPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Hand); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *OpenHorseWindowPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.SlotCount, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.EntityID, err = readEntityIDField(br, "int32"); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *OpenHorseWindowPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.SlotCount); err != nil {
+		return
+	}
+	if err = writeEntityIDField(writer, "int32", packet.EntityID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *OpenSignEditorPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *OpenSignEditorPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *OpenWindowPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.WindowType, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.WindowTitle, err = ReadMinecraftString(br, 262144); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *OpenWindowPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.WindowType); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.WindowTitle); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ParticlePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ID, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.LongDistance, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.OffsetX, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.OffsetY, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.OffsetZ, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Speed, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.ParticleCount, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.ID == 3 || packet.ID == 23 {
+		if packet.Data.BlockState, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Data.BlockState = 0
+	}
+	if packet.ID == 14 {
+		if packet.Data.Red, err = ReadFloat(br); err != nil {
+			return
+		}
+	} else {
+		packet.Data.Red = 0
+	}
+	if packet.ID == 14 {
+		if packet.Data.Green, err = ReadFloat(br); err != nil {
+			return
+		}
+	} else {
+		packet.Data.Green = 0
+	}
+	if packet.ID == 14 {
+		if packet.Data.Blue, err = ReadFloat(br); err != nil {
+			return
+		}
+	} else {
+		packet.Data.Blue = 0
+	}
+	if packet.ID == 14 {
+		if packet.Data.Scale, err = ReadFloat(br); err != nil {
+			return
+		}
+	} else {
+		packet.Data.Scale = 0
+	}
+	if packet.ID == 34 {
+		if packet.Data.Item.Present, err = ReadBool(br); err != nil {
+			return
+		}
+		if packet.Data.Item.Present {
+			if packet.Data.Item.ItemID, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+		} else {
+			packet.Data.Item.ItemID = 0
+		}
+		if packet.Data.Item.Present {
+			if packet.Data.Item.Count, err = ReadByte(br); err != nil {
+				return
+			}
+		} else {
+			packet.Data.Item.Count = 0
+		}
+		if packet.Data.Item.Present {
+			if packet.Data.Item.NBT, err = readNBTField(br); err != nil {
+				return
+			}
+		} else {
+			packet.Data.Item.NBT = nil
+		}
+	} else {
+		packet.Data.Item = Slot{}
+	}
+	return
+}
+
+func (packet *ParticlePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteInt(writer, packet.ID); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.LongDistance); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.OffsetX); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.OffsetY); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.OffsetZ); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Speed); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.ParticleCount); err != nil {
+		return
+	}
+	if packet.ID == 3 || packet.ID == 23 {
+		if err = WriteVarInt(writer, packet.Data.BlockState); err != nil {
+			return
+		}
+	}
+	if packet.ID == 14 {
+		if err = WriteFloat(writer, packet.Data.Red); err != nil {
+			return
+		}
+	}
+	if packet.ID == 14 {
+		if err = WriteFloat(writer, packet.Data.Green); err != nil {
+			return
+		}
+	}
+	if packet.ID == 14 {
+		if err = WriteFloat(writer, packet.Data.Blue); err != nil {
+			return
+		}
+	}
+	if packet.ID == 14 {
+		if err = WriteFloat(writer, packet.Data.Scale); err != nil {
+			return
+		}
+	}
+	if packet.ID == 34 {
+		if err = WriteBool(writer, packet.Data.Item.Present); err != nil {
+			return
+		}
+		if packet.Data.Item.Present {
+			if err = WriteVarInt(writer, packet.Data.Item.ItemID); err != nil {
+				return
+			}
+		}
+		if packet.Data.Item.Present {
+			if err = WriteByte(writer, packet.Data.Item.Count); err != nil {
+				return
+			}
+		}
+		if packet.Data.Item.Present {
+			if err = writeNBTField(writer, packet.Data.Item.NBT); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (packet *PickItemPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.SlotToUse, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PickItemPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.SlotToUse); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerAbilitiesPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Flags, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.FlyingSpeed, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.FieldOfViewModifier, err = ReadFloat(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerAbilitiesPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.Flags); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.FlyingSpeed); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.FieldOfViewModifier); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerAbilitiesPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Flags, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerAbilitiesPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.Flags); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerBlockPlacementPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Hand, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Face, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.CursorX, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.CursorY, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.CursorZ, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.InsideBlock, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerBlockPlacementPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Hand); err != nil {
+		return
+	}
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Face); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.CursorX); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.CursorY); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.CursorZ); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.InsideBlock); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerDiggingPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Status, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Face, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerDiggingPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Status); err != nil {
+		return
+	}
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Face); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerListItemPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var present bool
+	var n int
+	if packet.Action, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if n, err = readCount(br, "VarInt", 16384); err != nil {
+		return
+	}
+	packet.Items = make([]PlayerListItem, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 PlayerListItem
+		if _, err = io.ReadFull(br, item0.UUID[:]); err != nil {
+			return
+		}
+		if packet.Action == 0 {
+			if item0.Name, err = ReadMinecraftString(br, 128); err != nil {
+				return
+			}
+		} else {
+			item0.Name = ""
+		}
+		if packet.Action == 0 {
+			if n, err = readCount(br, "VarInt", 16); err != nil {
+				return
+			}
+			item0.Properties = make([]AuthProperty, 0, countCapacity(n))
+			for i1, n1 := 0, n; i1 < n1; i1++ {
+				var item1 AuthProperty
+				if item1.Name, err = ReadMinecraftString(br, 32767); err != nil {
+					return
+				}
+				if item1.Value, err = ReadMinecraftString(br, 32767); err != nil {
+					return
+				}
+				if present, err = ReadBool(br); err != nil {
+					return
+				}
+				if present {
+					if item1.Signature, err = ReadMinecraftString(br, 32767); err != nil {
+						return
+					}
+				} else {
+					item1.Signature = ""
+				}
+				item0.Properties = append(item0.Properties, item1)
+			}
+		} else {
+			item0.Properties = nil
+		}
+		if packet.Action == 0 || packet.Action == 1 {
+			if item0.GameMode, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+		} else {
+			item0.GameMode = 0
+		}
+		if packet.Action == 0 || packet.Action == 2 {
+			if item0.Ping, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+		} else {
+			item0.Ping = 0
+		}
+		if packet.Action == 0 || packet.Action == 3 {
+			if present, err = ReadBool(br); err != nil {
+				return
+			}
+			if present {
+				if item0.DisplayName, err = ReadMinecraftString(br, 32767); err != nil {
+					return
+				}
+			} else {
+				item0.DisplayName = ""
+			}
+		} else {
+			item0.DisplayName = ""
+		}
+		packet.Items = append(packet.Items, item0)
+	}
+	return
+}
+
+func (packet *PlayerListItemPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	var present bool
+	if err = WriteVarInt(writer, packet.Action); err != nil {
+		return
+	}
+	if err = writeCount(writer, "VarInt", 16384, len(packet.Items)); err != nil {
+		return
+	}
+	for i0 := range packet.Items {
+		item0 := &packet.Items[i0]
+		if _, err = writer.Write(item0.UUID[:]); err != nil {
+			return
+		}
+		if packet.Action == 0 {
+			if err = WriteMinecraftString(writer, item0.Name); err != nil {
+				return
+			}
+		}
+		if packet.Action == 0 {
+			if err = writeCount(writer, "VarInt", 16, len(item0.Properties)); err != nil {
+				return
+			}
+			for i1 := range item0.Properties {
+				item1 := &item0.Properties[i1]
+				if err = WriteMinecraftString(writer, item1.Name); err != nil {
+					return
+				}
+				if err = WriteMinecraftString(writer, item1.Value); err != nil {
+					return
+				}
+				present = item1.Signature != ""
+				if err = WriteBool(writer, present); err != nil {
+					return
+				}
+				if present {
+					if err = WriteMinecraftString(writer, item1.Signature); err != nil {
+						return
+					}
+				}
+			}
+		}
+		if packet.Action == 0 || packet.Action == 1 {
+			if err = WriteVarInt(writer, item0.GameMode); err != nil {
+				return
+			}
+		}
+		if packet.Action == 0 || packet.Action == 2 {
+			if err = WriteVarInt(writer, item0.Ping); err != nil {
+				return
+			}
+		}
+		if packet.Action == 0 || packet.Action == 3 {
+			present = item0.DisplayName != ""
+			if err = WriteBool(writer, present); err != nil {
+				return
+			}
+			if present {
+				if err = WriteMinecraftString(writer, item0.DisplayName); err != nil {
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+func (packet *PlayerListTitlePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Header, err = ReadMinecraftString(br, 262144); err != nil {
+		return
+	}
+	if packet.Footer, err = ReadMinecraftString(br, 262144); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerListTitlePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Header); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Footer); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerMovementPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.OnGround, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerMovementPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBool(writer, packet.OnGround); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerPositionAndLookPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Flags, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.TeleportID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerPositionAndLookPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteDouble(writer, packet.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Z); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Pitch); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.Flags); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.TeleportID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerPositionAndRotationPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.OnGround, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerPositionAndRotationPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Pitch); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.OnGround); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerPositionPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.OnGround, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerPositionPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.OnGround); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerRotationPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Yaw, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.OnGround, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PlayerRotationPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteFloat(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Pitch); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.OnGround); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PluginMessagePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Channel, err = ReadMinecraftString(br, 128); err != nil {
+		return
+	}
+	if packet.Payload, err = readBytesField(br, "eof", 1048576); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PluginMessagePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Channel); err != nil {
+		return
+	}
+	if err = writeBytesField(writer, "eof", 1048576, packet.Payload); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PluginMessagePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Channel, err = ReadMinecraftString(br, 64); err != nil {
+		return
+	}
+	if packet.Payload, err = readBytesField(br, "eof", 35000); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *PluginMessagePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Channel); err != nil {
+		return
+	}
+	if err = writeBytesField(writer, "eof", 35000, packet.Payload); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *QueryBlockNBTPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.TransactionID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *QueryBlockNBTPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.TransactionID); err != nil {
+		return
+	}
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *QueryEntityNBTPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.TransactionID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *QueryEntityNBTPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.TransactionID); err != nil {
+		return
+	}
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *RemoveEntityEffectPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.EffectID, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *RemoveEntityEffectPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.EffectID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ResourcePackSendCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Url, err = ReadMinecraftString(br, 32767); err != nil {
+		return
+	}
+	if packet.Hash, err = ReadMinecraftString(br, 40); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ResourcePackSendCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Url); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Hash); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ResourcePackStatusPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Result, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ResourcePackStatusPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Result); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *RespawnPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Dimension, err = readNBTField(br); err != nil {
+		return
+	}
+	if packet.DimensionId, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	if packet.HashedSeed, err = ReadLong(br); err != nil {
+		return
+	}
+	if packet.GameMode, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.PreviousGameMode, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.IsDebug, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.IsFlat, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.CopyMetadata, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *RespawnPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeNBTField(writer, packet.Dimension); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, string(packet.DimensionId)); err != nil {
+		return
+	}
+	if err = WriteLong(writer, packet.HashedSeed); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.GameMode); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.PreviousGameMode); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.IsDebug); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.IsFlat); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.CopyMetadata); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ScoreboardObjectivePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Name, err = ReadMinecraftString(br, 16); err != nil {
+		return
+	}
+	if packet.Mode, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if packet.Value, err = ReadMinecraftString(br, 262144); err != nil {
+			return
+		}
+	} else {
+		packet.Value = ""
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if packet.Type, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Type = 0
+	}
+	return
+}
+
+func (packet *ScoreboardObjectivePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Name); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.Mode); err != nil {
+		return
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if err = WriteMinecraftString(writer, packet.Value); err != nil {
+			return
+		}
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if err = WriteVarInt(writer, packet.Type); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *SelectAdvancementTabPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var present bool
+	if present, err = ReadBool(br); err != nil {
+		return
+	}
+	if present {
+		if packet.Identifier, err = ReadIdentifier(br); err != nil {
+			return
+		}
+	} else {
+		packet.Identifier = ""
+	}
+	return
+}
+
+func (packet *SelectAdvancementTabPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	var present bool
+	present = packet.Identifier != ""
+	if err = WriteBool(writer, present); err != nil {
+		return
+	}
+	if present {
+		if err = WriteMinecraftString(writer, string(packet.Identifier)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *SelectTradePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.SelectedSlot, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SelectTradePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.SelectedSlot); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ServerDifficultyPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Difficulty, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.Locked, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *ServerDifficultyPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteUnsignedByte(writer, packet.Difficulty); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.Locked); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetBeaconEffectPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.PrimaryEffect, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.SecondaryEffect, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetBeaconEffectPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.PrimaryEffect); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.SecondaryEffect); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetCooldownPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ItemID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.CooldownTicks, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetCooldownPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.ItemID); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.CooldownTicks); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetDifficultyPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.NewDifficulty, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetDifficultyPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.NewDifficulty); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetDisplayedRecipePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.RecipeID, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetDisplayedRecipePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, string(packet.RecipeID)); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetExperiencePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ExperienceBar, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Level, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.TotalExperience, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetExperiencePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteFloat(writer, packet.ExperienceBar); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Level); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.TotalExperience); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetPassengersPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.Passengers = make([]EntityID, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 EntityID
+		if item0, err = readEntityIDField(br, "VarInt"); err != nil {
+			return
+		}
+		packet.Passengers = append(packet.Passengers, item0)
+	}
+	return
+}
+
+func (packet *SetPassengersPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = writeCount(writer, "VarInt", 65536, len(packet.Passengers)); err != nil {
+		return
+	}
+	for i0 := range packet.Passengers {
+		if err = writeEntityIDField(writer, "VarInt", packet.Passengers[i0]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *SetRecipeBookStatePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.BookID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.BookOpen, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.FilterActive, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetRecipeBookStatePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.BookID); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.BookOpen); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.FilterActive); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SetSlotPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.Slot, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.Item.Present, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.Item.Present {
+		if packet.Item.ItemID, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Item.ItemID = 0
+	}
+	if packet.Item.Present {
+		if packet.Item.Count, err = ReadByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.Item.Count = 0
+	}
+	if packet.Item.Present {
+		if packet.Item.NBT, err = readNBTField(br); err != nil {
+			return
+		}
+	} else {
+		packet.Item.NBT = nil
+	}
+	return
+}
+
+func (packet *SetSlotPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.Slot); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.Item.Present); err != nil {
+		return
+	}
+	if packet.Item.Present {
+		if err = WriteVarInt(writer, packet.Item.ItemID); err != nil {
+			return
+		}
+	}
+	if packet.Item.Present {
+		if err = WriteByte(writer, packet.Item.Count); err != nil {
+			return
+		}
+	}
+	if packet.Item.Present {
+		if err = writeNBTField(writer, packet.Item.NBT); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *Slot) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Present, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.Present {
+		if packet.ItemID, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.ItemID = 0
+	}
+	if packet.Present {
+		if packet.Count, err = ReadByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.Count = 0
+	}
+	if packet.Present {
+		if packet.NBT, err = readNBTField(br); err != nil {
+			return
+		}
+	} else {
+		packet.NBT = nil
+	}
+	return
+}
+
+func (packet *Slot) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBool(writer, packet.Present); err != nil {
+		return
+	}
+	if packet.Present {
+		if err = WriteVarInt(writer, packet.ItemID); err != nil {
+			return
+		}
+	}
+	if packet.Present {
+		if err = WriteByte(writer, packet.Count); err != nil {
+			return
+		}
+	}
+	if packet.Present {
+		if err = writeNBTField(writer, packet.NBT); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *SoundEffectPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.SoundID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Category, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.X, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.Y, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.Z, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.Volume, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadFloat(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SoundEffectPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.SoundID); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Category); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.X); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.Y); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.Z); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Volume); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Pitch); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnEntityPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if _, err = io.ReadFull(br, packet.ObjectUUID[:]); err != nil {
+		return
+	}
+	if packet.Type, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.Data, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.VelocityX, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.VelocityY, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.VelocityZ, err = ReadShort(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnEntityPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if _, err = writer.Write(packet.ObjectUUID[:]); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Type); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Pitch); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.Data); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.VelocityX); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.VelocityY); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.VelocityZ); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnExperienceOrbPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Count, err = ReadShort(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnExperienceOrbPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.Count); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnLivingEntityPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if _, err = io.ReadFull(br, packet.EntityUUID[:]); err != nil {
+		return
+	}
+	if packet.Type, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.HeadPitch, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.VelocityX, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.VelocityY, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.VelocityZ, err = ReadShort(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnLivingEntityPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if _, err = writer.Write(packet.EntityUUID[:]); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Type); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Pitch); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.HeadPitch); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.VelocityX); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.VelocityY); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.VelocityZ); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnPaintingPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if _, err = io.ReadFull(br, packet.EntityUUID[:]); err != nil {
+		return
+	}
+	if packet.Motive, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Facing, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnPaintingPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if _, err = writer.Write(packet.EntityUUID[:]); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Motive); err != nil {
+		return
+	}
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Facing); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnPlayer) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if _, err = io.ReadFull(br, packet.UUID[:]); err != nil {
+		return
+	}
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadAngle(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadAngle(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnPlayer) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if _, err = writer.Write(packet.UUID[:]); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteAngle(writer, packet.Pitch); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnPositionPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Position, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpawnPositionPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Position); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpectatePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if _, err = io.ReadFull(br, packet.TargetPlayer[:]); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SpectatePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if _, err = writer.Write(packet.TargetPlayer[:]); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *StatisticsPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.Statistics = make([]Statistic, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 Statistic
+		if item0.CategoryID, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+		if item0.StatisticID, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+		if item0.Value, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+		packet.Statistics = append(packet.Statistics, item0)
+	}
+	return
+}
+
+func (packet *StatisticsPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeCount(writer, "VarInt", 65536, len(packet.Statistics)); err != nil {
+		return
+	}
+	for i0 := range packet.Statistics {
+		item0 := &packet.Statistics[i0]
+		if err = WriteVarInt(writer, item0.CategoryID); err != nil {
+			return
+		}
+		if err = WriteVarInt(writer, item0.StatisticID); err != nil {
+			return
+		}
+		if err = WriteVarInt(writer, item0.Value); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *StatusPingPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Time, err = ReadLong(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *StatusPingPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteLong(writer, packet.Time); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *StatusPingPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Time, err = ReadLong(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *StatusPingPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteLong(writer, packet.Time); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *StatusResponsePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Data, err = ReadMinecraftString(br, 32767); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *StatusResponsePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Data); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SteerBoatPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.LeftPaddleTurning, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.RightPaddleTurning, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SteerBoatPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBool(writer, packet.LeftPaddleTurning); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.RightPaddleTurning); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SteerVehiclePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Sideways, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Forward, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Flags, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *SteerVehiclePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteFloat(writer, packet.Sideways); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Forward); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.Flags); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *StopSoundPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Flags, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.Flags == 1 || packet.Flags == 3 {
+		if packet.Source, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Source = 0
+	}
+	if packet.Flags == 2 || packet.Flags == 3 {
+		if packet.Sound, err = ReadIdentifier(br); err != nil {
+			return
+		}
+	} else {
+		packet.Sound = ""
+	}
+	return
+}
+
+func (packet *StopSoundPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.Flags); err != nil {
+		return
+	}
+	if packet.Flags == 1 || packet.Flags == 3 {
+		if err = WriteVarInt(writer, packet.Source); err != nil {
+			return
+		}
+	}
+	if packet.Flags == 2 || packet.Flags == 3 {
+		if err = WriteMinecraftString(writer, string(packet.Sound)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *TabCompletePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var present bool
+	var n int
+	if packet.TransactionId, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Start, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Length, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if n, err = readCount(br, "VarInt", 32767); err != nil {
+		return
+	}
+	packet.Matches = make([]TabCompleteMatch, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 TabCompleteMatch
+		if item0.Match, err = ReadMinecraftString(br, 32767); err != nil {
+			return
+		}
+		if present, err = ReadBool(br); err != nil {
+			return
+		}
+		if present {
+			if item0.Tooltip, err = ReadMinecraftString(br, 262144); err != nil {
+				return
+			}
+		} else {
+			item0.Tooltip = ""
+		}
+		packet.Matches = append(packet.Matches, item0)
+	}
+	return
+}
+
+func (packet *TabCompletePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	var present bool
+	if err = WriteVarInt(writer, packet.TransactionId); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Start); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Length); err != nil {
+		return
+	}
+	if err = writeCount(writer, "VarInt", 32767, len(packet.Matches)); err != nil {
+		return
+	}
+	for i0 := range packet.Matches {
+		item0 := &packet.Matches[i0]
+		if err = WriteMinecraftString(writer, item0.Match); err != nil {
+			return
+		}
+		present = item0.Tooltip != ""
+		if err = WriteBool(writer, present); err != nil {
+			return
+		}
+		if present {
+			if err = WriteMinecraftString(writer, item0.Tooltip); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (packet *TabCompletePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.TransactionId, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Text, err = ReadMinecraftString(br, 32500); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *TabCompletePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.TransactionId); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Text); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *TagsPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.BlockTags = make([]NamedTag, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 NamedTag
+		if item0.Name, err = ReadIdentifier(br); err != nil {
+			return
+		}
+		if n, err = readCount(br, "VarInt", 65536); err != nil {
+			return
+		}
+		item0.Entries = make([]VarInt, 0, countCapacity(n))
+		for i1, n1 := 0, n; i1 < n1; i1++ {
+			var item1 VarInt
+			if item1, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+			item0.Entries = append(item0.Entries, item1)
+		}
+		packet.BlockTags = append(packet.BlockTags, item0)
+	}
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.ItemTags = make([]NamedTag, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 NamedTag
+		if item0.Name, err = ReadIdentifier(br); err != nil {
+			return
+		}
+		if n, err = readCount(br, "VarInt", 65536); err != nil {
+			return
+		}
+		item0.Entries = make([]VarInt, 0, countCapacity(n))
+		for i1, n1 := 0, n; i1 < n1; i1++ {
+			var item1 VarInt
+			if item1, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+			item0.Entries = append(item0.Entries, item1)
+		}
+		packet.ItemTags = append(packet.ItemTags, item0)
+	}
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.FluidTags = make([]NamedTag, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 NamedTag
+		if item0.Name, err = ReadIdentifier(br); err != nil {
+			return
+		}
+		if n, err = readCount(br, "VarInt", 65536); err != nil {
+			return
+		}
+		item0.Entries = make([]VarInt, 0, countCapacity(n))
+		for i1, n1 := 0, n; i1 < n1; i1++ {
+			var item1 VarInt
+			if item1, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+			item0.Entries = append(item0.Entries, item1)
+		}
+		packet.FluidTags = append(packet.FluidTags, item0)
+	}
+	if n, err = readCount(br, "VarInt", 65536); err != nil {
+		return
+	}
+	packet.EntityTags = make([]NamedTag, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 NamedTag
+		if item0.Name, err = ReadIdentifier(br); err != nil {
+			return
+		}
+		if n, err = readCount(br, "VarInt", 65536); err != nil {
+			return
+		}
+		item0.Entries = make([]VarInt, 0, countCapacity(n))
+		for i1, n1 := 0, n; i1 < n1; i1++ {
+			var item1 VarInt
+			if item1, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+			item0.Entries = append(item0.Entries, item1)
+		}
+		packet.EntityTags = append(packet.EntityTags, item0)
+	}
+	return
+}
+
+func (packet *TagsPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeCount(writer, "VarInt", 65536, len(packet.BlockTags)); err != nil {
+		return
+	}
+	for i0 := range packet.BlockTags {
+		item0 := &packet.BlockTags[i0]
+		if err = WriteMinecraftString(writer, string(item0.Name)); err != nil {
+			return
+		}
+		if err = writeCount(writer, "VarInt", 65536, len(item0.Entries)); err != nil {
+			return
+		}
+		for i1 := range item0.Entries {
+			if err = WriteVarInt(writer, item0.Entries[i1]); err != nil {
+				return
+			}
+		}
+	}
+	if err = writeCount(writer, "VarInt", 65536, len(packet.ItemTags)); err != nil {
+		return
+	}
+	for i0 := range packet.ItemTags {
+		item0 := &packet.ItemTags[i0]
+		if err = WriteMinecraftString(writer, string(item0.Name)); err != nil {
+			return
+		}
+		if err = writeCount(writer, "VarInt", 65536, len(item0.Entries)); err != nil {
+			return
+		}
+		for i1 := range item0.Entries {
+			if err = WriteVarInt(writer, item0.Entries[i1]); err != nil {
+				return
+			}
+		}
+	}
+	if err = writeCount(writer, "VarInt", 65536, len(packet.FluidTags)); err != nil {
+		return
+	}
+	for i0 := range packet.FluidTags {
+		item0 := &packet.FluidTags[i0]
+		if err = WriteMinecraftString(writer, string(item0.Name)); err != nil {
+			return
+		}
+		if err = writeCount(writer, "VarInt", 65536, len(item0.Entries)); err != nil {
+			return
+		}
+		for i1 := range item0.Entries {
+			if err = WriteVarInt(writer, item0.Entries[i1]); err != nil {
+				return
+			}
+		}
+	}
+	if err = writeCount(writer, "VarInt", 65536, len(packet.EntityTags)); err != nil {
+		return
+	}
+	for i0 := range packet.EntityTags {
+		item0 := &packet.EntityTags[i0]
+		if err = WriteMinecraftString(writer, string(item0.Name)); err != nil {
+			return
+		}
+		if err = writeCount(writer, "VarInt", 65536, len(item0.Entries)); err != nil {
+			return
+		}
+		for i1 := range item0.Entries {
+			if err = WriteVarInt(writer, item0.Entries[i1]); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (packet *TeamsPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if packet.Name, err = ReadMinecraftString(br, 16); err != nil {
+		return
+	}
+	if packet.Mode, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if packet.DisplayName, err = ReadMinecraftString(br, 262144); err != nil {
+			return
+		}
+	} else {
+		packet.DisplayName = ""
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if packet.FriendlyFlags, err = ReadUnsignedByte(br); err != nil {
+			return
+		}
+	} else {
+		packet.FriendlyFlags = 0
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if packet.NameTagVisibility, err = ReadMinecraftString(br, 32); err != nil {
+			return
+		}
+	} else {
+		packet.NameTagVisibility = ""
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if packet.CollisionRule, err = ReadMinecraftString(br, 32); err != nil {
+			return
+		}
+	} else {
+		packet.CollisionRule = ""
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if packet.Color, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Color = 0
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if packet.Prefix, err = ReadMinecraftString(br, 262144); err != nil {
+			return
+		}
+	} else {
+		packet.Prefix = ""
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if packet.Suffix, err = ReadMinecraftString(br, 262144); err != nil {
+			return
+		}
+	} else {
+		packet.Suffix = ""
+	}
+	if packet.Mode == 0 || packet.Mode == 3 || packet.Mode == 4 {
+		if n, err = readCount(br, "VarInt", 1024); err != nil {
+			return
+		}
+		packet.Players = make([]string, 0, countCapacity(n))
+		for i0, n0 := 0, n; i0 < n0; i0++ {
+			var item0 string
+			if item0, err = ReadMinecraftString(br, 40); err != nil {
+				return
+			}
+			packet.Players = append(packet.Players, item0)
+		}
+	} else {
+		packet.Players = nil
+	}
+	return
+}
+
+func (packet *TeamsPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.Name); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, packet.Mode); err != nil {
+		return
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if err = WriteMinecraftString(writer, packet.DisplayName); err != nil {
+			return
+		}
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if err = WriteUnsignedByte(writer, packet.FriendlyFlags); err != nil {
+			return
+		}
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if err = WriteMinecraftString(writer, packet.NameTagVisibility); err != nil {
+			return
+		}
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if err = WriteMinecraftString(writer, packet.CollisionRule); err != nil {
+			return
+		}
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if err = WriteVarInt(writer, packet.Color); err != nil {
+			return
+		}
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if err = WriteMinecraftString(writer, packet.Prefix); err != nil {
+			return
+		}
+	}
+	if packet.Mode == 0 || packet.Mode == 2 {
+		if err = WriteMinecraftString(writer, packet.Suffix); err != nil {
+			return
+		}
+	}
+	if packet.Mode == 0 || packet.Mode == 3 || packet.Mode == 4 {
+		if err = writeCount(writer, "VarInt", 1024, len(packet.Players)); err != nil {
+			return
+		}
+		for i0 := range packet.Players {
+			if err = WriteMinecraftString(writer, packet.Players[i0]); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (packet *TeleportConfirmPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.TeleportID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *TeleportConfirmPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.TeleportID); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *TimeUpdatePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WorldAge, err = ReadLong(br); err != nil {
+		return
+	}
+	if packet.TimeOfDay, err = ReadLong(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *TimeUpdatePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteLong(writer, packet.WorldAge); err != nil {
+		return
+	}
+	if err = WriteLong(writer, packet.TimeOfDay); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *TitlePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Action, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Action == 0 || packet.Action == 1 || packet.Action == 2 {
+		if packet.Text, err = ReadMinecraftString(br, 262144); err != nil {
+			return
+		}
+	} else {
+		packet.Text = ""
+	}
+	if packet.Action == 3 {
+		if packet.FadeIn, err = ReadInt(br); err != nil {
+			return
+		}
+	} else {
+		packet.FadeIn = 0
+	}
+	if packet.Action == 3 {
+		if packet.Stay, err = ReadInt(br); err != nil {
+			return
+		}
+	} else {
+		packet.Stay = 0
+	}
+	if packet.Action == 3 {
+		if packet.FadeOut, err = ReadInt(br); err != nil {
+			return
+		}
+	} else {
+		packet.FadeOut = 0
+	}
+	return
+}
+
+func (packet *TitlePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Action); err != nil {
+		return
+	}
+	if packet.Action == 0 || packet.Action == 1 || packet.Action == 2 {
+		if err = WriteMinecraftString(writer, packet.Text); err != nil {
+			return
+		}
+	}
+	if packet.Action == 3 {
+		if err = WriteInt(writer, packet.FadeIn); err != nil {
+			return
+		}
+	}
+	if packet.Action == 3 {
+		if err = WriteInt(writer, packet.Stay); err != nil {
+			return
+		}
+	}
+	if packet.Action == 3 {
+		if err = WriteInt(writer, packet.FadeOut); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *UnloadChunkPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ChunkX, err = ReadInt(br); err != nil {
+		return
+	}
+	if packet.ChunkZ, err = ReadInt(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UnloadChunkPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteInt(writer, packet.ChunkX); err != nil {
+		return
+	}
+	if err = WriteInt(writer, packet.ChunkZ); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UnlockRecipesPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Action, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.CraftingBookOpen, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.CraftingBookFilterActive, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.SmeltingBookOpen, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.SmeltingBookFilterActive, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.BlastFurnaceBookOpen, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.BlastFurnaceBookFilterActive, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.SmokerBookOpen, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.SmokerBookFilterActive, err = ReadBool(br); err != nil {
+		return
+	}
+	if packet.RecipeIDs, err = ReadIdentifierArray(br); err != nil {
+		return
+	}
+	if packet.Action == 0 {
+		if packet.InitRecipeIDs, err = ReadIdentifierArray(br); err != nil {
+			return
+		}
+	} else {
+		packet.InitRecipeIDs = nil
+	}
+	return
+}
+
+func (packet *UnlockRecipesPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Action); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.CraftingBookOpen); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.CraftingBookFilterActive); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.SmeltingBookOpen); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.SmeltingBookFilterActive); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.BlastFurnaceBookOpen); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.BlastFurnaceBookFilterActive); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.SmokerBookOpen); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.SmokerBookFilterActive); err != nil {
+		return
+	}
+	if err = WriteIdentifierArray(writer, packet.RecipeIDs); err != nil {
+		return
+	}
+	if packet.Action == 0 {
+		if err = WriteIdentifierArray(writer, packet.InitRecipeIDs); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *UpdateCommandBlockMinecartPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	if packet.Command, err = ReadMinecraftString(br, 32767); err != nil {
+		return
+	}
+	if packet.TrackOutput, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateCommandBlockMinecartPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Command); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.TrackOutput); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateCommandBlockPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Command, err = ReadMinecraftString(br, 32767); err != nil {
+		return
+	}
+	if packet.Mode, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Flags, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateCommandBlockPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Command); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Mode); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Flags); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateHealthPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Health, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Food, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.FoodSaturation, err = ReadFloat(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateHealthPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteFloat(writer, packet.Health); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Food); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.FoodSaturation); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateJigsawBlockPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Name, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	if packet.Target, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	if packet.Pool, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	if packet.FinalState, err = ReadMinecraftString(br, 32767); err != nil {
+		return
+	}
+	if packet.JointType, err = ReadMinecraftString(br, 32767); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateJigsawBlockPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, string(packet.Name)); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, string(packet.Target)); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, string(packet.Pool)); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.FinalState); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.JointType); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateScorePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityName, err = ReadMinecraftString(br, 40); err != nil {
+		return
+	}
+	if packet.Action, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.ObjectiveName, err = ReadMinecraftString(br, 16); err != nil {
+		return
+	}
+	if packet.Action != 1 {
+		if packet.Value, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.Value = 0
+	}
+	return
+}
+
+func (packet *UpdateScorePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteMinecraftString(writer, packet.EntityName); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Action); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.ObjectiveName); err != nil {
+		return
+	}
+	if packet.Action != 1 {
+		if err = WriteVarInt(writer, packet.Value); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (packet *UpdateSignPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Line1, err = ReadMinecraftString(br, 384); err != nil {
+		return
+	}
+	if packet.Line2, err = ReadMinecraftString(br, 384); err != nil {
+		return
+	}
+	if packet.Line3, err = ReadMinecraftString(br, 384); err != nil {
+		return
+	}
+	if packet.Line4, err = ReadMinecraftString(br, 384); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateSignPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Line1); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Line2); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Line3); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Line4); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateStructureBlockPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Location, err = ReadBlockPosition(br); err != nil {
+		return
+	}
+	if packet.Action, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Mode, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Name, err = ReadMinecraftString(br, 32767); err != nil {
+		return
+	}
+	if packet.OffsetX, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.OffsetY, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.OffsetZ, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.SizeX, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.SizeY, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.SizeZ, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.Mirror, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Rotation, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Metadata, err = ReadMinecraftString(br, 128); err != nil {
+		return
+	}
+	if packet.Integrity, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Seed, err = ReadVarLong(br); err != nil {
+		return
+	}
+	if packet.Flags, err = ReadByte(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateStructureBlockPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteBlockPosition(writer, packet.Location); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Action); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Mode); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Name); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.OffsetX); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.OffsetY); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.OffsetZ); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.SizeX); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.SizeY); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.SizeZ); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Mirror); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Rotation); err != nil {
+		return
+	}
+	if err = WriteMinecraftString(writer, packet.Metadata); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Integrity); err != nil {
+		return
+	}
+	if err = WriteVarLong(writer, packet.Seed); err != nil {
+		return
+	}
+	if err = WriteByte(writer, packet.Flags); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateViewDistancePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ViewDistance, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateViewDistancePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.ViewDistance); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateViewPositionPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ChunkX, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.ChunkZ, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UpdateViewPositionPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.ChunkX); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.ChunkZ); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UseItemPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Hand, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *UseItemPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Hand); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *VehicleMovePacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadFloat(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *VehicleMovePacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Pitch); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *VehicleMovePacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Position.X, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Y, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Position.Z, err = ReadDouble(br); err != nil {
+		return
+	}
+	if packet.Yaw, err = ReadFloat(br); err != nil {
+		return
+	}
+	if packet.Pitch, err = ReadFloat(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *VehicleMovePacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteDouble(writer, packet.Position.X); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Y); err != nil {
+		return
+	}
+	if err = WriteDouble(writer, packet.Position.Z); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Yaw); err != nil {
+		return
+	}
+	if err = WriteFloat(writer, packet.Pitch); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *WindowConfirmationPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.ActionNumber, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.Accepted, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *WindowConfirmationPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.ActionNumber); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.Accepted); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *WindowConfirmationPacketSB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadByte(br); err != nil {
+		return
+	}
+	if packet.ActionNumber, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.Accepted, err = ReadBool(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *WindowConfirmationPacketSB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.ActionNumber); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.Accepted); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *WindowItemsPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if packet.WindowID, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if n, err = readCount(br, "int16", 1024); err != nil {
+		return
+	}
+	packet.Slots = make([]Slot, 0, countCapacity(n))
+	for i0, n0 := 0, n; i0 < n0; i0++ {
+		var item0 Slot
+		if item0.Present, err = ReadBool(br); err != nil {
+			return
+		}
+		if item0.Present {
+			if item0.ItemID, err = ReadVarIntFrom(br); err != nil {
+				return
+			}
+		} else {
+			item0.ItemID = 0
+		}
+		if item0.Present {
+			if item0.Count, err = ReadByte(br); err != nil {
+				return
+			}
+		} else {
+			item0.Count = 0
+		}
+		if item0.Present {
+			if item0.NBT, err = readNBTField(br); err != nil {
+				return
+			}
+		} else {
+			item0.NBT = nil
+		}
+		packet.Slots = append(packet.Slots, item0)
+	}
+	return
+}
+
+func (packet *WindowItemsPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteUnsignedByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = writeCount(writer, "int16", 1024, len(packet.Slots)); err != nil {
+		return
+	}
+	for i0 := range packet.Slots {
+		item0 := &packet.Slots[i0]
+		if err = WriteBool(writer, item0.Present); err != nil {
+			return
+		}
+		if item0.Present {
+			if err = WriteVarInt(writer, item0.ItemID); err != nil {
+				return
+			}
+		}
+		if item0.Present {
+			if err = WriteByte(writer, item0.Count); err != nil {
+				return
+			}
+		}
+		if item0.Present {
+			if err = writeNBTField(writer, item0.NBT); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (packet *WindowPropertyPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	if packet.Property, err = ReadShort(br); err != nil {
+		return
+	}
+	if packet.Value, err = ReadShort(br); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *WindowPropertyPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteUnsignedByte(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.Property); err != nil {
+		return
+	}
+	if err = WriteShort(writer, packet.Value); err != nil {
+		return
+	}
+	return
+}
+
+func (packet *WorldBorderPacketCB) UnmarshalPacket(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.Action, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Action == 2 || packet.Action == 3 {
+		if packet.X, err = ReadDouble(br); err != nil {
+			return
+		}
+	} else {
+		packet.X = 0
+	}
+	if packet.Action == 2 || packet.Action == 3 {
+		if packet.Z, err = ReadDouble(br); err != nil {
+			return
+		}
+	} else {
+		packet.Z = 0
+	}
+	if packet.Action == 0 {
+		if packet.Diameter, err = ReadDouble(br); err != nil {
+			return
+		}
+	} else {
+		packet.Diameter = 0
+	}
+	if packet.Action == 1 || packet.Action == 3 {
+		if packet.OldDiameter, err = ReadDouble(br); err != nil {
+			return
+		}
+	} else {
+		packet.OldDiameter = 0
+	}
+	if packet.Action == 1 || packet.Action == 3 {
+		if packet.NewDiameter, err = ReadDouble(br); err != nil {
+			return
+		}
+	} else {
+		packet.NewDiameter = 0
+	}
+	if packet.Action == 1 || packet.Action == 3 {
+		if packet.Speed, err = ReadVarLong(br); err != nil {
+			return
+		}
+	} else {
+		packet.Speed = 0
+	}
+	if packet.Action == 3 {
+		if packet.PortalTeleportBoundary, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.PortalTeleportBoundary = 0
+	}
+	if packet.Action == 3 || packet.Action == 4 {
+		if packet.WarningTime, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.WarningTime = 0
+	}
+	if packet.Action == 3 || packet.Action == 5 {
+		if packet.WarningBlocks, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	} else {
+		packet.WarningBlocks = 0
+	}
+	return
+}
+
+func (packet *WorldBorderPacketCB) MarshalPacket(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.Action); err != nil {
+		return
+	}
+	if packet.Action == 2 || packet.Action == 3 {
+		if err = WriteDouble(writer, packet.X); err != nil {
+			return
+		}
+	}
+	if packet.Action == 2 || packet.Action == 3 {
+		if err = WriteDouble(writer, packet.Z); err != nil {
+			return
+		}
+	}
+	if packet.Action == 0 {
+		if err = WriteDouble(writer, packet.Diameter); err != nil {
+			return
+		}
+	}
+	if packet.Action == 1 || packet.Action == 3 {
+		if err = WriteDouble(writer, packet.OldDiameter); err != nil {
+			return
+		}
+	}
+	if packet.Action == 1 || packet.Action == 3 {
+		if err = WriteDouble(writer, packet.NewDiameter); err != nil {
+			return
+		}
+	}
+	if packet.Action == 1 || packet.Action == 3 {
+		if err = WriteVarLong(writer, packet.Speed); err != nil {
+			return
+		}
+	}
+	if packet.Action == 3 {
+		if err = WriteVarInt(writer, packet.PortalTeleportBoundary); err != nil {
+			return
+		}
+	}
+	if packet.Action == 3 || packet.Action == 4 {
+		if err = WriteVarInt(writer, packet.WarningTime); err != nil {
+			return
+		}
+	}
+	if packet.Action == 3 || packet.Action == 5 {
+		if err = WriteVarInt(writer, packet.WarningBlocks); err != nil {
+			return
+		}
+	}
+	return
+}
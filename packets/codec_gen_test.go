@@ -0,0 +1,298 @@
+// Code generated by packetgen; DO NOT EDIT.
+
+package packets
+
+var generatedCodecs = []interface{}{
+	new(AcknowledgePlayerDiggingPacketCB),
+	new(AdvancementTabPacketSB),
+	new(AdvancementsPacketCB),
+	new(AnimationPacketSB),
+	new(AttachEntityPacketCB),
+	new(BlockActionPacketCB),
+	new(BlockBreakAnimationPacketCB),
+	new(BlockChangePacketCB),
+	new(BlockEntityDataPacketCB),
+	new(BossBarPacketCB),
+	new(CameraPacketCB),
+	new(ChatMessagePacketCB),
+	new(ChatMessagePacketSB),
+	new(ChunkDataPacketCB),
+	new(ClickWindowButtonPacketSB),
+	new(ClickWindowPacketSB),
+	new(ClientSettingsPacketSB),
+	new(ClientStatusPacketSB),
+	new(CloseWindowPacketCB),
+	new(CloseWindowPacketSB),
+	new(CollectItemPacketCB),
+	new(CombatEventPacketCB),
+	new(CraftRecipeRequestPacketSB),
+	new(CraftRecipeResponsePacketCB),
+	new(CreativeInventoryActionPacketSB),
+	new(DestroyEntitiesPacketCB),
+	new(DisplayScoreboardPacketCB),
+	new(EditBookPacketSB),
+	new(EffectPacketCB),
+	new(EncryptionRequestPacket),
+	new(EncryptionResponsePacket),
+	new(EntityActionPacketSB),
+	new(EntityAnimationPacketCB),
+	new(EntityEffectPacketCB),
+	new(EntityHeadLookPacketCB),
+	new(EntityMovementPacketCB),
+	new(EntityPositionAndRotationPacketCB),
+	new(EntityPositionPacketCB),
+	new(EntityPropertiesPacketCB),
+	new(EntityRotationPacketCB),
+	new(EntitySoundEffectPacketCB),
+	new(EntityStatusPacketCB),
+	new(EntityTeleportPacketCB),
+	new(EntityVelocityPacketCB),
+	new(ExplosionPacketCB),
+	new(FacePlayerPacketCB),
+	new(GameStateChangePacketCB),
+	new(GenerateStructurePacketSB),
+	new(HandshakePacket),
+	new(HeldItemChangePacketCB),
+	new(HeldItemChangePacketSB),
+	new(InteractEntityPacketSB),
+	new(JoinGamePacketCB),
+	new(KeepAlivePacketCB),
+	new(KeepAlivePacketSB),
+	new(KickPacketCB),
+	new(LockDifficultyPacketSB),
+	new(LoginCompressionPacket),
+	new(LoginKickPacket),
+	new(LoginStartPacket),
+	new(LoginSuccessPacket),
+	new(MapDataPacketCB),
+	new(MultiBlockChangePacketCB),
+	new(NBTQueryResponsePacketCB),
+	new(NameItemPacketSB),
+	new(NamedSoundEffectPacketCB),
+	new(OpenBookPacketCB),
+	new(OpenHorseWindowPacketCB),
+	new(OpenSignEditorPacketCB),
+	new(OpenWindowPacketCB),
+	new(ParticlePacketCB),
+	new(PickItemPacketSB),
+	new(PlayerAbilitiesPacketCB),
+	new(PlayerAbilitiesPacketSB),
+	new(PlayerBlockPlacementPacketSB),
+	new(PlayerDiggingPacketSB),
+	new(PlayerListItemPacketCB),
+	new(PlayerListTitlePacketCB),
+	new(PlayerMovementPacketSB),
+	new(PlayerPositionAndLookPacketCB),
+	new(PlayerPositionAndRotationPacketSB),
+	new(PlayerPositionPacketSB),
+	new(PlayerRotationPacketSB),
+	new(PluginMessagePacketCB),
+	new(PluginMessagePacketSB),
+	new(QueryBlockNBTPacketSB),
+	new(QueryEntityNBTPacketSB),
+	new(RemoveEntityEffectPacketCB),
+	new(ResourcePackSendCB),
+	new(ResourcePackStatusPacketSB),
+	new(RespawnPacketCB),
+	new(ScoreboardObjectivePacketCB),
+	new(SelectAdvancementTabPacketCB),
+	new(SelectTradePacketSB),
+	new(ServerDifficultyPacketCB),
+	new(SetBeaconEffectPacketSB),
+	new(SetCooldownPacketCB),
+	new(SetDifficultyPacketSB),
+	new(SetDisplayedRecipePacketSB),
+	new(SetExperiencePacketCB),
+	new(SetPassengersPacketCB),
+	new(SetRecipeBookStatePacketSB),
+	new(SetSlotPacketCB),
+	new(Slot),
+	new(SoundEffectPacketCB),
+	new(SpawnEntityPacketCB),
+	new(SpawnExperienceOrbPacketCB),
+	new(SpawnLivingEntityPacketCB),
+	new(SpawnPaintingPacketCB),
+	new(SpawnPlayer),
+	new(SpawnPositionPacketCB),
+	new(SpectatePacketSB),
+	new(StatisticsPacketCB),
+	new(StatusPingPacketCB),
+	new(StatusPingPacketSB),
+	new(StatusResponsePacketCB),
+	new(SteerBoatPacketSB),
+	new(SteerVehiclePacketSB),
+	new(StopSoundPacketCB),
+	new(TabCompletePacketCB),
+	new(TabCompletePacketSB),
+	new(TagsPacketCB),
+	new(TeamsPacketCB),
+	new(TeleportConfirmPacketSB),
+	new(TimeUpdatePacketCB),
+	new(TitlePacketCB),
+	new(UnloadChunkPacketCB),
+	new(UnlockRecipesPacketCB),
+	new(UpdateCommandBlockMinecartPacketSB),
+	new(UpdateCommandBlockPacketSB),
+	new(UpdateHealthPacketCB),
+	new(UpdateJigsawBlockPacketSB),
+	new(UpdateScorePacketCB),
+	new(UpdateSignPacketSB),
+	new(UpdateStructureBlockPacketSB),
+	new(UpdateViewDistancePacketCB),
+	new(UpdateViewPositionPacketCB),
+	new(UseItemPacketSB),
+	new(VehicleMovePacketCB),
+	new(VehicleMovePacketSB),
+	new(WindowConfirmationPacketCB),
+	new(WindowConfirmationPacketSB),
+	new(WindowItemsPacketCB),
+	new(WindowPropertyPacketCB),
+	new(WorldBorderPacketCB),
+}
+
+var specPackets = []struct {
+	id        VarInt
+	direction Direction
+	packet    Packet
+}{
+	{0x00, ClientBound, new(SpawnEntityPacketCB)},
+	{0x01, ClientBound, new(SpawnExperienceOrbPacketCB)},
+	{0x02, ClientBound, new(SpawnLivingEntityPacketCB)},
+	{0x03, ClientBound, new(SpawnPaintingPacketCB)},
+	{0x04, ClientBound, new(SpawnPlayer)},
+	{0x05, ClientBound, new(EntityAnimationPacketCB)},
+	{0x06, ClientBound, new(StatisticsPacketCB)},
+	{0x07, ClientBound, new(AcknowledgePlayerDiggingPacketCB)},
+	{0x08, ClientBound, new(BlockBreakAnimationPacketCB)},
+	{0x09, ClientBound, new(BlockEntityDataPacketCB)},
+	{0x0A, ClientBound, new(BlockActionPacketCB)},
+	{0x0B, ClientBound, new(BlockChangePacketCB)},
+	{0x0C, ClientBound, new(BossBarPacketCB)},
+	{0x0D, ClientBound, new(ServerDifficultyPacketCB)},
+	{0x0E, ClientBound, new(ChatMessagePacketCB)},
+	{0x0F, ClientBound, new(TabCompletePacketCB)},
+	{0x10, ClientBound, new(DeclareCommandsPacketCB)},
+	{0x11, ClientBound, new(WindowConfirmationPacketCB)},
+	{0x12, ClientBound, new(CloseWindowPacketCB)},
+	{0x13, ClientBound, new(WindowItemsPacketCB)},
+	{0x14, ClientBound, new(WindowPropertyPacketCB)},
+	{0x15, ClientBound, new(SetSlotPacketCB)},
+	{0x16, ClientBound, new(SetCooldownPacketCB)},
+	{0x17, ClientBound, new(PluginMessagePacketCB)},
+	{0x18, ClientBound, new(NamedSoundEffectPacketCB)},
+	{0x19, ClientBound, new(KickPacketCB)},
+	{0x1A, ClientBound, new(EntityStatusPacketCB)},
+	{0x1B, ClientBound, new(ExplosionPacketCB)},
+	{0x1C, ClientBound, new(UnloadChunkPacketCB)},
+	{0x1D, ClientBound, new(GameStateChangePacketCB)},
+	{0x1E, ClientBound, new(OpenHorseWindowPacketCB)},
+	{0x1F, ClientBound, new(KeepAlivePacketCB)},
+	{0x20, ClientBound, new(ChunkDataPacketCB)},
+	{0x21, ClientBound, new(EffectPacketCB)},
+	{0x22, ClientBound, new(ParticlePacketCB)},
+	{0x23, ClientBound, new(UpdateLightPacketCB)},
+	{0x24, ClientBound, new(JoinGamePacketCB)},
+	{0x25, ClientBound, new(MapDataPacketCB)},
+	{0x26, ClientBound, new(TradeListPacketCB)},
+	{0x27, ClientBound, new(EntityPositionPacketCB)},
+	{0x28, ClientBound, new(EntityPositionAndRotationPacketCB)},
+	{0x29, ClientBound, new(EntityRotationPacketCB)},
+	{0x2A, ClientBound, new(EntityMovementPacketCB)},
+	{0x2B, ClientBound, new(VehicleMovePacketCB)},
+	{0x2C, ClientBound, new(OpenBookPacketCB)},
+	{0x2D, ClientBound, new(OpenWindowPacketCB)},
+	{0x2E, ClientBound, new(OpenSignEditorPacketCB)},
+	{0x2F, ClientBound, new(CraftRecipeResponsePacketCB)},
+	{0x30, ClientBound, new(PlayerAbilitiesPacketCB)},
+	{0x31, ClientBound, new(CombatEventPacketCB)},
+	{0x32, ClientBound, new(PlayerListItemPacketCB)},
+	{0x33, ClientBound, new(FacePlayerPacketCB)},
+	{0x34, ClientBound, new(PlayerPositionAndLookPacketCB)},
+	{0x35, ClientBound, new(UnlockRecipesPacketCB)},
+	{0x36, ClientBound, new(DestroyEntitiesPacketCB)},
+	{0x37, ClientBound, new(RemoveEntityEffectPacketCB)},
+	{0x38, ClientBound, new(ResourcePackSendCB)},
+	{0x39, ClientBound, new(RespawnPacketCB)},
+	{0x3A, ClientBound, new(EntityHeadLookPacketCB)},
+	{0x3B, ClientBound, new(MultiBlockChangePacketCB)},
+	{0x3C, ClientBound, new(SelectAdvancementTabPacketCB)},
+	{0x3D, ClientBound, new(WorldBorderPacketCB)},
+	{0x3E, ClientBound, new(CameraPacketCB)},
+	{0x3F, ClientBound, new(HeldItemChangePacketCB)},
+	{0x40, ClientBound, new(UpdateViewPositionPacketCB)},
+	{0x41, ClientBound, new(UpdateViewDistancePacketCB)},
+	{0x42, ClientBound, new(SpawnPositionPacketCB)},
+	{0x43, ClientBound, new(DisplayScoreboardPacketCB)},
+	{0x44, ClientBound, new(EntityMetadataPacketCB)},
+	{0x45, ClientBound, new(AttachEntityPacketCB)},
+	{0x46, ClientBound, new(EntityVelocityPacketCB)},
+	{0x47, ClientBound, new(EntityEquipmentPacketCB)},
+	{0x48, ClientBound, new(SetExperiencePacketCB)},
+	{0x49, ClientBound, new(UpdateHealthPacketCB)},
+	{0x4A, ClientBound, new(ScoreboardObjectivePacketCB)},
+	{0x4B, ClientBound, new(SetPassengersPacketCB)},
+	{0x4C, ClientBound, new(TeamsPacketCB)},
+	{0x4D, ClientBound, new(UpdateScorePacketCB)},
+	{0x4E, ClientBound, new(TimeUpdatePacketCB)},
+	{0x4F, ClientBound, new(TitlePacketCB)},
+	{0x50, ClientBound, new(EntitySoundEffectPacketCB)},
+	{0x51, ClientBound, new(SoundEffectPacketCB)},
+	{0x52, ClientBound, new(StopSoundPacketCB)},
+	{0x53, ClientBound, new(PlayerListTitlePacketCB)},
+	{0x54, ClientBound, new(NBTQueryResponsePacketCB)},
+	{0x55, ClientBound, new(CollectItemPacketCB)},
+	{0x56, ClientBound, new(EntityTeleportPacketCB)},
+	{0x57, ClientBound, new(AdvancementsPacketCB)},
+	{0x58, ClientBound, new(EntityPropertiesPacketCB)},
+	{0x59, ClientBound, new(EntityEffectPacketCB)},
+	{0x5A, ClientBound, new(DeclareRecipesPacketCB)},
+	{0x5B, ClientBound, new(TagsPacketCB)},
+	{0x00, ServerBound, new(TeleportConfirmPacketSB)},
+	{0x01, ServerBound, new(QueryBlockNBTPacketSB)},
+	{0x02, ServerBound, new(SetDifficultyPacketSB)},
+	{0x03, ServerBound, new(ChatMessagePacketSB)},
+	{0x04, ServerBound, new(ClientStatusPacketSB)},
+	{0x05, ServerBound, new(ClientSettingsPacketSB)},
+	{0x06, ServerBound, new(TabCompletePacketSB)},
+	{0x07, ServerBound, new(WindowConfirmationPacketSB)},
+	{0x08, ServerBound, new(ClickWindowButtonPacketSB)},
+	{0x09, ServerBound, new(ClickWindowPacketSB)},
+	{0x0A, ServerBound, new(CloseWindowPacketSB)},
+	{0x0B, ServerBound, new(PluginMessagePacketSB)},
+	{0x0C, ServerBound, new(EditBookPacketSB)},
+	{0x0D, ServerBound, new(QueryEntityNBTPacketSB)},
+	{0x0E, ServerBound, new(InteractEntityPacketSB)},
+	{0x0F, ServerBound, new(GenerateStructurePacketSB)},
+	{0x10, ServerBound, new(KeepAlivePacketSB)},
+	{0x11, ServerBound, new(LockDifficultyPacketSB)},
+	{0x12, ServerBound, new(PlayerPositionPacketSB)},
+	{0x13, ServerBound, new(PlayerPositionAndRotationPacketSB)},
+	{0x14, ServerBound, new(PlayerRotationPacketSB)},
+	{0x15, ServerBound, new(PlayerMovementPacketSB)},
+	{0x16, ServerBound, new(VehicleMovePacketSB)},
+	{0x17, ServerBound, new(SteerBoatPacketSB)},
+	{0x18, ServerBound, new(PickItemPacketSB)},
+	{0x19, ServerBound, new(CraftRecipeRequestPacketSB)},
+	{0x1A, ServerBound, new(PlayerAbilitiesPacketSB)},
+	{0x1B, ServerBound, new(PlayerDiggingPacketSB)},
+	{0x1C, ServerBound, new(EntityActionPacketSB)},
+	{0x1D, ServerBound, new(SteerVehiclePacketSB)},
+	{0x1E, ServerBound, new(SetRecipeBookStatePacketSB)},
+	{0x1F, ServerBound, new(SetDisplayedRecipePacketSB)},
+	{0x20, ServerBound, new(NameItemPacketSB)},
+	{0x21, ServerBound, new(ResourcePackStatusPacketSB)},
+	{0x22, ServerBound, new(AdvancementTabPacketSB)},
+	{0x23, ServerBound, new(SelectTradePacketSB)},
+	{0x24, ServerBound, new(SetBeaconEffectPacketSB)},
+	{0x25, ServerBound, new(HeldItemChangePacketSB)},
+	{0x26, ServerBound, new(UpdateCommandBlockPacketSB)},
+	{0x27, ServerBound, new(UpdateCommandBlockMinecartPacketSB)},
+	{0x28, ServerBound, new(CreativeInventoryActionPacketSB)},
+	{0x29, ServerBound, new(UpdateJigsawBlockPacketSB)},
+	{0x2A, ServerBound, new(UpdateStructureBlockPacketSB)},
+	{0x2B, ServerBound, new(UpdateSignPacketSB)},
+	{0x2C, ServerBound, new(AnimationPacketSB)},
+	{0x2D, ServerBound, new(SpectatePacketSB)},
+	{0x2E, ServerBound, new(PlayerBlockPlacementPacketSB)},
+	{0x2F, ServerBound, new(UseItemPacketSB)},
+}
@@ -0,0 +1,593 @@
+package packets
+
+import (
+	"bytes"
+	"math/bits"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/Craftserve/potoq/packets/nbt"
+	"github.com/google/uuid"
+)
+
+// > generated codec (codec_gen.go) must be byte-identical to reflection codec
+
+// fills fields serialized by struct codec with random values allowed by their tags
+func randomFill(rnd *rand.Rand, data interface{}) {
+	randomValue(rnd, reflect.ValueOf(data).Elem(), "")
+}
+
+func randomStruct(rnd *rand.Rand, elem reflect.Value) {
+	elemType := elem.Type()
+	for i := 0; i < elem.NumField(); i++ {
+		if elemType.Field(i).Tag.Get("mcignore") == "" {
+			randomValue(rnd, elem.Field(i), elemType.Field(i).Tag)
+		}
+	}
+}
+
+func randomValue(rnd *rand.Rand, field reflect.Value, tag reflect.StructTag) {
+	switch field.Type() {
+	case typeBool:
+		field.SetBool(rnd.Intn(2) == 1)
+	case typeUint8, typeInt8, typeInt16, typeInt32, typeInt64, typeVarInt, typeVarLong, typeEntityID, typeAngle:
+		v := rnd.Uint64()
+		if rnd.Intn(2) == 0 {
+			v %= 5 // small values are used by when tags
+		}
+		if field.Kind() == reflect.Uint8 {
+			field.SetUint(v)
+		} else {
+			field.SetInt(int64(v))
+		}
+	case typeFloat32, typeFloat64:
+		field.SetFloat(float64(float32(rnd.NormFloat64() * 1000)))
+	case typeIdentifier, typeString:
+		maxlen := IdentifierMaxLength
+		if field.Type() == typeString {
+			maxlen, _ = strconv.Atoi(tag.Get("max_length"))
+		}
+		if tag.Get("format") == "chat" {
+			field.SetString(randomChat(rnd))
+		} else {
+			field.SetString(randomString(rnd, maxlen))
+		}
+	case typeIdentifiers:
+		ids := make([]Identifier, rnd.Intn(4)+1)
+		for i := range ids {
+			ids[i] = Identifier(randomString(rnd, 32))
+		}
+		field.Set(reflect.ValueOf(ids))
+	case typeBytes:
+		maxlen, _ := strconv.Atoi(tag.Get("max_length"))
+		if maxlen > 256 {
+			maxlen = 256
+		}
+		data := make([]byte, rnd.Intn(maxlen)+1)
+		rnd.Read(data)
+		field.SetBytes(data)
+	case typeUUID:
+		rnd.Read(field.Slice(0, field.Len()).Bytes())
+	case typeNBT:
+		// single key, so map order doesn't change output
+		field.Set(reflect.ValueOf(nbt.TagCompound{"name": nbt.TagString(randomString(rnd, 16))}))
+	case typeBlockPos:
+		field.Set(reflect.ValueOf(randomBlockPosition(rnd)))
+	case typeSectionPos:
+		field.Set(reflect.ValueOf(SectionPos{rnd.Int31n(1<<22) - 1<<21, rnd.Int31n(1<<20) - 1<<19, rnd.Int31n(1<<22) - 1<<21}))
+	case reflect.TypeOf(EntityMetadata(nil)):
+		field.Set(reflect.ValueOf(randomMetadata(rnd)))
+	case reflect.TypeOf([]EquipmentEntry(nil)):
+		// at least one entry, top bit of slot is continuation flag
+		equipment := make([]EquipmentEntry, rnd.Intn(int(equipmentSlots))+1)
+		for i := range equipment {
+			equipment[i].Slot = byte(rnd.Intn(int(equipmentSlots)))
+			randomStruct(rnd, reflect.ValueOf(&equipment[i].Item).Elem())
+		}
+		field.Set(reflect.ValueOf(equipment))
+	case reflect.TypeOf([]CommandNode(nil)):
+		field.Set(reflect.ValueOf(randomCommandNodes(rnd)))
+	case reflect.TypeOf(UpdateLightPacketCB{}):
+		// number of light arrays is given by masks
+		packet := field.Addr().Interface().(*UpdateLightPacketCB)
+		packet.ChunkX, packet.ChunkZ = VarInt(rnd.Int31()), VarInt(-rnd.Int31())
+		packet.TrustEdges = rnd.Intn(2) == 1
+		packet.EmptySkyLightMask, packet.EmptyBlockLightMask = VarInt(rnd.Intn(1<<18)), VarInt(rnd.Intn(1<<18))
+		packet.SkyLight = randomLightArrays(rnd, &packet.SkyLightMask)
+		packet.BlockLight = randomLightArrays(rnd, &packet.BlockLightMask)
+	case reflect.TypeOf([]Recipe(nil)):
+		field.Set(reflect.ValueOf(randomRecipes(rnd)))
+	default:
+		switch field.Kind() {
+		case reflect.Struct:
+			randomStruct(rnd, field)
+		case reflect.Slice:
+			n := rnd.Intn(4)
+			slice := reflect.MakeSlice(field.Type(), n, n)
+			for i := 0; i < n; i++ {
+				randomValue(rnd, slice.Index(i), tag)
+			}
+			field.Set(slice)
+		default:
+			panic("randomValue: unsupported type " + field.Type().String())
+		}
+	}
+}
+
+// root with literal and argument nodes, arguments use parsers with each kind of properties
+func randomCommandNodes(rnd *rand.Rand) []CommandNode {
+	parsers := []Identifier{"brigadier:double", "brigadier:float", "brigadier:integer", "brigadier:long",
+		"brigadier:string", "minecraft:entity", "minecraft:range", "minecraft:vec3"}
+	nodes := []CommandNode{{Flags: COMMAND_NODE_ROOT}}
+	for _, parser := range parsers {
+		literal := CommandNode{Flags: COMMAND_NODE_LITERAL, Name: randomString(rnd, 16)}
+		argument := CommandNode{Flags: COMMAND_NODE_ARGUMENT | COMMAND_NODE_EXECUTABLE, Name: randomString(rnd, 16), Parser: parser}
+		literal.Children = []VarInt{VarInt(len(nodes) + 1)}
+		nodes[0].Children = append(nodes[0].Children, VarInt(len(nodes)))
+		randomStruct(rnd, reflect.ValueOf(&argument.Properties).Elem())
+		argument.Properties.Min = float64(float32(argument.Properties.Min))
+		argument.Properties.Max = float64(float32(argument.Properties.Max))
+		if rnd.Intn(2) == 0 {
+			argument.Flags |= COMMAND_NODE_SUGGESTIONS
+			argument.SuggestionsType = "minecraft:ask_server"
+		}
+		if rnd.Intn(2) == 0 {
+			argument.Flags |= COMMAND_NODE_REDIRECT
+			argument.RedirectNode = 0
+		}
+		nodes = append(nodes, literal, argument)
+	}
+	return nodes
+}
+
+// sets random mask of 18 sections and returns array for each set bit
+func randomLightArrays(rnd *rand.Rand, mask *VarInt) [][]byte {
+	*mask = VarInt(rnd.Intn(1 << 18))
+	arrays := make([][]byte, bits.OnesCount32(uint32(*mask)))
+	for i := range arrays {
+		arrays[i] = make([]byte, lightArrayLength)
+		rnd.Read(arrays[i])
+	}
+	return arrays
+}
+
+// one recipe of each kind
+func randomRecipes(rnd *rand.Rand) []Recipe {
+	types := []Identifier{"crafting_shapeless", "minecraft:crafting_shaped", "minecraft:crafting_special_armordye",
+		"minecraft:smelting", "minecraft:campfire_cooking", "minecraft:stonecutting", "minecraft:smithing"}
+	ingredients := []int{rnd.Intn(4), 0, 0, 1, 1, 1, 2}
+	recipes := make([]Recipe, len(types))
+	for i := range recipes {
+		recipe := &recipes[i]
+		recipe.Type = types[i]
+		recipe.ID = Identifier(randomString(rnd, 32))
+		if i == 2 {
+			continue // no data
+		}
+		if i == 1 {
+			recipe.Width, recipe.Height = VarInt(rnd.Intn(3)+1), VarInt(rnd.Intn(3)+1)
+			ingredients[i] = int(recipe.Width * recipe.Height)
+		}
+		if i != 6 {
+			recipe.Group = randomString(rnd, 16)
+		}
+		for j := 0; j < ingredients[i]; j++ {
+			ingredient := make(Ingredient, rnd.Intn(3))
+			for k := range ingredient {
+				randomStruct(rnd, reflect.ValueOf(&ingredient[k]).Elem())
+			}
+			recipe.Ingredients = append(recipe.Ingredients, ingredient)
+		}
+		randomStruct(rnd, reflect.ValueOf(&recipe.Result).Elem())
+		if i == 3 || i == 4 {
+			recipe.Experience = float32(rnd.NormFloat64())
+			recipe.CookingTime = VarInt(rnd.Intn(400))
+		}
+	}
+	return recipes
+}
+
+// one entry of each type, optionals are absent at random
+func randomMetadata(rnd *rand.Rand) EntityMetadata {
+	var data EntityMetadata
+	for typ := METADATA_BYTE; typ <= METADATA_POSE; typ++ {
+		var value interface{}
+		switch typ {
+		case METADATA_BYTE:
+			value = int8(rnd.Intn(256))
+		case METADATA_VARINT, METADATA_DIRECTION, METADATA_OPT_BLOCK_ID, METADATA_OPT_VARINT, METADATA_POSE:
+			value = VarInt(rnd.Int31())
+		case METADATA_FLOAT:
+			value = float32(rnd.NormFloat64())
+		case METADATA_STRING:
+			value = randomString(rnd, 32)
+		case METADATA_CHAT:
+			value = randomChat(rnd)
+		case METADATA_BOOLEAN:
+			value = rnd.Intn(2) == 1
+		case METADATA_POSITION:
+			value = randomBlockPosition(rnd)
+		case METADATA_NBT:
+			value = nbt.TagCompound{"name": nbt.TagString(randomString(rnd, 16))}
+		case METADATA_OPT_CHAT:
+			if rnd.Intn(2) == 0 {
+				value = randomChat(rnd)
+			}
+		case METADATA_OPT_POSITION:
+			if rnd.Intn(2) == 0 {
+				value = randomBlockPosition(rnd)
+			}
+		case METADATA_OPT_UUID:
+			if rnd.Intn(2) == 0 {
+				var id uuid.UUID
+				rnd.Read(id[:])
+				value = id
+			}
+		case METADATA_SLOT:
+			var slot Slot
+			randomFill(rnd, &slot)
+			value = slot
+		case METADATA_ROTATION:
+			value = Rotation{1, 2, 3}
+		case METADATA_PARTICLE:
+			var particle Particle
+			randomFill(rnd, &particle)
+			particle.ID = []VarInt{0, 3, 14, 23, 34}[rnd.Intn(5)] // with each kind of data
+			value = particle
+		case METADATA_VILLAGER_DATA:
+			value = VillagerData{1, 2, 3}
+		}
+		data = append(data, MetadataEntry{Index: byte(rnd.Intn(255)), Type: typ, Value: value})
+	}
+	return data
+}
+
+// valid json chat, so JSON encoding of packet is lossless
+func randomChat(rnd *rand.Rand) string {
+	return `{"text":"` + randomString(rnd, 32) + `"}`
+}
+
+// coordinates in range of packed encoding
+func randomBlockPosition(rnd *rand.Rand) BlockPosition {
+	return BlockPosition{rnd.Int31n(1<<26) - 1<<25, rnd.Int31n(1<<12) - 1<<11, rnd.Int31n(1<<26) - 1<<25}
+}
+
+func randomString(rnd *rand.Rand, maxlen int) string {
+	if maxlen > 64 {
+		maxlen = 64
+	}
+	b := make([]byte, rnd.Intn(maxlen+1))
+	for i := range b {
+		b[i] = byte('a' + rnd.Intn(26))
+	}
+	return string(b)
+}
+
+func TestGeneratedCodecMatchesReflection(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, proto := range generatedCodecs {
+		typ := reflect.TypeOf(proto).Elem()
+		for n := 0; n < 50; n++ {
+			packet := reflect.New(typ).Interface()
+			randomFill(rnd, packet)
+
+			var want, got bytes.Buffer
+			if err := writeStructReflect(&want, packet); err != nil {
+				t.Fatalf("%s: reflection write: %s", typ.Name(), err)
+			}
+			// plainWriter hides io.ByteWriter, so wrapping is tested too
+			if err := packet.(Marshaler).MarshalPacket(plainWriter{&got}); err != nil {
+				t.Fatalf("%s: generated write: %s", typ.Name(), err)
+			}
+			if !bytes.Equal(got.Bytes(), want.Bytes()) {
+				t.Fatalf("%s: generated output differs\ngot:  %x\nwant: %x", typ.Name(), got.Bytes(), want.Bytes())
+			}
+
+			reflected := reflect.New(typ).Interface()
+			if err := readStructReflect(bytes.NewReader(want.Bytes()), reflected); err != nil {
+				t.Fatalf("%s: reflection read: %s", typ.Name(), err)
+			}
+			generated := reflect.New(typ).Interface()
+			if err := generated.(Unmarshaler).UnmarshalPacket(plainReader{bytes.NewReader(want.Bytes())}); err != nil {
+				t.Fatalf("%s: generated read: %s", typ.Name(), err)
+			}
+			if !reflect.DeepEqual(generated, reflected) {
+				t.Fatalf("%s: generated read differs\ngot:  %+v\nwant: %+v", typ.Name(), generated, reflected)
+			}
+			// fields skipped by when tags are zero after read, so parsed packet is compared with its own encoding
+			var again bytes.Buffer
+			if err := generated.(Marshaler).MarshalPacket(&again); err != nil {
+				t.Fatalf("%s: generated write of parsed packet: %s", typ.Name(), err)
+			}
+			if !bytes.Equal(again.Bytes(), want.Bytes()) {
+				t.Fatalf("%s: round trip differs\ngot:  %x\nwant: %x", typ.Name(), again.Bytes(), want.Bytes())
+			}
+		}
+	}
+}
+
+// every packet of play.spec is registered and survives Serialize -> Parse -> Serialize
+func TestSpecPackets(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, entry := range specPackets {
+		typ := reflect.TypeOf(entry.packet).Elem()
+		registered := NewPacket(entry.id, PLAY, entry.direction)
+		if reflect.TypeOf(registered) != reflect.TypeOf(entry.packet) {
+			t.Fatalf("%s_%02X: registered %T, want %s", entry.direction, entry.id, registered, typ.Name())
+		}
+		if entry.packet.PacketID() != entry.id || entry.packet.Direction() != entry.direction {
+			t.Fatalf("%s: id %02X %s, spec says %02X %s", typ.Name(),
+				entry.packet.PacketID(), entry.packet.Direction(), entry.id, entry.direction)
+		}
+
+		for n := 0; n < 20; n++ {
+			packet := reflect.New(typ).Interface().(Packet)
+			randomFill(rnd, packet)
+			var first, second bytes.Buffer
+			if err := packet.Serialize(&first); err != nil {
+				t.Fatalf("%s: serialize: %s", typ.Name(), err)
+			}
+			parsed := reflect.New(typ).Interface().(Packet)
+			if err := parsed.Parse(bytes.NewReader(first.Bytes())); err != nil {
+				t.Fatalf("%s: parse: %s\ndata: %x", typ.Name(), err, first.Bytes())
+			}
+			if err := parsed.Serialize(&second); err != nil {
+				t.Fatalf("%s: serialize of parsed packet: %s", typ.Name(), err)
+			}
+			if !bytes.Equal(first.Bytes(), second.Bytes()) {
+				t.Fatalf("%s: round trip differs\ngot:  %x\nwant: %x", typ.Name(), second.Bytes(), first.Bytes())
+			}
+		}
+	}
+}
+
+// hides io.ByteWriter implementation
+type plainWriter struct {
+	w *bytes.Buffer
+}
+
+func (p plainWriter) Write(b []byte) (int, error) {
+	return p.w.Write(b)
+}
+
+func BenchmarkWriteStructReflectPosition(b *testing.B) {
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := writeStructReflect(&buf, benchPosition); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadStructReflectPosition(b *testing.B) {
+	var buf bytes.Buffer
+	if err := benchPosition.Serialize(&buf); err != nil {
+		b.Fatal(err)
+	}
+	r := newLoopReader(buf.Bytes())
+	packet := new(PlayerPositionAndLookPacketCB)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := readStructReflect(r, packet); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// encoding of tag language (when, optional, counted arrays) checked with bytes written by hand
+func TestTagLanguageEncoding(t *testing.T) {
+	id := uuid.UUID{1, 2, 3}
+	list := &PlayerListItemPacketCB{Action: ADD_PLAYER, Items: []PlayerListItem{{
+		UUID:       id,
+		Name:       "gracz",
+		Properties: []AuthProperty{{Name: "textures", Value: "v"}},
+		GameMode:   1,
+		Ping:       20,
+	}}}
+	var want bytes.Buffer
+	WriteVarInt(&want, ADD_PLAYER)
+	WriteVarInt(&want, 1)
+	want.Write(id[:])
+	WriteMinecraftString(&want, "gracz")
+	WriteVarInt(&want, 1)
+	WriteMinecraftString(&want, "textures")
+	WriteMinecraftString(&want, "v")
+	WriteBool(&want, false) // no signature
+	WriteVarInt(&want, 1)
+	WriteVarInt(&want, 20)
+	WriteBool(&want, false) // no display name
+	checkEncoding(t, list, want.Bytes())
+
+	list = &PlayerListItemPacketCB{Action: UPDATE_DISPLAY_NAME, Items: []PlayerListItem{{UUID: id, DisplayName: `"x"`}}}
+	want.Reset()
+	WriteVarInt(&want, UPDATE_DISPLAY_NAME)
+	WriteVarInt(&want, 1)
+	want.Write(id[:])
+	WriteBool(&want, true)
+	WriteMinecraftString(&want, `"x"`)
+	checkEncoding(t, list, want.Bytes())
+
+	teams := &TeamsPacketCB{Name: "red", Mode: TEAM_ADD_ENTITIES, Players: []string{"a", "b"}}
+	want.Reset()
+	WriteMinecraftString(&want, "red")
+	WriteUnsignedByte(&want, TEAM_ADD_ENTITIES)
+	WriteVarInt(&want, 2)
+	WriteMinecraftString(&want, "a")
+	WriteMinecraftString(&want, "b")
+	checkEncoding(t, teams, want.Bytes())
+
+	teams.Players = make([]string, 1025)
+	if err := writeStructReflect(new(bytes.Buffer), teams); err == nil {
+		t.Fatal("array longer than max_count written")
+	}
+}
+
+func checkEncoding(t *testing.T, packet Packet, want []byte) {
+	var got bytes.Buffer
+	if err := writeStructReflect(&got, packet); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Fatalf("%T encoding\ngot:  %x\nwant: %x", packet, got.Bytes(), want)
+	}
+	parsed := reflect.New(reflect.TypeOf(packet).Elem()).Interface()
+	if err := readStructReflect(bytes.NewReader(want), parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, packet) {
+		t.Fatalf("%T parsed\ngot:  %+v\nwant: %+v", packet, parsed, packet)
+	}
+}
+
+func TestParseFieldOffsets(t *testing.T) {
+	var buf bytes.Buffer
+	(&ClickWindowPacketSB{WindowID: 1, Slot: 5, ClickedItem: Slot{Present: true, ItemID: 1, Count: 1}}).Serialize(&buf)
+	data := buf.Bytes()
+
+	offsets, err := ParseFieldOffsets(new(ClickWindowPacketSB), data[:len(data)-2])
+	if err == nil {
+		t.Fatal("truncated packet parsed")
+	}
+	last := offsets[len(offsets)-1]
+	if last.Name != "ClickedItem" || last.Offset != 7 {
+		t.Fatalf("failed at %+v, offsets %+v", last, offsets)
+	}
+	if _, err := ParseFieldOffsets(new(ClickWindowPacketSB), append(data, 0)); err == nil {
+		t.Fatal("trailing byte not reported")
+	}
+}
+
+// > []byte fields and tag checks
+
+func TestBytesFieldLimits(t *testing.T) {
+	// claimed length is checked against data actually read, not allocated upfront
+	var claimed bytes.Buffer
+	WriteVarInt(&claimed, 2097151)
+	claimed.Write([]byte{1, 2, 3})
+	checkAlloc(t, func() {
+		for n := 0; n < 100; n++ {
+			if _, err := readBytesField(bytes.NewReader(claimed.Bytes()), "VarInt", 2097151); err == nil {
+				t.Fatal("truncated []byte field read")
+			}
+		}
+	})
+
+	response := &EncryptionResponsePacket{Secret: make([]byte, 1025), Token: make([]byte, 4)}
+	if err := response.MarshalPacket(new(bytes.Buffer)); err == nil {
+		t.Fatal("generated codec wrote []byte longer than max_length")
+	}
+	if err := writeStructReflect(new(bytes.Buffer), response); err == nil {
+		t.Fatal("reflection codec wrote []byte longer than max_length")
+	}
+	response.Secret = response.Secret[:1024]
+	if err := writeStructReflect(new(bytes.Buffer), response); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArrayFieldLimits(t *testing.T) {
+	// claimed count of elements isn't allocated upfront, by both codecs
+	var claimed bytes.Buffer
+	claimed.Write([]byte{0, 0, 0})
+	WriteVarInt(&claimed, 32767)
+	claimed.Write([]byte{1, 'a'})
+	checkAlloc(t, func() {
+		for n := 0; n < 100; n++ {
+			if err := ReadMinecraftStruct(bytes.NewReader(claimed.Bytes()), new(TabCompletePacketCB)); err == nil {
+				t.Fatal("generated codec read truncated array")
+			}
+			if err := readStructReflect(bytes.NewReader(claimed.Bytes()), new(TabCompletePacketCB)); err == nil {
+				t.Fatal("reflection codec read truncated array")
+			}
+		}
+	})
+}
+
+// invalid tags are returned as errors by reflection codec, without reading anything
+func TestInvalidTags(t *testing.T) {
+	type inner struct {
+		Value VarInt `when:"Mode==1"`
+	}
+	for _, data := range []interface{}{
+		&struct {
+			Text string `max_length:"x"`
+		}{},
+		&struct {
+			Data []byte `max_length:"10" length_prefix:"byte"`
+		}{},
+		&struct {
+			Data []byte `max_length:"10" length_prefix:"eof"`
+			Next VarInt
+		}{},
+		&struct {
+			Items []VarInt `length_prefix:"VarInt"`
+		}{},
+		&struct {
+			ID EntityID `datatype:"int8"`
+		}{},
+		&struct {
+			Mode  VarInt
+			Value VarInt `when:"Mode=1"`
+		}{},
+		&struct {
+			Mode  string `max_length:"10"`
+			Value VarInt `when:"Mode==1"`
+		}{},
+		&struct {
+			Nested inner
+		}{},
+		&struct {
+			Value uint64
+		}{},
+	} {
+		if err := readStructReflect(bytes.NewReader(make([]byte, 16)), data); err == nil {
+			t.Errorf("%T read without error", data)
+		}
+		if err := writeStructReflect(new(bytes.Buffer), data); err == nil {
+			t.Errorf("%T written without error", data)
+		}
+	}
+
+	// when tag can refer to field of struct containing this one
+	valid := &struct {
+		Mode   VarInt
+		Nested inner
+	}{Mode: 1, Nested: inner{Value: 5}}
+	var buf bytes.Buffer
+	if err := writeStructReflect(&buf, valid); err != nil || !bytes.Equal(buf.Bytes(), []byte{1, 5}) {
+		t.Fatalf("written %x: %v", buf.Bytes(), err)
+	}
+}
+
+func TestEntityEquipment(t *testing.T) {
+	packet := &EntityEquipmentPacketCB{EntityID: 3, Equipment: []EquipmentEntry{
+		{Slot: EQUIPMENT_MAIN_HAND, Item: Slot{Present: true, ItemID: 1, Count: 2}},
+		{Slot: EQUIPMENT_HELMET},
+	}}
+	want := []byte{3, 0x80 | EQUIPMENT_MAIN_HAND, 1, 1, 2, 0, EQUIPMENT_HELMET, 0}
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil || !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("serialized %x: %v, expected %x", buf.Bytes(), err, want)
+	}
+	var parsed EntityEquipmentPacketCB
+	if err := parsed.Parse(bytes.NewReader(want)); err != nil || !reflect.DeepEqual(&parsed, packet) {
+		t.Fatalf("parsed %+v: %v", parsed, err)
+	}
+
+	// continuation bit set in every entry
+	endless := []byte{3}
+	for i := 0; i <= int(equipmentSlots); i++ {
+		endless = append(endless, 0x80, 0)
+	}
+	if err := parsed.Parse(bytes.NewReader(endless)); err == nil {
+		t.Fatal("more entries than equipment slots parsed")
+	}
+	if err := (&EntityEquipmentPacketCB{EntityID: 3}).Serialize(new(bytes.Buffer)); err == nil {
+		t.Fatal("packet without entries serialized")
+	}
+}
@@ -6,50 +6,71 @@ import (
 	"io"
 )
 
-// AES CFB-8, version from stdlib is not working
+// AES CFB-8, stdlib has only CFB with full block segments.
+// Every byte needs its own block encryption, so instead of shifting IV after each byte
+// ciphertext is appended to shift register and IV is just a window sliding over it.
+
+const cfb8Chunk = 4096 // bytes processed before shift register is rewound
 
 type cfb8 struct {
-	c                cipher.Block
-	blockSize        int
-	iv, iv_real, tmp []byte
-	de               bool
+	b       cipher.Block
+	reg     []byte // IV followed by space for cfb8Chunk bytes of ciphertext
+	tmp     []byte
+	decrypt bool
+}
+
+// Returns cipher.Stream which encrypts with AES/CFB8 used by minecraft protocol
+func NewCFB8Encrypter(block cipher.Block, iv []byte) cipher.Stream {
+	return newCFB8(block, iv, false)
 }
 
-func newCFB8(c cipher.Block, iv []byte, decrypt bool) *cfb8 {
-	if len(iv) != 16 {
+// Returns cipher.Stream which decrypts with AES/CFB8 used by minecraft protocol
+func NewCFB8Decrypter(block cipher.Block, iv []byte) cipher.Stream {
+	return newCFB8(block, iv, true)
+}
+
+func newCFB8(block cipher.Block, iv []byte, decrypt bool) *cfb8 {
+	bs := block.BlockSize()
+	if len(iv) != bs {
 		panic("bad iv length!")
 	}
-	cp := make([]byte, 256)
-	copy(cp, iv)
+	reg := make([]byte, bs+cfb8Chunk)
+	copy(reg, iv)
 	return &cfb8{
-		c:         c,
-		blockSize: c.BlockSize(),
-		iv:        cp[:16],
-		iv_real:   cp,
-		tmp:       make([]byte, 16),
-		de:        decrypt,
+		b:       block,
+		reg:     reg,
+		tmp:     make([]byte, bs),
+		decrypt: decrypt,
 	}
 }
 
-func (cf *cfb8) XORKeyStream(dst, src []byte) {
-	for i := 0; i < len(src); i++ {
-		val := src[i]
-		cf.c.Encrypt(cf.tmp, cf.iv)
-		val = val ^ cf.tmp[0]
-
-		if cap(cf.iv) >= 17 {
-			cf.iv = cf.iv[1:17]
-		} else {
-			copy(cf.iv_real, cf.iv[1:])
-			cf.iv = cf.iv_real[:16]
+func (x *cfb8) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("cfb8: output smaller than input")
+	}
+	bs := len(x.reg) - cfb8Chunk
+	for len(src) > 0 {
+		n := len(src)
+		if n > cfb8Chunk {
+			n = cfb8Chunk
 		}
-
-		if cf.de {
-			cf.iv[15] = src[i]
+		reg, tmp := x.reg[:bs+n], x.tmp
+		if x.decrypt {
+			for i, c := range src[:n] {
+				x.b.Encrypt(tmp, reg[i:i+bs])
+				reg[i+bs] = c
+				dst[i] = c ^ tmp[0]
+			}
 		} else {
-			cf.iv[15] = val
+			for i, p := range src[:n] {
+				x.b.Encrypt(tmp, reg[i:i+bs])
+				c := p ^ tmp[0]
+				reg[i+bs] = c
+				dst[i] = c
+			}
 		}
-		dst[i] = val
+		copy(x.reg, reg[n:])
+		dst, src = dst[n:], src[n:]
 	}
 }
 
@@ -58,7 +79,7 @@ func (cf *cfb8) XORKeyStream(dst, src []byte) {
 type encryptedReader struct {
 	// implements io.Reader with minecraft protocol decryption
 	r         io.Reader
-	decrypter *cfb8
+	decrypter cipher.Stream
 }
 
 func NewEncryptedReader(r io.Reader, secret []byte) (reader io.Reader, err error) {
@@ -67,17 +88,14 @@ func NewEncryptedReader(r io.Reader, secret []byte) (reader io.Reader, err error
 	if err != nil {
 		return
 	}
-	decrypter := newCFB8(block, secret, true)
+	decrypter := NewCFB8Decrypter(block, secret)
 	reader = &encryptedReader{r, decrypter}
 	return
 }
 
 func (reader *encryptedReader) Read(p []byte) (n int, err error) {
 	n, err = reader.r.Read(p)
-	if err != nil {
-		return
-	}
-	reader.decrypter.XORKeyStream(p[:n], p[:n])
+	reader.decrypter.XORKeyStream(p[:n], p[:n]) // n > 0 is possible with err
 	return
 }
 
@@ -86,7 +104,7 @@ func (reader *encryptedReader) Read(p []byte) (n int, err error) {
 type encryptedWriter struct {
 	// implements io.Writer with minecraft protocol decryption
 	w         io.Writer
-	encrypter *cfb8
+	encrypter cipher.Stream
 }
 
 func NewEncryptedWriter(w io.Writer, secret []byte) (writer io.Writer, err error) {
@@ -95,7 +113,7 @@ func NewEncryptedWriter(w io.Writer, secret []byte) (writer io.Writer, err error
 	if err != nil {
 		return
 	}
-	encrypter := newCFB8(block, secret, false)
+	encrypter := NewCFB8Encrypter(block, secret)
 	writer = &encryptedWriter{w, encrypter}
 	return
 }
@@ -0,0 +1,128 @@
+package packets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"math/rand"
+	"testing"
+)
+
+// previous implementation, IV shifted after every byte
+type legacyCFB8 struct {
+	c                cipher.Block
+	blockSize        int
+	iv, iv_real, tmp []byte
+	de               bool
+}
+
+func newLegacyCFB8(c cipher.Block, iv []byte, decrypt bool) *legacyCFB8 {
+	cp := make([]byte, 256)
+	copy(cp, iv)
+	return &legacyCFB8{
+		c:         c,
+		blockSize: c.BlockSize(),
+		iv:        cp[:16],
+		iv_real:   cp,
+		tmp:       make([]byte, 16),
+		de:        decrypt,
+	}
+}
+
+func (cf *legacyCFB8) XORKeyStream(dst, src []byte) {
+	for i := 0; i < len(src); i++ {
+		val := src[i]
+		cf.c.Encrypt(cf.tmp, cf.iv)
+		val = val ^ cf.tmp[0]
+
+		if cap(cf.iv) >= 17 {
+			cf.iv = cf.iv[1:17]
+		} else {
+			copy(cf.iv_real, cf.iv[1:])
+			cf.iv = cf.iv_real[:16]
+		}
+
+		if cf.de {
+			cf.iv[15] = src[i]
+		} else {
+			cf.iv[15] = val
+		}
+		dst[i] = val
+	}
+}
+
+func testCipher(t testing.TB) (cipher.Block, []byte) {
+	key := make([]byte, 16)
+	rand.New(rand.NewSource(1)).Read(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return block, key
+}
+
+func TestCFB8MatchesLegacy(t *testing.T) {
+	block, iv := testCipher(t)
+	rnd := rand.New(rand.NewSource(2))
+	data := make([]byte, 3*cfb8Chunk+123)
+	rnd.Read(data)
+
+	for _, decrypt := range []bool{false, true} {
+		legacy := newLegacyCFB8(block, iv, decrypt)
+		stream := newCFB8(block, iv, decrypt)
+
+		want := make([]byte, len(data))
+		legacy.XORKeyStream(want, data)
+
+		// random chunks, in place
+		got := append([]byte(nil), data...)
+		for rest := got; len(rest) > 0; {
+			n := rnd.Intn(2*cfb8Chunk) + 1
+			if n > len(rest) {
+				n = len(rest)
+			}
+			stream.XORKeyStream(rest[:n], rest[:n])
+			rest = rest[n:]
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("decrypt=%t: output differs from legacy implementation", decrypt)
+		}
+	}
+}
+
+func TestCFB8RoundTrip(t *testing.T) {
+	block, iv := testCipher(t)
+	data := []byte("minecraft protocol encryption test")
+	enc := make([]byte, len(data))
+	NewCFB8Encrypter(block, iv).XORKeyStream(enc, data)
+	dec := make([]byte, len(data))
+	NewCFB8Decrypter(block, iv).XORKeyStream(dec, enc)
+	if !bytes.Equal(dec, data) {
+		t.Fatalf("round trip failed: %q", dec)
+	}
+}
+
+func benchmarkStream(b *testing.B, stream cipher.Stream) {
+	buf := make([]byte, 32*1024)
+	b.SetBytes(int64(len(buf)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream.XORKeyStream(buf, buf)
+	}
+}
+
+func BenchmarkCFB8Legacy(b *testing.B) {
+	block, iv := testCipher(b)
+	benchmarkStream(b, newLegacyCFB8(block, iv, false))
+}
+
+func BenchmarkCFB8Encrypt(b *testing.B) {
+	block, iv := testCipher(b)
+	benchmarkStream(b, NewCFB8Encrypter(block, iv))
+}
+
+func BenchmarkCFB8Decrypt(b *testing.B) {
+	block, iv := testCipher(b)
+	benchmarkStream(b, NewCFB8Decrypter(block, iv))
+}
@@ -1,123 +1,358 @@
 package packets
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/Craftserve/potoq/packets/nbt"
+	"github.com/google/uuid"
 	"io"
+	"reflect"
 )
 
-type EntityMetadata map[byte]interface{}
+// types of entity metadata values (1.16)
+const (
+	METADATA_BYTE          VarInt = iota // int8
+	METADATA_VARINT                      // VarInt
+	METADATA_FLOAT                       // float32
+	METADATA_STRING                      // string
+	METADATA_CHAT                        // string, json chat
+	METADATA_OPT_CHAT                    // string or nil
+	METADATA_SLOT                        // Slot
+	METADATA_BOOLEAN                     // bool
+	METADATA_ROTATION                    // Rotation
+	METADATA_POSITION                    // BlockPosition
+	METADATA_OPT_POSITION                // BlockPosition or nil
+	METADATA_DIRECTION                   // VarInt
+	METADATA_OPT_UUID                    // uuid.UUID or nil
+	METADATA_OPT_BLOCK_ID                // VarInt, 0 is absent
+	METADATA_NBT                         // nbt.TagCompound
+	METADATA_PARTICLE                    // Particle
+	METADATA_VILLAGER_DATA               // VillagerData
+	METADATA_OPT_VARINT                  // VarInt, 0 is absent, otherwise value + 1
+	METADATA_POSE                        // VarInt
+)
+
+const metadataEnd = 0xFF
 
-func NewEntityMetadata() EntityMetadata {
-	return make(EntityMetadata)
+type MetadataEntry struct {
+	Index byte
+	Type  VarInt      // METADATA_*
+	Value interface{} // Go type depends on Type, see METADATA_* constants
 }
 
-//TODO new parser and serializer
-func (data EntityMetadata) Parse(reader io.Reader) (err error) {
-	var item, index, dataType byte
+// Entries are kept in order they were sent
+type EntityMetadata []MetadataEntry
+
+func (data *EntityMetadata) Parse(reader io.Reader) (err error) {
+	br := asByter(reader)
+	*data = (*data)[:0]
 	for {
-		item, err = ReadUnsignedByte(reader)
-		if err != nil {
+		var index byte
+		if index, err = ReadUnsignedByte(br); err != nil || index == metadataEnd {
 			return
 		}
-		if item == 127 {
+		if len(*data) >= metadataEnd {
+			return errors.New("EntityMetadata: too many entries")
+		}
+		entry := MetadataEntry{Index: index}
+		if entry.Type, err = ReadVarIntFrom(br); err != nil {
 			return
 		}
+		if entry.Value, err = readMetadataValue(br, entry.Type); err != nil {
+			return fmt.Errorf("EntityMetadata index %d: %w", index, err)
+		}
+		*data = append(*data, entry)
+	}
+}
 
-		index = item & 0x1F
-		dataType = item >> 5
-
-		switch dataType {
-		case 0:
-			data[index], err = ReadByte(reader) // !!!!!! reads int8 !!!!!!!!!!
-		case 1:
-			data[index], err = ReadVarInt(reader)
-		case 2:
-			data[index], err = ReadFloat(reader)
-		case 3:
-			data[index], err = ReadMinecraftString(reader, 255)
-		case 4:
-			data[index], err = ReadMinecraftString(reader, 255) //chat
-		case 5:
-			//data[index], err = chatoptions
-		case 6:
-			item := new(ItemData)
-			err = item.Parse(reader)
-			data[index] = item
-		case 7:
-			data[index], err = ReadBool(reader)
-		case 8:
-			vector := new(Vector)
-			vector.X, _ = ReadInt(reader)
-			vector.Y, _ = ReadInt(reader)
-			vector.Z, err = ReadInt(reader)
-			data[index] = vector
-		case 9:
-			//Position
-		case 10:
-			//optional Position
-		case 11:
-			data[index], err = ReadVarInt(reader)
-		case 12:
-			//optuuid
-		case 13:
-			data[index], err = ReadVarInt(reader)
-		default:
-			panic(fmt.Sprintf("Unkown dataType in EntityMetadata.Parse: %X %X %X", item, index, dataType))
+func (data EntityMetadata) Serialize(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	for _, entry := range data {
+		if entry.Index == metadataEnd {
+			return fmt.Errorf("EntityMetadata: invalid index %d", entry.Index)
+		}
+		if err = WriteUnsignedByte(writer, entry.Index); err != nil {
+			return
 		}
-		if err != nil {
+		if err = WriteVarInt(writer, entry.Type); err != nil {
 			return
 		}
+		if err = writeMetadataValue(writer, entry.Type, entry.Value); err != nil {
+			return fmt.Errorf("EntityMetadata index %d: %w", entry.Index, err)
+		}
 	}
-	return
+	return WriteUnsignedByte(writer, metadataEnd)
 }
 
-func (data EntityMetadata) Serialize(writer io.Writer) (err error) {
-	for index, value := range data {
-		switch value.(type) {
-		case int8:
-			WriteUnsignedByte(writer, index|(0<<5))
-			err = WriteByte(writer, value.(int8))
-		case int16:
-			WriteUnsignedByte(writer, index|(1<<5))
-			err = WriteShort(writer, value.(int16))
-		case int32:
-			WriteUnsignedByte(writer, index|(2<<5))
-			err = WriteInt(writer, value.(int32))
-		case float32:
-			WriteUnsignedByte(writer, index|(3<<5))
-			err = WriteFloat(writer, value.(float32))
+// Returns entry with given index
+func (data EntityMetadata) Get(index byte) (MetadataEntry, bool) {
+	for _, entry := range data {
+		if entry.Index == index {
+			return entry, true
+		}
+	}
+	return MetadataEntry{}, false
+}
+
+// Go types of values, by METADATA_* type
+var metadataTypes = [...]reflect.Type{
+	METADATA_BYTE:          typeInt8,
+	METADATA_VARINT:        typeVarInt,
+	METADATA_FLOAT:         typeFloat32,
+	METADATA_STRING:        typeString,
+	METADATA_CHAT:          typeString,
+	METADATA_OPT_CHAT:      typeString,
+	METADATA_SLOT:          reflect.TypeOf(Slot{}),
+	METADATA_BOOLEAN:       typeBool,
+	METADATA_ROTATION:      reflect.TypeOf(Rotation{}),
+	METADATA_POSITION:      typeBlockPos,
+	METADATA_OPT_POSITION:  typeBlockPos,
+	METADATA_DIRECTION:     typeVarInt,
+	METADATA_OPT_UUID:      typeUUID,
+	METADATA_OPT_BLOCK_ID:  typeVarInt,
+	METADATA_NBT:           typeNBT,
+	METADATA_PARTICLE:      reflect.TypeOf(Particle{}),
+	METADATA_VILLAGER_DATA: reflect.TypeOf(VillagerData{}),
+	METADATA_OPT_VARINT:    typeVarInt,
+	METADATA_POSE:          typeVarInt,
+}
+
+func metadataFormat(typ VarInt) string {
+	if typ == METADATA_CHAT || typ == METADATA_OPT_CHAT {
+		return "chat"
+	}
+	return ""
+}
+
+// Entries are encoded as [{"Index":0,"Type":0,"Value":...}], absent optional values are null
+func (data EntityMetadata) MarshalJSON() ([]byte, error) {
+	if data == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, entry := range data {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `{"Index":%d,"Type":%d,"Value":`, entry.Index, entry.Type)
+		if entry.Value == nil {
+			buf.WriteString("null")
+		} else if err := marshalJSONValue(&buf, reflect.ValueOf(entry.Value), metadataFormat(entry.Type)); err != nil {
+			return nil, fmt.Errorf("EntityMetadata index %d: %w", entry.Index, err)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+func (data *EntityMetadata) UnmarshalJSON(raw []byte) error {
+	var entries []struct {
+		Index byte
+		Type  VarInt
+		Value json.RawMessage
+	}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return err
+	}
+	if entries == nil {
+		*data = nil
+		return nil
+	}
+	*data = make(EntityMetadata, len(entries))
+	for i, entry := range entries {
+		if entry.Type < 0 || int(entry.Type) >= len(metadataTypes) {
+			return fmt.Errorf("EntityMetadata index %d: unknown type %d", entry.Index, entry.Type)
+		}
+		(*data)[i] = MetadataEntry{Index: entry.Index, Type: entry.Type}
+		if bytes.Equal(entry.Value, []byte("null")) && entry.Type != METADATA_CHAT {
+			continue // absent optional
+		}
+		value := reflect.New(metadataTypes[entry.Type]).Elem()
+		if err := unmarshalJSONValue(entry.Value, value, metadataFormat(entry.Type)); err != nil {
+			return fmt.Errorf("EntityMetadata index %d: %w", entry.Index, err)
+		}
+		(*data)[i].Value = value.Interface()
+	}
+	return nil
+}
+
+func readMetadataValue(br byter, typ VarInt) (interface{}, error) {
+	switch typ {
+	case METADATA_BYTE:
+		return ReadByte(br)
+	case METADATA_VARINT, METADATA_DIRECTION, METADATA_OPT_BLOCK_ID, METADATA_OPT_VARINT, METADATA_POSE:
+		return ReadVarIntFrom(br)
+	case METADATA_FLOAT:
+		return ReadFloat(br)
+	case METADATA_STRING:
+		return ReadMinecraftString(br, 32767)
+	case METADATA_CHAT:
+		return ReadMinecraftString(br, 262144)
+	case METADATA_BOOLEAN:
+		return ReadBool(br)
+	case METADATA_POSITION:
+		return ReadBlockPosition(br)
+	case METADATA_NBT:
+		return readNBTField(br)
+	case METADATA_OPT_CHAT, METADATA_OPT_POSITION, METADATA_OPT_UUID:
+		present, err := ReadBool(br)
+		if err != nil || !present {
+			return nil, err
+		}
+		switch typ {
+		case METADATA_OPT_CHAT:
+			return ReadMinecraftString(br, 262144)
+		case METADATA_OPT_POSITION:
+			return ReadBlockPosition(br)
+		}
+		var id uuid.UUID
+		_, err = io.ReadFull(br, id[:])
+		return id, err
+	case METADATA_SLOT:
+		var slot Slot
+		err := slot.Parse(br)
+		return slot, err
+	case METADATA_ROTATION:
+		var rotation Rotation
+		err := ReadMinecraftStruct(br, &rotation)
+		return rotation, err
+	case METADATA_PARTICLE:
+		var particle Particle
+		err := ReadMinecraftStruct(br, &particle)
+		return particle, err
+	case METADATA_VILLAGER_DATA:
+		var villager VillagerData
+		err := ReadMinecraftStruct(br, &villager)
+		return villager, err
+	}
+	return nil, fmt.Errorf("unknown type %d", typ)
+}
+
+func writeMetadataValue(writer io.Writer, typ VarInt, value interface{}) (err error) {
+	ok := true
+	switch typ {
+	case METADATA_BYTE:
+		var v int8
+		if v, ok = value.(int8); ok {
+			err = WriteByte(writer, v)
+		}
+	case METADATA_VARINT, METADATA_DIRECTION, METADATA_OPT_BLOCK_ID, METADATA_OPT_VARINT, METADATA_POSE:
+		var v VarInt
+		if v, ok = value.(VarInt); ok {
+			err = WriteVarInt(writer, v)
+		}
+	case METADATA_FLOAT:
+		var v float32
+		if v, ok = value.(float32); ok {
+			err = WriteFloat(writer, v)
+		}
+	case METADATA_STRING, METADATA_CHAT:
+		var v string
+		if v, ok = value.(string); ok {
+			err = WriteMinecraftString(writer, v)
+		}
+	case METADATA_BOOLEAN:
+		var v bool
+		if v, ok = value.(bool); ok {
+			err = WriteBool(writer, v)
+		}
+	case METADATA_POSITION:
+		var v BlockPosition
+		if v, ok = value.(BlockPosition); ok {
+			err = WriteBlockPosition(writer, v)
+		}
+	case METADATA_NBT:
+		var v nbt.TagCompound
+		if v, ok = value.(nbt.TagCompound); ok {
+			err = writeNBTField(writer, v)
+		}
+	case METADATA_OPT_CHAT, METADATA_OPT_POSITION, METADATA_OPT_UUID:
+		if value == nil {
+			return WriteBool(writer, false)
+		}
+		switch v := value.(type) {
 		case string:
-			WriteUnsignedByte(writer, index|(4<<5))
-			err = WriteMinecraftString(writer, value.(string))
-		case *ItemData:
-			WriteUnsignedByte(writer, index|(5<<5))
-			item := value.(*ItemData)
-			err = item.Serialize(writer)
-		case *Vector:
-			WriteUnsignedByte(writer, index|(6<<5))
-			vector := value.(*Vector)
-			WriteInt(writer, vector.X)
-			WriteInt(writer, vector.Y)
-			err = WriteInt(writer, vector.Z)
+			if ok = typ == METADATA_OPT_CHAT; ok {
+				if err = WriteBool(writer, true); err == nil {
+					err = WriteMinecraftString(writer, v)
+				}
+			}
+		case BlockPosition:
+			if ok = typ == METADATA_OPT_POSITION; ok {
+				if err = WriteBool(writer, true); err == nil {
+					err = WriteBlockPosition(writer, v)
+				}
+			}
+		case uuid.UUID:
+			if ok = typ == METADATA_OPT_UUID; ok {
+				if err = WriteBool(writer, true); err == nil {
+					_, err = writer.Write(v[:])
+				}
+			}
 		default:
-			panic(fmt.Sprintf("Unkown type in EntityMetadata.Serialize for %d: %T", index, value))
+			ok = false
 		}
-		if err != nil {
-			return
+	case METADATA_SLOT:
+		var v Slot
+		if v, ok = value.(Slot); ok {
+			err = v.Serialize(writer)
+		}
+	case METADATA_ROTATION:
+		var v Rotation
+		if v, ok = value.(Rotation); ok {
+			err = WriteMinecraftStruct(writer, &v)
+		}
+	case METADATA_PARTICLE:
+		var v Particle
+		if v, ok = value.(Particle); ok {
+			err = WriteMinecraftStruct(writer, &v)
+		}
+	case METADATA_VILLAGER_DATA:
+		var v VillagerData
+		if v, ok = value.(VillagerData); ok {
+			err = WriteMinecraftStruct(writer, &v)
 		}
+	default:
+		return fmt.Errorf("unknown type %d", typ)
+	}
+	if !ok {
+		return fmt.Errorf("type %d can't hold %T", typ, value)
 	}
-	err = WriteUnsignedByte(writer, 0xFF)
 	return
 }
 
-type Vector struct {
-	X int32
-	Y int32
-	Z int32
+type Rotation struct {
+	X, Y, Z float32
+}
+
+// Particle with its data, also sent in ParticlePacketCB
+type Particle struct {
+	ID   VarInt
+	Data ParticleData
+}
+
+// Fields depend on ID of particle (1.16 registry) in parent struct
+type ParticleData struct {
+	BlockState VarInt  `when:"ID==3,23"` // block, falling_dust
+	Red        float32 `when:"ID==14"`   // dust
+	Green      float32 `when:"ID==14"`
+	Blue       float32 `when:"ID==14"`
+	Scale      float32 `when:"ID==14"`
+	Item       Slot    `when:"ID==34"` // item
+}
+
+type VillagerData struct {
+	Type       VarInt
+	Profession VarInt
+	Level      VarInt
 }
 
 type AuthProperty struct {
-	Name      string `json:"name"`
-	Value     string `json:"value"`
-	Signature string `json:"signature"`
+	Name      string `json:"name" max_length:"32767"`
+	Value     string `json:"value" max_length:"32767"`
+	Signature string `json:"signature" max_length:"32767" optional:"bool"`
 }
@@ -0,0 +1,51 @@
+package packets
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestEntityMetadata(t *testing.T) {
+	id := uuid.UUID{1, 2, 3}
+	var raw bytes.Buffer
+	raw.Write([]byte{0, byte(METADATA_BYTE), 0x20})
+	raw.Write([]byte{2, byte(METADATA_OPT_CHAT), 0}) // absent
+	raw.Write([]byte{8, byte(METADATA_OPT_UUID), 1})
+	raw.Write(id[:])
+	raw.Write([]byte{7, byte(METADATA_SLOT), 1, 0x8A, 0x01, 64, 0}) // item 138, no NBT
+	raw.Write([]byte{16, byte(METADATA_VILLAGER_DATA), 1, 2, 3})
+	raw.WriteByte(0xFF)
+
+	want := EntityMetadata{
+		{0, METADATA_BYTE, int8(0x20)},
+		{2, METADATA_OPT_CHAT, nil},
+		{8, METADATA_OPT_UUID, id},
+		{7, METADATA_SLOT, Slot{Present: true, ItemID: 138, Count: 64}},
+		{16, METADATA_VILLAGER_DATA, VillagerData{1, 2, 3}},
+	}
+	var data EntityMetadata
+	if err := data.Parse(bytes.NewReader(raw.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Fatalf("parsed\ngot:  %#v\nwant: %#v", data, want)
+	}
+	var out bytes.Buffer
+	if err := data.Serialize(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), raw.Bytes()) {
+		t.Fatalf("serialized\ngot:  %x\nwant: %x", out.Bytes(), raw.Bytes())
+	}
+
+	if err := data.Parse(bytes.NewReader([]byte{0, 19, 0, 0xFF})); err == nil {
+		t.Fatal("unknown type parsed")
+	}
+	data = EntityMetadata{{0, METADATA_VARINT, int32(1)}}
+	if err := data.Serialize(new(bytes.Buffer)); err == nil {
+		t.Fatal("value of wrong type serialized")
+	}
+}
@@ -0,0 +1,141 @@
+package packets
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// > fuzz targets, run with eg. go test -fuzz FuzzParse ./packets
+// Without -fuzz only seeds (golden packets and testdata/fuzz) are checked.
+
+// Bound of memory allocated by single input. Parsers should allocate proportionally to data
+// they've read, not to lengths claimed by it. Reader buffers up to MaxPacketSize.
+const fuzzMaxAlloc = 16 * 1024 * 1024
+
+func checkAlloc(t *testing.T, fn func()) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	fn()
+	runtime.ReadMemStats(&after)
+	if n := after.TotalAlloc - before.TotalAlloc; n > fuzzMaxAlloc {
+		t.Fatalf("allocated %d bytes", n)
+	}
+}
+
+func addGoldenSeeds(f *testing.F, add func(g goldenPacket)) {
+	list, err := readGolden()
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, g := range list {
+		add(g)
+	}
+}
+
+func FuzzReadPacket(f *testing.F) {
+	addGoldenSeeds(f, func(g goldenPacket) {
+		for _, threshold := range []int{NoCompression, 16} {
+			var buf bytes.Buffer
+			raw := NewRawPacket(g.id, append(appendVarInt(nil, g.id), g.data...))
+			NewPacketWriter(&buf, threshold).WritePacket(raw, true)
+			f.Add(buf.Bytes(), threshold > 0)
+		}
+	})
+	f.Fuzz(func(t *testing.T, data []byte, compressed bool) {
+		threshold := NoCompression
+		if compressed {
+			threshold = 16
+		}
+		checkAlloc(t, func() {
+			reader := NewPacketReader(bytes.NewReader(data), threshold)
+			for {
+				raw, err := reader.ReadPacket()
+				if err != nil {
+					return
+				}
+				if raw.ID < 0 || raw.ID > MaxPacketID {
+					t.Fatalf("invalid id %d", raw.ID)
+				}
+				payload, err := reader.Payload()
+				if err != nil {
+					return
+				}
+				if _, err := io.Copy(ioutil.Discard, payload); err != nil {
+					return
+				}
+			}
+		})
+	})
+}
+
+// generated codec must agree with reflection codec on every input
+func FuzzReadMinecraftStruct(f *testing.F) {
+	index := make(map[reflect.Type]uint8)
+	for i, codec := range generatedCodecs {
+		index[reflect.TypeOf(codec)] = uint8(i)
+	}
+	addGoldenSeeds(f, func(g goldenPacket) {
+		if i, ok := index[reflect.TypeOf(NewPacket(g.id, g.state, g.direction))]; ok {
+			f.Add(i, g.data)
+		}
+	})
+	f.Fuzz(func(t *testing.T, i uint8, data []byte) {
+		if int(i) >= len(generatedCodecs) {
+			return
+		}
+		typ := reflect.TypeOf(generatedCodecs[i]).Elem()
+		generated := reflect.New(typ).Interface()
+		reflected := reflect.New(typ).Interface()
+		var gen_err, refl_err error
+		checkAlloc(t, func() {
+			gen_err = ReadMinecraftStruct(bytes.NewReader(data), generated)
+			refl_err = readStructReflect(bytes.NewReader(data), reflected)
+		})
+		if (gen_err == nil) != (refl_err == nil) {
+			t.Fatalf("%s: generated error %v, reflection error %v", typ.Name(), gen_err, refl_err)
+		}
+		if gen_err != nil {
+			return
+		}
+		// compared by encoding, NaN floats aren't DeepEqual
+		var got, want bytes.Buffer
+		gen_err = writeStructReflect(&got, generated)
+		refl_err = writeStructReflect(&want, reflected)
+		if (gen_err == nil) != (refl_err == nil) || !bytes.Equal(got.Bytes(), want.Bytes()) {
+			t.Fatalf("%s: generated read differs\ngot:  %+v\nwant: %+v", typ.Name(), generated, reflected)
+		}
+	})
+}
+
+// Parse of every registered packet, parsed packets have to be serializable
+func FuzzParse(f *testing.F) {
+	addGoldenSeeds(f, func(g goldenPacket) {
+		f.Add(uint8(g.state), uint8(g.direction), uint8(g.id), g.data)
+	})
+	f.Fuzz(func(t *testing.T, state, direction, id uint8, data []byte) {
+		if direction != uint8(ServerBound) && direction != uint8(ClientBound) {
+			return
+		}
+		packet := NewPacket(VarInt(id), ConnState(state), Direction(direction))
+		if packet == nil {
+			return
+		}
+		var err error
+		checkAlloc(t, func() {
+			err = packet.Parse(bytes.NewReader(data))
+		})
+		if err == nil {
+			packet.Serialize(ioutil.Discard)
+		}
+	})
+}
+
+func appendVarInt(b []byte, v VarInt) []byte {
+	var buf bytes.Buffer
+	WriteVarInt(&buf, v)
+	return append(b, buf.Bytes()...)
+}
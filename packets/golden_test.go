@@ -0,0 +1,173 @@
+package packets
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// > golden packets: testdata/golden.txt has encoding of one packet of each registered type
+
+var updateGolden = flag.Bool("update", false, "regenerate testdata/golden.txt with random packets")
+
+const goldenPath = "testdata/golden.txt"
+
+type goldenPacket struct {
+	state     ConnState
+	direction Direction
+	id        VarInt
+	name      string
+	data      []byte
+}
+
+func (g goldenPacket) key() string {
+	return fmt.Sprintf("%s %s 0x%02X %s", g.state, g.direction, g.id, g.name)
+}
+
+// all packets known to NewPacket, in order of state, direction and id
+func registeredPackets() (list []goldenPacket) {
+	for _, state := range []ConnState{HANDSHAKING, STATUS, LOGIN, PLAY} {
+		for _, direction := range []Direction{ServerBound, ClientBound} {
+			for id := VarInt(0); id <= MaxPacketID; id++ {
+				if packet := NewPacket(id, state, direction); packet != nil {
+					name := reflect.TypeOf(packet).Elem().Name()
+					list = append(list, goldenPacket{state, direction, id, name, nil})
+				}
+			}
+		}
+	}
+	return
+}
+
+func readGolden() ([]goldenPacket, error) {
+	f, err := os.Open(goldenPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var list []goldenPacket
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, MaxPacketSize*2)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var g goldenPacket
+		var state, direction, data string
+		if _, err := fmt.Sscanf(text, "%s %s 0x%X %s %s", &state, &direction, &g.id, &g.name, &data); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", goldenPath, line, err)
+		}
+		for s := HANDSHAKING; s <= PLAY; s++ {
+			if s.String() == state {
+				g.state = s
+			}
+		}
+		if g.state.String() != state {
+			return nil, fmt.Errorf("%s:%d: invalid state %s", goldenPath, line, state)
+		}
+		switch direction {
+		case "SB":
+			g.direction = ServerBound
+		case "CB":
+			g.direction = ClientBound
+		default:
+			return nil, fmt.Errorf("%s:%d: invalid direction %s", goldenPath, line, direction)
+		}
+		if data == "-" {
+			data = ""
+		}
+		if g.data, err = hex.DecodeString(data); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", goldenPath, line, err)
+		}
+		list = append(list, g)
+	}
+	return list, scanner.Err()
+}
+
+func writeGolden(list []goldenPacket) error {
+	var buf bytes.Buffer
+	buf.WriteString("# state direction id type data; regenerate with go test -run TestGoldenPackets -update\n")
+	for _, g := range list {
+		data := hex.EncodeToString(g.data)
+		if data == "" {
+			data = "-" // packets without fields
+		}
+		fmt.Fprintf(&buf, "%s %s\n", g.key(), data)
+	}
+	if err := os.MkdirAll("testdata", 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(goldenPath, buf.Bytes(), 0644)
+}
+
+// random packets, same seed gives same fixtures
+func generateGolden() ([]goldenPacket, error) {
+	rnd := rand.New(rand.NewSource(1))
+	list := registeredPackets()
+	for i := range list {
+		packet := NewPacket(list[i].id, list[i].state, list[i].direction)
+		randomFill(rnd, packet)
+		var buf bytes.Buffer
+		if err := packet.Serialize(&buf); err != nil {
+			return nil, fmt.Errorf("%s: serialize: %v", list[i].key(), err)
+		}
+		list[i].data = buf.Bytes()
+	}
+	return list, nil
+}
+
+// Parse of fixture must consume all of it and Serialize must give the same bytes back,
+// so any change of wire format shows up as fixture diff.
+func TestGoldenPackets(t *testing.T) {
+	if *updateGolden {
+		list, err := generateGolden()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writeGolden(list); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := readGolden()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := make(map[string]bool)
+	for _, g := range list {
+		fixtures[g.key()] = true
+		packet := NewPacket(g.id, g.state, g.direction)
+		if packet == nil || reflect.TypeOf(packet).Elem().Name() != g.name {
+			t.Errorf("%s: registered packet is %T", g.key(), packet)
+			continue
+		}
+		reader := bytes.NewReader(g.data)
+		if err := packet.Parse(reader); err != nil {
+			t.Errorf("%s: parse: %v", g.key(), err)
+			continue
+		}
+		if reader.Len() > 0 {
+			t.Errorf("%s: %d of %d bytes left after parse", g.key(), reader.Len(), len(g.data))
+		}
+		var buf bytes.Buffer
+		if err := packet.Serialize(&buf); err != nil {
+			t.Errorf("%s: serialize: %v", g.key(), err)
+		} else if !bytes.Equal(buf.Bytes(), g.data) {
+			t.Errorf("%s: round trip differs\ngot:  %x\nwant: %x", g.key(), buf.Bytes(), g.data)
+		}
+	}
+	for _, g := range registeredPackets() {
+		if !fixtures[g.key()] {
+			t.Errorf("%s: no fixture in %s, run with -update", g.key(), goldenPath)
+		}
+	}
+}
@@ -0,0 +1,577 @@
+// Command packetgen generates UnmarshalPacket and MarshalPacket methods for packet structs
+// read and written with ReadMinecraftStruct/WriteMinecraftStruct, so reflection isn't used at runtime.
+// Generated code uses the same tags and field helpers, output is byte-identical to reflection codec.
+//
+// With -spec, packet types and newPlayPacket are first generated from spec table (see play.spec).
+//
+// Run in packets directory with: go generate
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const header = "// Code generated by packetgen; DO NOT EDIT.\n\n"
+
+var (
+	output     = flag.String("output", "codec_gen.go", "generated methods")
+	testOutput = flag.String("test_output", "codec_gen_test.go", "generated list of types for round-trip tests")
+	specFile   = flag.String("spec", "", "spec table of packets, generated types are written to -spec_output")
+	specOutput = flag.String("spec_output", "play_gen.go", "generated packet types")
+)
+
+type field struct {
+	name, typ string
+	tag       reflect.StructTag
+}
+
+type structDef struct {
+	name   string
+	all    []field // including mcignore, used to resolve when tags
+	fields []field // serialized ones
+}
+
+// struct declarations of package, by name
+var structs = make(map[string]*ast.StructType)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("packetgen: ")
+	flag.Parse()
+
+	var sp *spec
+	if *specFile != "" {
+		sp = generateSpec(*specFile, *specOutput)
+	}
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	codec := make(map[string]bool) // types passed to Read/WriteMinecraftStruct
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") || name == *output {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		collect(f, codec)
+	}
+
+	var names []string
+	for name := range codec {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var methods, list bytes.Buffer
+	list.WriteString(header + "package packets\n\nvar generatedCodecs = []interface{}{\n")
+	for _, name := range names {
+		def, err := newStructDef(name)
+		if err == nil {
+			err = def.check(nil)
+		}
+		if err != nil {
+			log.Printf("skipping %s: %s", name, err)
+			continue
+		}
+		def.writeUnmarshal(&methods)
+		def.writeMarshal(&methods)
+		fmt.Fprintf(&list, "new(%s),\n", name)
+	}
+	list.WriteString("}\n")
+	if sp != nil {
+		sp.writeTestList(&list)
+	}
+
+	write(*output, append([]byte(header+"package packets\n\n"+importDecl(methods.Bytes())), methods.Bytes()...))
+	write(*testOutput, list.Bytes())
+}
+
+// io and packages of field types referenced in generated code
+func importDecl(src []byte) string {
+	decl := "import (\n"
+	if bytes.Contains(src, []byte("nbt.")) {
+		decl += "\"github.com/Craftserve/potoq/packets/nbt\"\n"
+	}
+	if bytes.Contains(src, []byte("uuid.")) {
+		decl += "\"github.com/google/uuid\"\n"
+	}
+	return decl + "\"io\"\n)\n"
+}
+
+func write(name string, src []byte) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Fatalf("%s: %s\n%s", name, err, src)
+	}
+	if err = ioutil.WriteFile(name, formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// finds struct declarations and methods calling XxxMinecraftStruct(_, receiver)
+func collect(f *ast.File, codec map[string]bool) {
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						structs[ts.Name.Name] = st
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || decl.Body == nil || len(decl.Recv.List[0].Names) == 0 {
+				continue
+			}
+			star, ok := decl.Recv.List[0].Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			recv := decl.Recv.List[0].Names[0].Name
+			ast.Inspect(decl.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) != 2 {
+					return true
+				}
+				fn, ok := call.Fun.(*ast.Ident)
+				arg, ok2 := call.Args[1].(*ast.Ident)
+				if ok && ok2 && arg.Name == recv && (fn.Name == "ReadMinecraftStruct" || fn.Name == "WriteMinecraftStruct") {
+					codec[star.X.(*ast.Ident).Name] = true
+				}
+				return true
+			})
+		}
+	}
+}
+
+func newStructDef(name string) (*structDef, error) {
+	st, ok := structs[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a struct", name)
+	}
+	def := &structDef{name: name}
+	for _, f := range st.Fields.List {
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			v, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(v)
+		}
+		if len(f.Names) == 0 {
+			return nil, fmt.Errorf("embedded field %s", exprString(f.Type))
+		}
+		for _, n := range f.Names {
+			fd := field{n.Name, exprString(f.Type), tag}
+			def.all = append(def.all, fd)
+			if tag.Get("mcignore") == "" {
+				def.fields = append(def.fields, fd)
+			}
+		}
+	}
+	return def, nil
+}
+
+func exprString(e ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
+
+// > checks, same rules as checkStructTags in packets
+
+// scope of nested structs, used to resolve fields in when tags
+type scope struct {
+	def    *structDef
+	expr   string // expression of struct value in generated code
+	parent *scope
+}
+
+func (s *scope) lookup(name string) (expr, typ string, ok bool) {
+	for ; s != nil; s = s.parent {
+		for _, f := range s.def.all {
+			if f.name == name {
+				return s.expr + "." + name, f.typ, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func (def *structDef) check(parent *scope) error {
+	sc := &scope{def, "", parent}
+	for i, f := range def.fields {
+		if when := f.tag.Get("when"); when != "" {
+			name, _, _, err := parseWhen(when)
+			if err != nil {
+				return fmt.Errorf("field %s: %s", f.name, err)
+			}
+			if _, typ, ok := sc.lookup(name); !ok || !isInteger(typ) && typ != "bool" {
+				return fmt.Errorf("field %s: invalid field in when tag %q", f.name, when)
+			}
+		}
+		if err := checkType(f.typ, f.tag, sc); err != nil {
+			return fmt.Errorf("field %s: %s", f.name, err)
+		}
+		if f.typ == "[]byte" && f.tag.Get("length_prefix") == "eof" && i != len(def.fields)-1 {
+			return fmt.Errorf("field %s: length_prefix eof field is not last in struct", f.name)
+		}
+	}
+	return nil
+}
+
+func isInteger(typ string) bool {
+	switch typ {
+	case "uint8", "byte", "int8", "int16", "int32", "int64", "VarInt", "VarLong", "EntityID":
+		return true
+	}
+	return false
+}
+
+func checkType(typ string, tag reflect.StructTag, sc *scope) error {
+	switch typ {
+	case "bool", "uint8", "byte", "int8", "int16", "int32", "int64", "VarInt", "VarLong", "BlockPosition",
+		"SectionPos", "Angle", "float32", "float64", "Identifier", "[]Identifier", "uuid.UUID", "nbt.TagCompound":
+		return nil
+	case "string":
+		_, err := strconv.Atoi(tag.Get("max_length"))
+		return err
+	case "[]byte":
+		if _, err := strconv.ParseInt(tag.Get("max_length"), 10, 64); err != nil {
+			return err
+		}
+		switch tag.Get("length_prefix") {
+		case "int16", "int32", "VarInt", "eof":
+			return nil
+		}
+		return fmt.Errorf("invalid length_prefix %q", tag.Get("length_prefix"))
+	case "EntityID":
+		switch tag.Get("datatype") {
+		case "int32", "VarInt":
+			return nil
+		}
+		return fmt.Errorf("invalid datatype %q", tag.Get("datatype"))
+	}
+	if elem := strings.TrimPrefix(typ, "[]"); elem != typ {
+		if elem == "byte" {
+			return fmt.Errorf("[]byte can't be array element")
+		}
+		switch tag.Get("length_prefix") {
+		case "int16", "int32", "VarInt":
+		default:
+			return fmt.Errorf("invalid length_prefix of array %q", tag.Get("length_prefix"))
+		}
+		if _, err := strconv.Atoi(tag.Get("max_count")); err != nil {
+			return err
+		}
+		return checkType(elem, tag, sc)
+	}
+	if _, ok := structs[typ]; ok {
+		def, err := newStructDef(typ)
+		if err != nil {
+			return err
+		}
+		return def.check(sc)
+	}
+	return fmt.Errorf("unsupported type %s", typ)
+}
+
+// same syntax as parseWhen in packets
+func parseWhen(when string) (name, op string, values []string, err error) {
+	i := strings.Index(when, "=")
+	if i < 1 || (when[i-1] != '!' && (len(when) == i+1 || when[i+1] != '=')) {
+		return "", "", nil, fmt.Errorf("invalid when tag %q", when)
+	}
+	if when[i-1] == '!' {
+		name, op = when[:i-1], "!="
+		when = when[i+1:]
+	} else {
+		name, op = when[:i], "=="
+		when = when[i+2:]
+	}
+	values = strings.Split(when, ",")
+	for _, v := range values {
+		if _, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// > code generation
+
+type generator struct {
+	buf     bytes.Buffer
+	depth   int  // nesting of arrays, used for names of loop variables
+	present bool // present variable used
+	count   bool // n variable used
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// condition of when tag in generated code
+func whenExpr(when string, sc *scope) string {
+	name, op, values, _ := parseWhen(when)
+	expr, typ, _ := sc.lookup(name)
+	var parts []string
+	for _, v := range values {
+		if typ == "bool" {
+			if (v == "0") == (op == "==") {
+				parts = append(parts, "!"+expr)
+			} else {
+				parts = append(parts, expr)
+			}
+		} else {
+			parts = append(parts, expr+" "+op+" "+v)
+		}
+	}
+	if op == "==" {
+		return strings.Join(parts, " || ")
+	}
+	return strings.Join(parts, " && ")
+}
+
+func zeroValue(typ string) string {
+	switch {
+	case typ == "bool":
+		return "false"
+	case typ == "string" || typ == "Identifier":
+		return `""`
+	case strings.HasPrefix(typ, "[]") || typ == "nbt.TagCompound":
+		return "nil"
+	case typ == "uuid.UUID":
+		return "[16]byte{}"
+	case structs[typ] != nil:
+		return typ + "{}"
+	}
+	return "0"
+}
+
+// reader functions of types without tags
+var readFuncs = map[string]string{
+	"bool":            "ReadBool(br)",
+	"uint8":           "ReadUnsignedByte(br)",
+	"byte":            "ReadUnsignedByte(br)",
+	"int8":            "ReadByte(br)",
+	"int16":           "ReadShort(br)",
+	"int32":           "ReadInt(br)",
+	"int64":           "ReadLong(br)",
+	"VarInt":          "ReadVarIntFrom(br)",
+	"VarLong":         "ReadVarLong(br)",
+	"BlockPosition":   "ReadBlockPosition(br)",
+	"SectionPos":      "ReadSectionPos(br)",
+	"Angle":           "ReadAngle(br)",
+	"float32":         "ReadFloat(br)",
+	"float64":         "ReadDouble(br)",
+	"Identifier":      "ReadIdentifier(br)",
+	"[]Identifier":    "ReadIdentifierArray(br)",
+	"nbt.TagCompound": "readNBTField(br)",
+}
+
+func (def *structDef) writeUnmarshal(out *bytes.Buffer) {
+	g := &generator{}
+	g.readFields(&scope{def, "packet", nil})
+	fmt.Fprintf(out, "\nfunc (packet *%s) UnmarshalPacket(reader io.Reader) (err error) {\n", def.name)
+	if len(def.fields) > 0 {
+		out.WriteString("br := asByter(reader)\n")
+	}
+	g.writeVars(out)
+	out.Write(g.buf.Bytes())
+	out.WriteString("return\n}\n")
+}
+
+func (g *generator) writeVars(out *bytes.Buffer) {
+	if g.present {
+		out.WriteString("var present bool\n")
+	}
+	if g.count {
+		out.WriteString("var n int\n")
+	}
+}
+
+func (g *generator) readFields(sc *scope) {
+	for _, f := range sc.def.fields {
+		target := sc.expr + "." + f.name
+		when, optional := f.tag.Get("when"), f.tag.Get("optional") != ""
+		if when != "" {
+			g.printf("if %s {\n", whenExpr(when, sc))
+		}
+		if optional {
+			g.present = true
+			g.printf("if present, err = ReadBool(br); err != nil {\nreturn\n}\nif present {\n")
+		}
+		g.readValue(target, f.typ, f.tag, sc)
+		if optional {
+			g.printf("} else {\n%s = %s\n}\n", target, zeroValue(f.typ))
+		}
+		if when != "" {
+			g.printf("} else {\n%s = %s\n}\n", target, zeroValue(f.typ))
+		}
+	}
+}
+
+func (g *generator) readValue(target, typ string, tag reflect.StructTag, sc *scope) {
+	var call string
+	switch typ {
+	case "string":
+		call = fmt.Sprintf("ReadMinecraftString(br, %s)", tag.Get("max_length"))
+	case "[]byte":
+		call = fmt.Sprintf("readBytesField(br, %q, %s)", tag.Get("length_prefix"), tag.Get("max_length"))
+	case "EntityID":
+		call = fmt.Sprintf("readEntityIDField(br, %q)", tag.Get("datatype"))
+	case "uuid.UUID":
+		g.printf("if _, err = io.ReadFull(br, %s[:]); err != nil {\nreturn\n}\n", target)
+		return
+	default:
+		call = readFuncs[typ]
+	}
+	if call != "" {
+		g.printf("if %s, err = %s; err != nil {\nreturn\n}\n", target, call)
+		return
+	}
+
+	if elem := strings.TrimPrefix(typ, "[]"); elem != typ {
+		g.count = true
+		i := fmt.Sprintf("i%d", g.depth)
+		g.printf("if n, err = readCount(br, %q, %s); err != nil {\nreturn\n}\n", tag.Get("length_prefix"), tag.Get("max_count"))
+		// count isn't trusted, so array grows with elements actually read
+		item := fmt.Sprintf("item%d", g.depth)
+		g.printf("%s = make(%s, 0, countCapacity(n))\nfor %s, n%d := 0, n; %s < n%d; %s++ {\nvar %s %s\n", target, typ, i, g.depth, i, g.depth, i, item, elem)
+		g.depth++
+		g.readValue(item, elem, tag, sc)
+		g.depth--
+		g.printf("%s = append(%s, %s)\n}\n", target, target, item)
+		return
+	}
+
+	def, _ := newStructDef(typ)
+	g.readFields(&scope{def, target, sc})
+}
+
+// declares pointer to struct element of array, other elements are used by index
+func (g *generator) element(target, i, elem string) string {
+	if structs[elem] == nil || readFuncs[elem] != "" {
+		return target + "[" + i + "]"
+	}
+	item := fmt.Sprintf("item%d", g.depth)
+	g.printf("%s := &%s[%s]\n", item, target, i)
+	return item
+}
+
+// writer functions, %s is replaced with field
+var writeFuncs = map[string]string{
+	"bool":            "WriteBool(writer, %s)",
+	"uint8":           "WriteUnsignedByte(writer, %s)",
+	"byte":            "WriteUnsignedByte(writer, %s)",
+	"int8":            "WriteByte(writer, %s)",
+	"int16":           "WriteShort(writer, %s)",
+	"int32":           "WriteInt(writer, %s)",
+	"int64":           "WriteLong(writer, %s)",
+	"VarInt":          "WriteVarInt(writer, %s)",
+	"VarLong":         "WriteVarLong(writer, %s)",
+	"BlockPosition":   "WriteBlockPosition(writer, %s)",
+	"SectionPos":      "WriteSectionPos(writer, %s)",
+	"Angle":           "WriteAngle(writer, %s)",
+	"float32":         "WriteFloat(writer, %s)",
+	"float64":         "WriteDouble(writer, %s)",
+	"string":          "WriteMinecraftString(writer, %s)",
+	"Identifier":      "WriteMinecraftString(writer, string(%s))",
+	"[]Identifier":    "WriteIdentifierArray(writer, %s)",
+	"nbt.TagCompound": "writeNBTField(writer, %s)",
+}
+
+func (def *structDef) writeMarshal(out *bytes.Buffer) {
+	g := &generator{}
+	g.writeFields(&scope{def, "packet", nil})
+	fmt.Fprintf(out, "\nfunc (packet *%s) MarshalPacket(writer io.Writer) (err error) {\n", def.name)
+	if len(def.fields) > 0 {
+		out.WriteString("writer = asByteWriter(writer)\n")
+	}
+	g.writeVars(out)
+	out.Write(g.buf.Bytes())
+	out.WriteString("return\n}\n")
+}
+
+func (g *generator) writeFields(sc *scope) {
+	for _, f := range sc.def.fields {
+		value := sc.expr + "." + f.name
+		when, optional := f.tag.Get("when"), f.tag.Get("optional") != ""
+		if when != "" {
+			g.printf("if %s {\n", whenExpr(when, sc))
+		}
+		if optional {
+			g.present = true
+			if f.typ == "bool" {
+				g.printf("present = %s\n", value)
+			} else {
+				g.printf("present = %s != %s\n", value, zeroValue(f.typ))
+			}
+			g.printf("if err = WriteBool(writer, present); err != nil {\nreturn\n}\nif present {\n")
+		}
+		g.writeValue(value, f.typ, f.tag, sc)
+		if optional {
+			g.printf("}\n")
+		}
+		if when != "" {
+			g.printf("}\n")
+		}
+	}
+}
+
+func (g *generator) writeValue(value, typ string, tag reflect.StructTag, sc *scope) {
+	var call string
+	switch typ {
+	case "[]byte":
+		call = fmt.Sprintf("writeBytesField(writer, %q, %s, %s)", tag.Get("length_prefix"), tag.Get("max_length"), value)
+	case "EntityID":
+		call = fmt.Sprintf("writeEntityIDField(writer, %q, %s)", tag.Get("datatype"), value)
+	case "uuid.UUID":
+		g.printf("if _, err = writer.Write(%s[:]); err != nil {\nreturn\n}\n", value)
+		return
+	default:
+		if f, ok := writeFuncs[typ]; ok {
+			call = fmt.Sprintf(f, value)
+		}
+	}
+	if call != "" {
+		g.printf("if err = %s; err != nil {\nreturn\n}\n", call)
+		return
+	}
+
+	if elem := strings.TrimPrefix(typ, "[]"); elem != typ {
+		i := fmt.Sprintf("i%d", g.depth)
+		g.printf("if err = writeCount(writer, %q, %s, len(%s)); err != nil {\nreturn\n}\n", tag.Get("length_prefix"), tag.Get("max_count"), value)
+		g.printf("for %s := range %s {\n", i, value)
+		item := g.element(value, i, elem)
+		g.depth++
+		g.writeValue(item, elem, tag, sc)
+		g.depth--
+		g.printf("}\n")
+		return
+	}
+
+	def, _ := newStructDef(typ)
+	g.writeFields(&scope{def, value, sc})
+}
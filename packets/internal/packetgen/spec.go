@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// > spec mode: packet types and newPlayPacket generated from table in play.spec
+
+type specPacket struct {
+	direction string // cb or sb
+	id        int64
+	name      string
+	custom    bool     // written by hand, only registered
+	fields    []string // lines of struct body
+}
+
+type spec struct {
+	packets []*specPacket
+	structs []*specPacket // helper structs, without id and direction
+}
+
+func parseSpec(name string) (*spec, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sp := new(spec)
+	var last *specPacket
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			if last == nil || last.custom {
+				return nil, fmt.Errorf("%s:%d: field outside of packet", name, line)
+			}
+			last.fields = append(last.fields, strings.TrimSpace(text))
+			continue
+		}
+
+		words := strings.Fields(text)
+		last, err = parseSpecHeader(words)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", name, line, err)
+		}
+		if last.direction == "" {
+			sp.structs = append(sp.structs, last)
+		} else {
+			sp.packets = append(sp.packets, last)
+		}
+	}
+	return sp, scanner.Err()
+}
+
+// "cb|sb <id> <name> [custom]" or "struct <name>"
+func parseSpecHeader(words []string) (*specPacket, error) {
+	if len(words) == 2 && words[0] == "struct" {
+		return &specPacket{name: words[1]}, nil
+	}
+	if len(words) < 3 || len(words) > 4 || (words[0] != "cb" && words[0] != "sb") {
+		return nil, fmt.Errorf("invalid header %q", strings.Join(words, " "))
+	}
+	id, err := strconv.ParseInt(words[1], 0, 32)
+	if err != nil {
+		return nil, err
+	}
+	p := &specPacket{direction: words[0], id: id, name: words[2]}
+	if len(words) == 4 {
+		if words[3] != "custom" {
+			return nil, fmt.Errorf("unknown option %q", words[3])
+		}
+		p.custom = true
+	}
+	return p, nil
+}
+
+func (p *specPacket) typeDecl() string {
+	return fmt.Sprintf("type %s struct {\n%s\n}\n", p.name, strings.Join(p.fields, "\n"))
+}
+
+func (p *specPacket) directionName() string {
+	if p.direction == "sb" {
+		return "ServerBound"
+	}
+	return "ClientBound"
+}
+
+// generates Go source of spec packets and registry
+func (sp *spec) generate(specName string) ([]byte, error) {
+	var types bytes.Buffer
+	seen := make(map[string]bool)
+	for _, d := range append(sp.structs, sp.packets...) {
+		if seen[d.name] {
+			return nil, fmt.Errorf("%s defined twice", d.name)
+		}
+		seen[d.name] = true
+		if d.custom {
+			continue
+		}
+		if d.direction != "" {
+			fmt.Fprintf(&types, "\n// > 0x%02X %s\n\n", d.id, d.name)
+		} else {
+			types.WriteString("\n")
+		}
+		decl := d.typeDecl()
+		// fields are checked here, so errors point to spec instead of generated file
+		if _, err := parser.ParseFile(token.NewFileSet(), d.name, "package packets\n"+decl, 0); err != nil {
+			return nil, fmt.Errorf("%s: %s", d.name, err)
+		}
+		types.WriteString(decl)
+		if d.direction != "" {
+			d.writeMethods(&types)
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by packetgen from %s; DO NOT EDIT.\n\npackage packets\n\n", specName)
+	out.WriteString(importDecl(types.Bytes()) + "\n")
+	sp.writeRegistry(&out)
+	out.Write(types.Bytes())
+	return out.Bytes(), nil
+}
+
+func (p *specPacket) writeMethods(out *bytes.Buffer) {
+	fmt.Fprintf(out, "\nfunc (packet *%s) PacketID() VarInt {\nreturn 0x%02X\n}\n", p.name, p.id)
+	fmt.Fprintf(out, "\nfunc (packet *%s) Parse(reader io.Reader) error {\nreturn ReadMinecraftStruct(reader, packet)\n}\n", p.name)
+	fmt.Fprintf(out, "\nfunc (packet *%s) Serialize(writer io.Writer) error {\nreturn WriteMinecraftStruct(writer, packet)\n}\n", p.name)
+	fmt.Fprintf(out, "\nfunc (packet *%s) Direction() Direction {\nreturn %s\n}\n", p.name, p.directionName())
+}
+
+func (sp *spec) writeRegistry(out *bytes.Buffer) {
+	out.WriteString("func newPlayPacket(packetId VarInt, direction Direction) (packet Packet) {\nif direction == ServerBound {\n")
+	sp.writeSwitch(out, "sb")
+	out.WriteString("} else {\n")
+	sp.writeSwitch(out, "cb")
+	out.WriteString("}\nreturn\n}\n")
+}
+
+func (sp *spec) writeSwitch(out *bytes.Buffer, direction string) {
+	out.WriteString("switch packetId {\n")
+	for _, p := range sp.packets {
+		if p.direction == direction {
+			fmt.Fprintf(out, "case 0x%02X:\npacket = new(%s)\n", p.id, p.name)
+		}
+	}
+	out.WriteString("}\n")
+}
+
+// list of spec packets for tests, with expected id and direction
+func (sp *spec) writeTestList(out *bytes.Buffer) {
+	out.WriteString("\nvar specPackets = []struct {\nid VarInt\ndirection Direction\npacket Packet\n}{\n")
+	for _, p := range sp.packets {
+		fmt.Fprintf(out, "{0x%02X, %s, new(%s)},\n", p.id, p.directionName(), p.name)
+	}
+	out.WriteString("}\n")
+}
+
+func generateSpec(specName, outName string) *spec {
+	sp, err := parseSpec(specName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := sp.generate(specName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	write(outName, src)
+	return sp
+}
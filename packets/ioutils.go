@@ -3,65 +3,140 @@ package packets
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
-	"github.com/RyanW02/NamedBinaryTagParser/nbt"
+	"github.com/Craftserve/potoq/packets/nbt"
 	"io"
 	"math"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/google/uuid"
 )
 
 func ReadBool(reader io.Reader) (c bool, err error) {
-	var v [1]byte
-	_, err = reader.Read(v[0:])
-	c = v[0] == 1
+	v, err := readUint(reader, 1)
+	c = v == 1
 	return
 }
 
 func ReadByte(reader io.Reader) (c int8, err error) {
-	err = binary.Read(reader, binary.BigEndian, &c)
-	return
+	v, err := readUint(reader, 1)
+	return int8(v), err
 }
 
 func ReadUnsignedByte(reader io.Reader) (c byte, err error) {
-	err = binary.Read(reader, binary.BigEndian, &c)
-	return
+	v, err := readUint(reader, 1)
+	return byte(v), err
 }
 
 func ReadShort(reader io.Reader) (c int16, err error) {
-	err = binary.Read(reader, binary.BigEndian, &c)
-	return
+	v, err := readUint(reader, 2)
+	return int16(v), err
 }
 
 func ReadInt(reader io.Reader) (c int32, err error) {
-	err = binary.Read(reader, binary.BigEndian, &c)
-	return
+	v, err := readUint(reader, 4)
+	return int32(v), err
 }
 
 func ReadLong(reader io.Reader) (c int64, err error) {
-	err = binary.Read(reader, binary.BigEndian, &c)
-	return
+	v, err := readUint(reader, 8)
+	return int64(v), err
 }
 
-func ReadVarInt(reader io.Reader) (c VarInt, err error) { // TODO: wymagac ByteReader
+func ReadVarInt(reader io.Reader) (c VarInt, err error) {
 	br, ok := reader.(io.ByteReader)
 	if !ok {
-		br = &dummyByteReader{reader, [1]byte{}}
+		br = &dummyByteReader{Reader: reader}
+	}
+	return ReadVarIntFrom(br)
+}
+
+var errVarIntTooBig = errors.New("VarInt is too big")
+
+// Same as ReadVarInt, but never allocates
+func ReadVarIntFrom(reader io.ByteReader) (VarInt, error) {
+	var x uint32
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := reader.ReadByte()
+		if err != nil {
+			if shift > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		x |= uint32(b&0x7F) << shift
+		if b < 0x80 {
+			return VarInt(int32(x)), nil
+		}
+	}
+	return 0, errVarIntTooBig
+}
+
+func ReadVarLong(reader io.Reader) (VarLong, error) {
+	br, ok := reader.(io.ByteReader)
+	if !ok {
+		br = &dummyByteReader{Reader: reader}
+	}
+	var x uint64
+	for shift := uint(0); shift < 70; shift += 7 {
+		b, err := br.ReadByte()
+		if err != nil {
+			if shift > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		x |= uint64(b&0x7F) << shift
+		if b < 0x80 {
+			return VarLong(x), nil
+		}
+	}
+	return 0, errors.New("VarLong is too big")
+}
+
+// number of bytes used by encoded VarInt
+func varIntSize(c VarInt) int {
+	n := 1
+	for x := uint32(c); x >= 0x80; x >>= 7 {
+		n++
 	}
-	x, err := binary.ReadUvarint(br)
-	return VarInt(int32(uint32(x))), err
+	return n
 }
 
 func ReadFloat(reader io.Reader) (c float32, err error) {
-	err = binary.Read(reader, binary.BigEndian, &c)
-	return
+	v, err := readUint(reader, 4)
+	return math.Float32frombits(uint32(v)), err
 }
 
 func ReadDouble(reader io.Reader) (c float64, err error) {
-	err = binary.Read(reader, binary.BigEndian, &c)
-	return
+	v, err := readUint(reader, 8)
+	return math.Float64frombits(v), err
+}
+
+// Reads n bytes long big endian integer. ByteReader is used if possible,
+// because buffer passed to Read would be allocated on heap.
+func readUint(reader io.Reader, n int) (v uint64, err error) {
+	if br, ok := reader.(io.ByteReader); ok {
+		for i := 0; i < n; i++ {
+			var b byte
+			b, err = br.ReadByte()
+			if err != nil {
+				if i > 0 && err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return 0, err
+			}
+			v = v<<8 | uint64(b)
+		}
+		return
+	}
+	var buf [8]byte
+	_, err = io.ReadFull(reader, buf[8-n:])
+	return binary.BigEndian.Uint64(buf[:]), err
 }
 
 func ReadMinecraftString(reader io.Reader, maxLength int) (string, error) {
@@ -93,7 +168,15 @@ func ReadIdentifierArray(reader io.Reader) ([]Identifier, error) {
 	if err != nil {
 		return nil, err
 	}
-	array := make([]Identifier, count)
+	if count < 0 {
+		return nil, fmt.Errorf("invalid identifier count: %d", count)
+	}
+	// count isn't limited, so array grows with data actually read
+	capacity := int(count)
+	if capacity > 16 {
+		capacity = 16
+	}
+	array := make([]Identifier, 0, capacity)
 	for i := 0; i < int(count); i++ {
 		value, err := ReadIdentifier(reader)
 		if err != nil {
@@ -105,47 +188,78 @@ func ReadIdentifierArray(reader io.Reader) ([]Identifier, error) {
 }
 
 func WriteBool(writer io.Writer, c bool) error {
-	var v [1]byte
+	var v uint64
 	if c {
-		v[0] = 1
+		v = 1
 	}
-	_, err := writer.Write(v[0:])
-	return err
+	return writeUint(writer, v, 1)
 }
 
 func WriteByte(writer io.Writer, c int8) error {
-	return binary.Write(writer, binary.BigEndian, &c)
+	return writeUint(writer, uint64(uint8(c)), 1)
 }
 
 func WriteUnsignedByte(writer io.Writer, c byte) error {
-	return binary.Write(writer, binary.BigEndian, &c)
+	return writeUint(writer, uint64(c), 1)
 }
 
 func WriteShort(writer io.Writer, c int16) error {
-	return binary.Write(writer, binary.BigEndian, &c)
+	return writeUint(writer, uint64(uint16(c)), 2)
 }
 
 func WriteInt(writer io.Writer, c int32) error {
-	return binary.Write(writer, binary.BigEndian, &c)
+	return writeUint(writer, uint64(uint32(c)), 4)
 }
 
 func WriteLong(writer io.Writer, c int64) error {
-	return binary.Write(writer, binary.BigEndian, &c)
+	return writeUint(writer, uint64(c), 8)
 }
 
 func WriteVarInt(writer io.Writer, c VarInt) error {
+	x := uint32(c)
+	if bw, ok := writer.(io.ByteWriter); ok {
+		for ; x >= 0x80; x >>= 7 {
+			if err := bw.WriteByte(byte(x) | 0x80); err != nil {
+				return err
+			}
+		}
+		return bw.WriteByte(byte(x))
+	}
 	var buf [8]byte
-	n := binary.PutUvarint(buf[:], uint64(uint32(c)))
+	n := binary.PutUvarint(buf[:], uint64(x))
+	_, err := writer.Write(buf[:n])
+	return err
+}
+
+func WriteVarLong(writer io.Writer, c VarLong) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(c))
 	_, err := writer.Write(buf[:n])
 	return err
 }
 
 func WriteFloat(writer io.Writer, c float32) error {
-	return binary.Write(writer, binary.BigEndian, &c)
+	return writeUint(writer, uint64(math.Float32bits(c)), 4)
 }
 
 func WriteDouble(writer io.Writer, c float64) error {
-	return binary.Write(writer, binary.BigEndian, &c)
+	return writeUint(writer, math.Float64bits(c), 8)
+}
+
+// Writes n bytes long big endian integer, see readUint
+func writeUint(writer io.Writer, v uint64, n int) error {
+	if bw, ok := writer.(io.ByteWriter); ok {
+		for i := n - 1; i >= 0; i-- {
+			if err := bw.WriteByte(byte(v >> (8 * uint(i)))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	_, err := writer.Write(buf[8-n:])
+	return err
 }
 
 func WriteMinecraftString(writer io.Writer, s string) (err error) {
@@ -153,7 +267,7 @@ func WriteMinecraftString(writer io.Writer, s string) (err error) {
 	if err != nil {
 		return err
 	}
-	_, err = writer.Write([]byte(s))
+	_, err = io.WriteString(writer, s)
 	return
 }
 
@@ -162,7 +276,7 @@ func WriteIdentifier(writer io.Writer, identifier Identifier) (err error) {
 	if err != nil {
 		return err
 	}
-	_, err = writer.Write([]byte(identifier))
+	_, err = io.WriteString(writer, string(identifier))
 	return
 }
 
@@ -187,21 +301,103 @@ type dummyByteReader struct {
 	buf [1]byte
 }
 
-func (b dummyByteReader) ReadByte() (byte, error) {
-	_, err := b.Read(b.buf[:])
+func (b *dummyByteReader) ReadByte() (byte, error) {
+	_, err := io.ReadFull(b.Reader, b.buf[:])
 	return b.buf[0], err
 }
 
 // ReadMinecraftStruct, WriteMinecraftStruct
+//
+// Field tags:
+//  mcignore:"-"             field isn't serialized
+//  max_length:"N"           max length of string or []byte
+//  length_prefix:"T"        []byte length prefix: int16, int32, VarInt or eof (rest of packet, last field);
+//                           element count of other slices: int16, int32 or VarInt, max_count:"N" is required then
+//  datatype:"T"             EntityID encoding: int32 or VarInt
+//  optional:"bool"          bool prefix, value is sent only if it isn't zero
+//  when:"Field==1,2"        field is sent only if Field (earlier in struct or in enclosing struct) has one of values,
+//                           when:"Field!=1" is sent if it hasn't; zeroed when not present
+// Elements of slices use tags of the slice, struct elements have own tags.
+// nil nbt.TagCompound is sent as TAG_End.
 
-func ReadMinecraftStruct(reader io.Reader, data interface{}) (err error) {
-	br, ok := reader.(io.ByteReader)
-	if !ok {
-		br = &dummyByteReader{reader, [1]byte{}}
+// field types are compared with reflect.Type, so field values don't have to be boxed in interface
+var (
+	typeBool        = reflect.TypeOf(false)
+	typeUint8       = reflect.TypeOf(uint8(0))
+	typeInt8        = reflect.TypeOf(int8(0))
+	typeInt16       = reflect.TypeOf(int16(0))
+	typeInt32       = reflect.TypeOf(int32(0))
+	typeInt64       = reflect.TypeOf(int64(0))
+	typeVarInt      = reflect.TypeOf(VarInt(0))
+	typeVarLong     = reflect.TypeOf(VarLong(0))
+	typeBlockPos    = reflect.TypeOf(BlockPosition{})
+	typeSectionPos  = reflect.TypeOf(SectionPos{})
+	typeAngle       = reflect.TypeOf(Angle(0))
+	typeFloat32     = reflect.TypeOf(float32(0))
+	typeFloat64     = reflect.TypeOf(float64(0))
+	typeIdentifier  = reflect.TypeOf(Identifier(""))
+	typeIdentifiers = reflect.TypeOf([]Identifier(nil))
+	typeString      = reflect.TypeOf("")
+	typeBytes       = reflect.TypeOf([]byte(nil))
+	typeEntityID    = reflect.TypeOf(EntityID(0))
+	typeUUID        = reflect.TypeOf(uuid.UUID{})
+	typeNBT         = reflect.TypeOf(nbt.TagCompound{})
+)
+
+// Reads fields of struct pointed by data. UnmarshalPacket is used if implemented (see codec_gen.go),
+// otherwise fields are read with reflection according to their tags.
+func ReadMinecraftStruct(reader io.Reader, data interface{}) error {
+	if u, ok := data.(Unmarshaler); ok {
+		return u.UnmarshalPacket(reader)
 	}
+	return readStructReflect(reader, data)
+}
 
-	elem := reflect.ValueOf(data).Elem()
-	elemType := reflect.TypeOf(data).Elem()
+func readStructReflect(reader io.Reader, data interface{}) error {
+	if err := checkStructTags(reflect.TypeOf(data).Elem()); err != nil {
+		return err
+	}
+	return readStructValue(asByter(reader), reflect.ValueOf(data).Elem(), nil)
+}
+
+// Structs containing currently processed one, fields of all of them can be used in when tag.
+type structScope struct {
+	value  reflect.Value
+	parent *structScope
+}
+
+func readStructValue(br byter, elem reflect.Value, parent *structScope) error {
+	return readStructFields(br, elem, parent, nil)
+}
+
+// Offset of top-level field in packet data, see ParseFieldOffsets
+type FieldOffset struct {
+	Name   string
+	Offset int
+}
+
+// Parses data into packet with reflection codec and returns offsets where top-level fields start,
+// if parsing fails last one is field which couldn't be read. Used by dump tools to show where parsing failed,
+// packets with hand-written Parse may be laid out differently than their fields.
+func ParseFieldOffsets(packet Packet, data []byte) ([]FieldOffset, error) {
+	reader := bytes.NewReader(data)
+	elem := reflect.ValueOf(packet).Elem()
+	if err := checkStructTags(elem.Type()); err != nil {
+		return nil, err
+	}
+	var offsets []FieldOffset
+	err := readStructFields(reader, elem, nil, func(i int) {
+		offsets = append(offsets, FieldOffset{elem.Type().Field(i).Name, len(data) - reader.Len()})
+	})
+	if err == nil && reader.Len() > 0 {
+		err = fmt.Errorf("%d bytes left after last field", reader.Len())
+	}
+	return offsets, err
+}
+
+// onField is called before reading each present field, with its index
+func readStructFields(br byter, elem reflect.Value, parent *structScope, onField func(i int)) (err error) {
+	elemType := elem.Type()
 	for i := 0; i < elem.NumField(); i++ {
 		field := elem.Field(i)
 		fieldType := elemType.Field(i)
@@ -209,316 +405,663 @@ func ReadMinecraftStruct(reader io.Reader, data interface{}) (err error) {
 		if fieldType.Tag.Get("mcignore") != "" {
 			continue
 		}
-
-		switch field.Interface().(type) {
-		case bool:
-			var val bool
-			if val, err = ReadBool(reader); err != nil {
-				return
-			}
-			field.SetBool(val)
-		case uint8:
-			var buf [1]byte
-			if _, err = io.ReadFull(reader, buf[0:]); err != nil {
-				return
-			}
-			field.SetUint(uint64(buf[0]))
-		case int8:
-			var buf [1]byte
-			if _, err = io.ReadFull(reader, buf[0:]); err != nil {
-				return
-			}
-			field.SetInt(int64(buf[0]))
-		case int16:
-			var buf [2]byte
-			if _, err = io.ReadFull(reader, buf[0:]); err != nil {
-				return
-			}
-			field.SetInt(int64(binary.BigEndian.Uint16(buf[0:])))
-		case int32:
-			var buf [4]byte
-			if _, err = io.ReadFull(reader, buf[0:]); err != nil {
-				return
-			}
-			field.SetInt(int64(binary.BigEndian.Uint32(buf[0:])))
-		case int64:
-			var buf [8]byte
-			if _, err = io.ReadFull(reader, buf[0:]); err != nil {
-				return
-			}
-			field.SetInt(int64(binary.BigEndian.Uint64(buf[0:])))
-		case VarInt:
-			var v uint64
-			v, err = binary.ReadUvarint(br)
-			if err != nil {
-				return
-			}
-			field.SetInt(int64(int32(uint32(v))))
-		case float32:
-			var buf [4]byte
-			if _, err = io.ReadFull(reader, buf[0:]); err != nil {
-				return
-			}
-			field.SetFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(buf[0:]))))
-		case float64:
-			var buf [8]byte
-			if _, err = io.ReadFull(reader, buf[0:]); err != nil {
-				return
-			}
-			field.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(buf[0:])))
-		case Identifier:
-			var identifier Identifier
-			if identifier, err = ReadIdentifier(reader); err != nil {
-				return
-			}
-			field.Set(reflect.ValueOf(identifier))
-		case []Identifier:
-			var identifiers []Identifier
-			if identifiers, err = ReadIdentifierArray(reader); err != nil {
+		present := true
+		if when := fieldType.Tag.Get("when"); when != "" {
+			present = evalWhen(when, elem, parent)
+		}
+		if present && onField != nil {
+			onField(i)
+		}
+		if present && fieldType.Tag.Get("optional") != "" {
+			if present, err = ReadBool(br); err != nil {
 				return
 			}
-			field.Set(reflect.ValueOf(identifiers))
-		case string:
-			var maxlen int
-			if maxlen, err = strconv.Atoi(fieldType.Tag.Get("max_length")); err != nil {
-				panic("Invalid max_length tag in " + fieldType.Name + ": " + err.Error())
-			}
-			var str string
-			if str, err = ReadMinecraftString(reader, maxlen); err != nil {
+		}
+		if !present {
+			field.Set(reflect.Zero(fieldType.Type))
+			continue
+		}
+		if err = readField(br, field, fieldType.Tag, elem.NumField() == i+1, elem, parent); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// reads single value, tag is tag of struct field (for array elements too), owner is struct containing field
+func readField(br byter, field reflect.Value, tag reflect.StructTag, last bool, owner reflect.Value, parent *structScope) (err error) {
+	var v uint64
+	switch field.Type() {
+	case typeBool:
+		if v, err = readUint(br, 1); err != nil {
+			return
+		}
+		field.SetBool(v == 1)
+	case typeUint8:
+		if v, err = readUint(br, 1); err != nil {
+			return
+		}
+		field.SetUint(v)
+	case typeInt8:
+		if v, err = readUint(br, 1); err != nil {
+			return
+		}
+		field.SetInt(int64(int8(v)))
+	case typeInt16:
+		if v, err = readUint(br, 2); err != nil {
+			return
+		}
+		field.SetInt(int64(int16(v)))
+	case typeInt32:
+		if v, err = readUint(br, 4); err != nil {
+			return
+		}
+		field.SetInt(int64(int32(v)))
+	case typeInt64:
+		if v, err = readUint(br, 8); err != nil {
+			return
+		}
+		field.SetInt(int64(v))
+	case typeVarInt:
+		var c VarInt
+		if c, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+		field.SetInt(int64(c))
+	case typeVarLong:
+		var c VarLong
+		if c, err = ReadVarLong(br); err != nil {
+			return
+		}
+		field.SetInt(int64(c))
+	case typeBlockPos:
+		if v, err = readUint(br, 8); err != nil {
+			return
+		}
+		field.Set(reflect.ValueOf(unpackBlockPosition(v)))
+	case typeSectionPos:
+		if v, err = readUint(br, 8); err != nil {
+			return
+		}
+		field.Set(reflect.ValueOf(unpackSectionPos(v)))
+	case typeAngle:
+		if v, err = readUint(br, 1); err != nil {
+			return
+		}
+		field.SetUint(v)
+	case typeFloat32:
+		if v, err = readUint(br, 4); err != nil {
+			return
+		}
+		field.SetFloat(float64(math.Float32frombits(uint32(v))))
+	case typeFloat64:
+		if v, err = readUint(br, 8); err != nil {
+			return
+		}
+		field.SetFloat(math.Float64frombits(v))
+	case typeIdentifier:
+		var identifier Identifier
+		if identifier, err = ReadIdentifier(br); err != nil {
+			return
+		}
+		field.SetString(string(identifier))
+	case typeIdentifiers:
+		var identifiers []Identifier
+		if identifiers, err = ReadIdentifierArray(br); err != nil {
+			return
+		}
+		field.Set(reflect.ValueOf(identifiers))
+	case typeString:
+		var maxlen int
+		if maxlen, err = strconv.Atoi(tag.Get("max_length")); err != nil {
+			panic("Invalid max_length tag of string field: " + err.Error())
+		}
+		var str string
+		if str, err = ReadMinecraftString(br, maxlen); err != nil {
+			return
+		}
+		field.SetString(str)
+	case typeBytes:
+		var maxlen int64
+		if maxlen, err = strconv.ParseInt(tag.Get("max_length"), 10, 64); err != nil {
+			panic("invalid max_length tag of []byte field")
+		}
+		prefix := tag.Get("length_prefix")
+		if prefix == "eof" && !last {
+			panic("max_length eof field is not last in struct")
+		}
+		var data []byte
+		if data, err = readBytesField(br, prefix, maxlen); err != nil {
+			return
+		}
+		field.SetBytes(data)
+	case typeEntityID:
+		var id EntityID
+		if id, err = readEntityIDField(br, tag.Get("datatype")); err != nil {
+			return
+		}
+		field.SetInt(int64(id))
+	case typeUUID:
+		// field is addressable, so data is read directly into struct
+		if _, err = io.ReadFull(br, field.Slice(0, field.Len()).Bytes()); err != nil {
+			return
+		}
+	case typeNBT:
+		var compound nbt.TagCompound
+		if compound, err = readNBTField(br); err != nil {
+			return
+		}
+		field.Set(reflect.ValueOf(compound))
+	default:
+		switch field.Kind() {
+		case reflect.Struct:
+			return readStructValue(br, field, &structScope{owner, parent})
+		case reflect.Slice:
+			var n int
+			if n, err = readCount(br, tag.Get("length_prefix"), tagMaxCount(tag)); err != nil {
 				return
 			}
-			field.SetString(str)
-		case []byte:
-			var maxlen, datalen int64
-			if maxlen, err = strconv.ParseInt(fieldType.Tag.Get("max_length"), 10, 64); err != nil {
-				panic("invalid max_length tag in " + elemType.Name())
-			}
-
-			prefix := fieldType.Tag.Get("length_prefix")
-			switch prefix {
-			case "int16":
-				var buf [2]byte
-				if _, err = io.ReadFull(reader, buf[0:]); err != nil {
-					return
-				}
-				datalen = int64(binary.BigEndian.Uint16(buf[0:]))
-			case "int32":
-				var buf [4]byte
-				if _, err = io.ReadFull(reader, buf[0:]); err != nil {
+			slice := reflect.MakeSlice(field.Type(), 0, countCapacity(n))
+			item := reflect.New(field.Type().Elem()).Elem()
+			for j := 0; j < n; j++ {
+				item.Set(reflect.Zero(item.Type()))
+				if err = readField(br, item, tag, false, owner, parent); err != nil {
 					return
 				}
-				datalen = int64(binary.BigEndian.Uint32(buf[0:]))
-			case "VarInt":
-				var v uint64
-				if v, err = binary.ReadUvarint(br); err != nil {
-					return
-				}
-				datalen = int64(uint32(v))
-			case "eof":
-				datalen = maxlen
-				if elem.NumField() > i+1 {
-					panic("max_length eof field is not last in struct")
-				}
-			default:
-				panic("invalid length_prefix tag in " + elemType.Name())
+				slice = reflect.Append(slice, item)
 			}
+			field.Set(slice)
+		default:
+			panic("Invalid field type in minecraft struct: " + field.Type().String())
+		}
+	}
+	return
+}
 
-			if datalen < 0 || datalen > maxlen {
-				return fmt.Errorf("invalid datalen value: 0 < %d < %d", datalen, maxlen)
-			}
+// > field codecs shared by reflection and generated code
 
-			var buf bytes.Buffer
-			if prefix != "eof" {
-				buf.Grow(int(datalen))
-			}
-			_, err = io.CopyN(&buf, reader, datalen) // CopyN uses io.ReaderFrom interface
-			if err == io.EOF && prefix == "eof" {
-				err = nil
-			}
-			if err != nil {
-				return
-			}
-			field.SetBytes(buf.Bytes())
-		case EntityID:
-			var buf [8]byte
-			switch fieldType.Tag.Get("datatype") {
-			case "int32":
-				_, err = io.ReadFull(reader, buf[0:4])
-				field.SetInt(int64(binary.BigEndian.Uint32(buf[0:4])))
-			case "VarInt":
-				var v uint64
-				v, err = binary.ReadUvarint(br)
-				if err != nil {
-					return
-				}
-				field.SetInt(int64(int32(uint32(v))))
-			default:
-				panic("Invalid EntityID datatype in " + elemType.Name())
-			}
+// initial capacity of buffer for []byte field
+const bytesFieldInitialSize = 4 * 1024
+
+// reads []byte field, prefix is value of length_prefix tag
+func readBytesField(br byter, prefix string, maxlen int64) (data []byte, err error) {
+	var datalen int64
+	switch prefix {
+	case "int16":
+		var v uint64
+		if v, err = readUint(br, 2); err != nil {
+			return
+		}
+		datalen = int64(v)
+	case "int32":
+		var v uint64
+		if v, err = readUint(br, 4); err != nil {
+			return
+		}
+		datalen = int64(v)
+	case "VarInt":
+		var c VarInt
+		if c, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+		datalen = int64(uint32(c))
+	case "eof":
+		datalen = maxlen
+	default:
+		panic("invalid length_prefix tag " + prefix)
+	}
+
+	if datalen < 0 || datalen > maxlen {
+		return nil, fmt.Errorf("invalid datalen value: 0 < %d < %d", datalen, maxlen)
+	}
+
+	// datalen isn't trusted, so buffer grows with data actually read
+	var buf bytes.Buffer
+	if prefix != "eof" {
+		capacity := datalen
+		if capacity > bytesFieldInitialSize {
+			capacity = bytesFieldInitialSize
+		}
+		buf.Grow(int(capacity))
+	}
+	_, err = io.CopyN(&buf, br, datalen) // CopyN uses io.ReaderFrom interface
+	if err == io.EOF && prefix == "eof" {
+		err = nil
+	}
+	return buf.Bytes(), err
+}
+
+func writeBytesField(writer io.Writer, prefix string, maxlen int64, data []byte) (err error) {
+	if int64(len(data)) > maxlen {
+		return fmt.Errorf("[]byte field too long: %d, max_length %d", len(data), maxlen)
+	}
+	switch prefix {
+	case "int16":
+		err = writeUint(writer, uint64(len(data)), 2)
+	case "int32":
+		err = writeUint(writer, uint64(len(data)), 4)
+	case "VarInt":
+		err = WriteVarInt(writer, VarInt(len(data)))
+	case "eof":
+	default:
+		panic("Invalid length_prefix tag " + prefix)
+	}
+	if err != nil {
+		return
+	}
+	_, err = writer.Write(data)
+	return
+}
+
+// reads element count of array field, prefix is value of length_prefix tag
+func readCount(br byter, prefix string, maxCount int) (int, error) {
+	var n int
+	switch prefix {
+	case "int16":
+		v, err := readUint(br, 2)
+		if err != nil {
+			return 0, err
+		}
+		n = int(int16(v))
+	case "int32":
+		v, err := readUint(br, 4)
+		if err != nil {
+			return 0, err
+		}
+		n = int(int32(v))
+	case "VarInt":
+		c, err := ReadVarIntFrom(br)
+		if err != nil {
+			return 0, err
+		}
+		n = int(c)
+	default:
+		panic("invalid length_prefix tag of array " + prefix)
+	}
+	if n < 0 || n > maxCount {
+		return 0, fmt.Errorf("invalid array length: %d, max_count %d", n, maxCount)
+	}
+	return n, nil
+}
+
+// initial capacity of array with count read from packet, so it grows with elements actually read
+func countCapacity(n int) int {
+	if n > 1024 {
+		return 1024
+	}
+	return n
+}
+
+func writeCount(writer io.Writer, prefix string, maxCount int, n int) error {
+	if n > maxCount {
+		return fmt.Errorf("array too long: %d, max_count %d", n, maxCount)
+	}
+	switch prefix {
+	case "int16":
+		return writeUint(writer, uint64(n), 2)
+	case "int32":
+		return writeUint(writer, uint64(n), 4)
+	case "VarInt":
+		return WriteVarInt(writer, VarInt(n))
+	default:
+		panic("invalid length_prefix tag of array " + prefix)
+	}
+}
+
+func tagMaxCount(tag reflect.StructTag) int {
+	n, err := strconv.Atoi(tag.Get("max_count"))
+	if err != nil {
+		panic("Invalid max_count tag of array: " + err.Error())
+	}
+	return n
+}
+
+// Evaluates when tag, eg. "Mode==0,2" or "Action!=4". Field is looked up in struct elem first, then in parent structs.
+func evalWhen(when string, elem reflect.Value, parent *structScope) bool {
+	name, op, values, _ := parseWhen(when) // checked by checkStructTags
+	field := elem.FieldByName(name)
+	for ; !field.IsValid() && parent != nil; parent = parent.parent {
+		field = parent.value.FieldByName(name)
+	}
+
+	var v int64
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v = field.Int()
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		v = int64(field.Uint())
+	case reflect.Bool:
+		if field.Bool() {
+			v = 1
+		}
+	default:
+		panic("Invalid field in when tag: " + when)
+	}
+
+	matches := false
+	for _, value := range values {
+		if v == value {
+			matches = true
+			break
+		}
+	}
+	return matches == (op == "==")
+}
+
+// splits when tag into field name, operator (== or !=) and compared values
+func parseWhen(when string) (name, op string, values []int64, err error) {
+	i := strings.Index(when, "=")
+	if i < 1 || (when[i-1] != '!' && (len(when) == i+1 || when[i+1] != '=')) {
+		return "", "", nil, fmt.Errorf("invalid when tag %q", when)
+	}
+	if when[i-1] == '!' {
+		name, op = when[:i-1], "!="
+		when = when[i+1:]
+	} else {
+		name, op = when[:i], "=="
+		when = when[i+2:]
+	}
+	for _, s := range strings.Split(when, ",") {
+		var v int64
+		if v, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return "", "", nil, fmt.Errorf("invalid when tag %q: %s", when, err)
+		}
+		values = append(values, v)
+	}
+	return
+}
+
+// > tag checks, same rules as packetgen
+
+var checkedStructs sync.Map // reflect.Type -> error, nil if tags are valid
+
+// Checks tags of struct read or written with reflection codec once per type, so invalid tags
+// are returned as error instead of panicking in the middle of decoding.
+func checkStructTags(typ reflect.Type) error {
+	if v, ok := checkedStructs.Load(typ); ok {
+		err, _ := v.(error)
+		return err
+	}
+	err := checkStruct(typ, nil)
+	if err != nil {
+		err = fmt.Errorf("invalid tags of %s: %s", typ, err)
+	}
+	checkedStructs.Store(typ, err)
+	return err
+}
+
+// structs containing checked one, like structScope
+type typeScope struct {
+	typ    reflect.Type
+	parent *typeScope
+}
+
+func checkStruct(typ reflect.Type, parent *typeScope) error {
+	sc := &typeScope{typ, parent}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Tag.Get("mcignore") != "" {
+			continue
+		}
+		if when := f.Tag.Get("when"); when != "" {
+			name, _, _, err := parseWhen(when)
 			if err != nil {
-				return
+				return fmt.Errorf("field %s: %s", f.Name, err)
 			}
-		case uuid.UUID:
-			var buf uuid.UUID
-			_, err = io.ReadFull(reader, buf[:])
-			if err != nil {
-				return
+			if !sc.whenField(name) {
+				return fmt.Errorf("field %s: invalid field in when tag %q", f.Name, when)
 			}
-			field.Set(reflect.ValueOf(buf))
-		case nbt.TagCompound:
-			nbtReader, err := nbt.NewParser(reader)
-			if err != nil {
-				return err
+		}
+		if err := checkFieldType(f.Type, f.Tag, typ.NumField() == i+1, sc); err != nil {
+			return fmt.Errorf("field %s: %s", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// reports if field can be used in when tag, it's looked up like in evalWhen
+func (sc *typeScope) whenField(name string) bool {
+	for ; sc != nil; sc = sc.parent {
+		if f, ok := sc.typ.FieldByName(name); ok {
+			switch f.Type.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Bool:
+				return true
 			}
-			tag, _, err := nbtReader.Read()
-			if err != nil {
-				return err
+			return false
+		}
+	}
+	return false
+}
+
+func checkFieldType(typ reflect.Type, tag reflect.StructTag, last bool, sc *typeScope) error {
+	switch typ {
+	case typeBool, typeUint8, typeInt8, typeInt16, typeInt32, typeInt64, typeVarInt, typeVarLong, typeBlockPos,
+		typeSectionPos, typeAngle, typeFloat32, typeFloat64, typeIdentifier, typeIdentifiers, typeUUID, typeNBT:
+		return nil
+	case typeString:
+		_, err := strconv.Atoi(tag.Get("max_length"))
+		return err
+	case typeBytes:
+		if _, err := strconv.ParseInt(tag.Get("max_length"), 10, 64); err != nil {
+			return err
+		}
+		switch prefix := tag.Get("length_prefix"); prefix {
+		case "int16", "int32", "VarInt":
+			return nil
+		case "eof":
+			if !last {
+				return errors.New("length_prefix eof field is not last in struct")
 			}
-			field.Set(reflect.ValueOf(tag))
+			return nil
 		default:
-			panic(fmt.Sprintf("Invalid field %d in minecraft struct %s", i, elemType.Name()))
+			return fmt.Errorf("invalid length_prefix %q", prefix)
 		}
+	case typeEntityID:
+		switch tag.Get("datatype") {
+		case "int32", "VarInt":
+			return nil
+		}
+		return fmt.Errorf("invalid datatype %q", tag.Get("datatype"))
 	}
-	return
+	switch typ.Kind() {
+	case reflect.Struct:
+		return checkStruct(typ, sc)
+	case reflect.Slice:
+		if typ.Elem() == typeBytes {
+			return errors.New("[]byte can't be array element")
+		}
+		switch tag.Get("length_prefix") {
+		case "int16", "int32", "VarInt":
+		default:
+			return fmt.Errorf("invalid length_prefix of array %q", tag.Get("length_prefix"))
+		}
+		if _, err := strconv.Atoi(tag.Get("max_count")); err != nil {
+			return err
+		}
+		return checkFieldType(typ.Elem(), tag, false, sc)
+	}
+	return fmt.Errorf("unsupported type %s", typ)
+}
+
+// datatype is value of datatype tag
+func readEntityIDField(br byter, datatype string) (EntityID, error) {
+	switch datatype {
+	case "int32":
+		v, err := readUint(br, 4)
+		return EntityID(int32(v)), err
+	case "VarInt":
+		c, err := ReadVarIntFrom(br)
+		return EntityID(c), err
+	default:
+		panic("Invalid EntityID datatype " + datatype)
+	}
+}
+
+func writeEntityIDField(writer io.Writer, datatype string, id EntityID) error {
+	switch datatype {
+	case "int32":
+		return writeUint(writer, uint64(id), 4)
+	case "VarInt":
+		return WriteVarInt(writer, VarInt(id))
+	default:
+		panic("Invalid EntityID datatype " + datatype)
+	}
+}
+
+// TAG_End in place of compound is nil compound (no NBT)
+func readNBTField(reader io.Reader) (nbt.TagCompound, error) {
+	tag, _, err := nbt.NewDecoder(reader).Decode()
+	return tag, err
+}
+
+func writeNBTField(writer io.Writer, tag nbt.TagCompound) error {
+	return nbt.NewEncoder(writer).Encode(tag, "")
 }
 
-func WriteMinecraftStruct(writer io.Writer, data interface{}) (err error) {
-	elem := reflect.ValueOf(data).Elem()
-	elemType := reflect.TypeOf(data).Elem()
+// X, Y, Z of BlockPosition or SectionPos, read without boxing struct in interface
+func xyzFields(field reflect.Value) (x, y, z int32) {
+	return int32(field.Field(0).Int()), int32(field.Field(1).Int()), int32(field.Field(2).Int())
+}
+
+// wraps reader if it doesn't implement byter
+func asByter(reader io.Reader) byter {
+	if br, ok := reader.(byter); ok {
+		return br
+	}
+	return &dummyByteReader{Reader: reader}
+}
+
+// wraps writer if it doesn't implement io.ByteWriter
+func asByteWriter(writer io.Writer) io.Writer {
+	if _, ok := writer.(io.ByteWriter); ok {
+		return writer
+	}
+	return &dummyByteWriter{Writer: writer}
+}
+
+// dummyByteWriter - used in WriteMinecraftStruct
+
+type dummyByteWriter struct {
+	io.Writer
+	buf [1]byte
+}
+
+func (b *dummyByteWriter) WriteByte(c byte) error {
+	b.buf[0] = c
+	_, err := b.Write(b.buf[:])
+	return err
+}
+
+// Writes fields of struct pointed by data, MarshalPacket is used if implemented.
+func WriteMinecraftStruct(writer io.Writer, data interface{}) error {
+	if m, ok := data.(Marshaler); ok {
+		return m.MarshalPacket(writer)
+	}
+	return writeStructReflect(writer, data)
+}
+
+func writeStructReflect(writer io.Writer, data interface{}) error {
+	if err := checkStructTags(reflect.TypeOf(data).Elem()); err != nil {
+		return err
+	}
+	return writeStructValue(asByteWriter(writer), reflect.ValueOf(data).Elem(), nil)
+}
+
+func writeStructValue(writer io.Writer, elem reflect.Value, parent *structScope) (err error) {
+	elemType := elem.Type()
 	for i := 0; i < elem.NumField(); i++ {
-		field := elem.Field(i).Interface()
+		field := elem.Field(i)
 		fieldType := elemType.Field(i)
 
 		if fieldType.Tag.Get("mcignore") != "" {
 			continue
 		}
-
-		switch field.(type) { // TODO: tutaj powinno byc przypisanie, to bysmy nie rzutowali w kazdym case
-		case bool:
-			if err = WriteBool(writer, field.(bool)); err != nil {
-				return
-			}
-		case uint8:
-			buf := []byte{field.(uint8)}
-			if _, err = writer.Write(buf); err != nil {
-				return
-			}
-		case int8:
-			buf := []uint8{byte(field.(int8))}
-			if _, err = writer.Write(buf); err != nil {
-				return
-			}
-		case int16:
-			var buf [2]byte
-			binary.BigEndian.PutUint16(buf[0:], uint16(field.(int16)))
-			if _, err = writer.Write(buf[0:]); err != nil {
-				return
-			}
-		case int32:
-			var buf [4]byte
-			binary.BigEndian.PutUint32(buf[0:], uint32(field.(int32)))
-			if _, err = writer.Write(buf[0:]); err != nil {
-				return
-			}
-		case int64:
-			var buf [8]byte
-			binary.BigEndian.PutUint64(buf[0:], uint64(field.(int64)))
-			if _, err = writer.Write(buf[0:]); err != nil {
-				return
-			}
-		case VarInt:
-			var buf [8]byte
-			n := binary.PutUvarint(buf[:], uint64(uint32(field.(VarInt))))
-			if _, err = writer.Write(buf[:n]); err != nil {
-				return
-			}
-		case float32:
-			var buf [4]byte
-			binary.BigEndian.PutUint32(buf[0:], math.Float32bits(field.(float32)))
-			if _, err = writer.Write(buf[0:]); err != nil {
-				return
-			}
-		case float64:
-			var buf [8]byte
-			binary.BigEndian.PutUint64(buf[0:], math.Float64bits(field.(float64)))
-			if _, err = writer.Write(buf[0:]); err != nil {
-				return
-			}
-		case Identifier:
-			if err = WriteIdentifier(writer, field.(Identifier)); err != nil {
+		if when := fieldType.Tag.Get("when"); when != "" && !evalWhen(when, elem, parent) {
+			continue
+		}
+		if fieldType.Tag.Get("optional") != "" {
+			present := !field.IsZero()
+			if err = WriteBool(writer, present); err != nil {
 				return
 			}
-		case []Identifier:
-			if err = WriteIdentifierArray(writer, field.([]Identifier)); err != nil {
-				return
+			if !present {
+				continue
 			}
-		case string:
-			if err = WriteMinecraftString(writer, field.(string)); err != nil {
+		}
+		if err = writeField(writer, field, fieldType.Tag, elem.NumField() == i+1, elem, parent); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func writeField(writer io.Writer, field reflect.Value, tag reflect.StructTag, last bool, owner reflect.Value, parent *structScope) (err error) {
+	switch field.Type() {
+	case typeBool:
+		err = WriteBool(writer, field.Bool())
+	case typeUint8:
+		err = writeUint(writer, field.Uint(), 1)
+	case typeInt8:
+		err = writeUint(writer, uint64(field.Int()), 1)
+	case typeInt16:
+		err = writeUint(writer, uint64(field.Int()), 2)
+	case typeInt32:
+		err = writeUint(writer, uint64(field.Int()), 4)
+	case typeInt64:
+		err = writeUint(writer, uint64(field.Int()), 8)
+	case typeVarInt:
+		err = WriteVarInt(writer, VarInt(field.Int()))
+	case typeVarLong:
+		err = WriteVarLong(writer, VarLong(field.Int()))
+	case typeBlockPos:
+		x, y, z := xyzFields(field)
+		err = writeUint(writer, BlockPosition{x, y, z}.pack(), 8)
+	case typeSectionPos:
+		x, y, z := xyzFields(field)
+		err = writeUint(writer, SectionPos{x, y, z}.pack(), 8)
+	case typeAngle:
+		err = writeUint(writer, field.Uint(), 1)
+	case typeFloat32:
+		err = writeUint(writer, uint64(math.Float32bits(float32(field.Float()))), 4)
+	case typeFloat64:
+		err = writeUint(writer, math.Float64bits(field.Float()), 8)
+	case typeIdentifier, typeString:
+		err = WriteMinecraftString(writer, field.String())
+	case typeIdentifiers:
+		err = WriteIdentifierArray(writer, field.Interface().([]Identifier))
+	case typeBytes:
+		var maxlen int64
+		if maxlen, err = strconv.ParseInt(tag.Get("max_length"), 10, 64); err != nil {
+			panic("invalid max_length tag of []byte field")
+		}
+		prefix := tag.Get("length_prefix")
+		if prefix == "eof" && !last {
+			panic("max_length eof field is not last in struct")
+		}
+		err = writeBytesField(writer, prefix, maxlen, field.Bytes())
+	case typeEntityID:
+		err = writeEntityIDField(writer, tag.Get("datatype"), EntityID(field.Int()))
+	case typeUUID:
+		_, err = writer.Write(field.Slice(0, field.Len()).Bytes())
+	case typeNBT:
+		err = writeNBTField(writer, field.Interface().(nbt.TagCompound))
+	default:
+		switch field.Kind() {
+		case reflect.Struct:
+			return writeStructValue(writer, field, &structScope{owner, parent})
+		case reflect.Slice:
+			n := field.Len()
+			if err = writeCount(writer, tag.Get("length_prefix"), tagMaxCount(tag), n); err != nil {
 				return
 			}
-		case []byte:
-			bytesArr := field.([]byte)
-			bytesLen := len(bytesArr)
-			prefix := fieldType.Tag.Get("length_prefix")
-			switch prefix {
-			case "int16":
-				var buf [2]byte
-				binary.BigEndian.PutUint16(buf[0:], uint16(bytesLen))
-				if _, err = writer.Write(buf[0:]); err != nil {
-					return
-				}
-			case "int32":
-				var buf [4]byte
-				binary.BigEndian.PutUint32(buf[0:], uint32(bytesLen))
-				if _, err = writer.Write(buf[0:]); err != nil {
-					return
-				}
-			case "VarInt":
-				var buf [8]byte
-				n := binary.PutUvarint(buf[:], uint64(uint32(bytesLen)))
-				if _, err = writer.Write(buf[:n]); err != nil {
+			for j := 0; j < n; j++ {
+				if err = writeField(writer, field.Index(j), tag, false, owner, parent); err != nil {
 					return
 				}
-			case "eof":
-				if elem.NumField() > i+1 {
-					panic("max_length eof field is not last in struct")
-				}
-			default:
-				panic("Invalid length_prefix tag in " + elemType.Name())
-			}
-
-			if _, err = writer.Write(bytesArr); err != nil {
-				return
-			}
-		case EntityID:
-			var buf [8]byte
-			switch fieldType.Tag.Get("datatype") {
-			case "int32":
-				binary.BigEndian.PutUint32(buf[:4], uint32(int32(field.(EntityID))))
-				_, err = writer.Write(buf[:4])
-			case "VarInt":
-				n := binary.PutUvarint(buf[:], uint64(uint32(int32(field.(EntityID)))))
-				_, err = writer.Write(buf[:n])
-			default:
-				panic("Invalid EntityID datatype in " + elemType.Name())
-			}
-			if err != nil {
-				return
-			}
-		case uuid.UUID:
-			value := field.(uuid.UUID)
-			_, err = writer.Write(value[:])
-			if err != nil {
-				return
-			}
-		case nbt.TagCompound:
-			tag := field.(nbt.TagCompound)
-			err = nbt.NewWriter(writer).Write(tag, "")
-			if err != nil {
-				return
 			}
 		default:
-			panic("Invalid field in minecraft struct - " + fieldType.Name)
+			panic("Invalid field type in minecraft struct: " + field.Type().String())
 		}
 	}
 	return
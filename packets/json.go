@@ -0,0 +1,324 @@
+package packets
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// > JSON encoding of packets, used by traces, dumps and test fixtures
+//
+// Exported fields without mcignore tag are encoded in declaration order, with Go names as keys:
+//  []byte                 base64 (nil is null)
+//  string format:"chat"   json chat embedded as JSON value, empty is null
+//                         (chat which isn't valid JSON is encoded as string, so it's decoded with quotes)
+//  float32, float64       number, or "NaN", "+Inf", "-Inf"
+//  nbt.TagCompound        SNBT string (nil is null)
+//  json.Marshaler and encoding.TextMarshaler types (uuid.UUID, EntityMetadata) use own encoding.
+
+// envelope of packet, Fields for parsed packets, Raw for RawPacket
+type jsonPacket struct {
+	State      string          `json:"state"`
+	Direction  string          `json:"direction"`
+	ID         string          `json:"id"`
+	Type       string          `json:"type,omitempty"`
+	Fields     json.RawMessage `json:"fields,omitempty"`
+	Raw        []byte          `json:"raw,omitempty"`
+	DataLength VarInt          `json:"data_length,omitempty"`
+	Threshold  *VarInt         `json:"threshold,omitempty"` // compression threshold of raw packet, absent for NoCompression
+	Size       int             `json:"size,omitempty"`      // whole size of streamed raw packet
+}
+
+var (
+	typeJSONMarshaler   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	typeJSONUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	typeTextMarshaler   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typeTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Encodes packet with its state, direction, id and type. Streamed RawPacket has only head of payload,
+// so Size is added then.
+func MarshalPacketJSON(packet Packet, state ConnState, direction Direction) ([]byte, error) {
+	envelope := jsonPacket{
+		State:     state.String(),
+		Direction: direction.String(),
+		ID:        fmt.Sprintf("0x%02X", packet.PacketID()),
+	}
+	if raw, ok := packet.(RawPacket); ok {
+		envelope.Raw, envelope.DataLength = raw.Payload, raw.DataLength
+		if raw.compthres != NoCompression {
+			envelope.Threshold = &raw.compthres
+		}
+		if raw.Streamed() {
+			envelope.Size = raw.Size()
+		}
+		return json.Marshal(envelope)
+	}
+	fields, err := MarshalFieldsJSON(packet)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Type = reflect.TypeOf(packet).Elem().Name()
+	envelope.Fields = fields
+	return json.Marshal(envelope)
+}
+
+// Decodes packet encoded by MarshalPacketJSON. RawPacket keeps compression threshold of its connection,
+// so its payload can be written or decompressed.
+func UnmarshalPacketJSON(data []byte) (packet Packet, state ConnState, direction Direction, err error) {
+	var envelope jsonPacket
+	if err = json.Unmarshal(data, &envelope); err != nil {
+		return
+	}
+	id, err := strconv.ParseInt(envelope.ID, 0, 32)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid packet id %q", envelope.ID)
+	}
+	if state, err = parseConnState(envelope.State); err != nil {
+		return
+	}
+	switch envelope.Direction {
+	case "SB":
+		direction = ServerBound
+	case "CB":
+		direction = ClientBound
+	default:
+		return nil, 0, 0, fmt.Errorf("invalid direction %q", envelope.Direction)
+	}
+
+	if envelope.Type == "" {
+		raw := NewRawPacket(VarInt(id), envelope.Raw)
+		raw.DataLength = envelope.DataLength
+		if envelope.Threshold != nil {
+			raw.compthres = *envelope.Threshold
+		}
+		return raw, state, direction, nil
+	}
+	packet = NewPacket(VarInt(id), state, direction)
+	if packet == nil {
+		return nil, 0, 0, fmt.Errorf("unknown packet %s %s_%02X", state, direction, id)
+	}
+	if name := reflect.TypeOf(packet).Elem().Name(); name != envelope.Type {
+		return nil, 0, 0, fmt.Errorf("packet %s_%02X is %s, not %s", direction, id, name, envelope.Type)
+	}
+	err = UnmarshalFieldsJSON(envelope.Fields, packet)
+	return
+}
+
+func parseConnState(s string) (ConnState, error) {
+	for _, state := range []ConnState{HANDSHAKING, STATUS, LOGIN, PLAY} {
+		if state.String() == s {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid state %q", s)
+}
+
+// Encodes fields of struct pointed by data as JSON object
+func MarshalFieldsJSON(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := marshalJSONValue(&buf, reflect.ValueOf(data).Elem(), "")
+	return buf.Bytes(), err
+}
+
+// Decodes JSON object into struct pointed by data, unknown fields are errors
+func UnmarshalFieldsJSON(raw []byte, data interface{}) error {
+	return unmarshalJSONValue(raw, reflect.ValueOf(data).Elem(), "")
+}
+
+func marshalJSONValue(buf *bytes.Buffer, v reflect.Value, format string) error {
+	if v.Type().Implements(typeJSONMarshaler) || v.Type().Implements(typeTextMarshaler) {
+		data, err := json.Marshal(v.Interface())
+		buf.Write(data)
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		data, _ := json.Marshal(v.Interface())
+		buf.Write(data)
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		switch {
+		case math.IsNaN(f):
+			buf.WriteString(`"NaN"`)
+		case math.IsInf(f, 1):
+			buf.WriteString(`"+Inf"`)
+		case math.IsInf(f, -1):
+			buf.WriteString(`"-Inf"`)
+		default:
+			buf.WriteString(strconv.FormatFloat(f, 'g', -1, v.Type().Bits()))
+		}
+	case reflect.String:
+		if format == "chat" && v.Len() == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		if format == "chat" && json.Valid([]byte(v.String())) {
+			return json.Compact(buf, []byte(v.String()))
+		}
+		data, _ := json.Marshal(v.String())
+		buf.Write(data)
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+		} else if v.Type().Elem().Kind() == reflect.Uint8 {
+			buf.WriteByte('"')
+			buf.WriteString(base64.StdEncoding.EncodeToString(v.Bytes()))
+			buf.WriteByte('"')
+		} else {
+			return marshalJSONArray(buf, v, format)
+		}
+	case reflect.Array:
+		return marshalJSONArray(buf, v, format)
+	case reflect.Struct:
+		buf.WriteByte('{')
+		first := true
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" || field.Tag.Get("mcignore") != "" {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			name, _ := json.Marshal(field.Name)
+			buf.Write(name)
+			buf.WriteByte(':')
+			if err := marshalJSONValue(buf, v.Field(i), field.Tag.Get("format")); err != nil {
+				return fmt.Errorf("%s: %w", field.Name, err)
+			}
+		}
+		buf.WriteByte('}')
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return marshalJSONValue(buf, v.Elem(), format)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func marshalJSONArray(buf *bytes.Buffer, v reflect.Value, format string) error {
+	buf.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := marshalJSONValue(buf, v.Index(i), format); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+func unmarshalJSONValue(raw json.RawMessage, v reflect.Value, format string) error {
+	if reflect.PtrTo(v.Type()).Implements(typeJSONUnmarshaler) || reflect.PtrTo(v.Type()).Implements(typeTextUnmarshaler) {
+		return json.Unmarshal(raw, v.Addr().Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			switch s {
+			case "NaN":
+				v.SetFloat(math.NaN())
+			case "+Inf":
+				v.SetFloat(math.Inf(1))
+			case "-Inf":
+				v.SetFloat(math.Inf(-1))
+			default:
+				return fmt.Errorf("invalid float %q", s)
+			}
+			return nil
+		}
+		return json.Unmarshal(raw, v.Addr().Interface())
+	case reflect.String:
+		if format == "chat" && !bytes.Equal(raw, []byte("null")) { // any JSON value except null is chat
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, raw); err != nil {
+				return err
+			}
+			v.SetString(compact.String())
+			return nil
+		}
+		v.SetString("")
+		return json.Unmarshal(raw, v.Addr().Interface())
+	case reflect.Slice:
+		if bytes.Equal(raw, []byte("null")) {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return json.Unmarshal(raw, v.Addr().Interface())
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return err
+		}
+		v.Set(reflect.MakeSlice(v.Type(), len(items), len(items)))
+		for i, item := range items {
+			if err := unmarshalJSONValue(item, v.Index(i), format); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Array:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return err
+		}
+		if len(items) != v.Len() {
+			return fmt.Errorf("array of %d elements, expected %d", len(items), v.Len())
+		}
+		for i, item := range items {
+			if err := unmarshalJSONValue(item, v.Index(i), format); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return err
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			value, ok := fields[field.Name]
+			if !ok || field.PkgPath != "" || field.Tag.Get("mcignore") != "" {
+				continue
+			}
+			delete(fields, field.Name)
+			if err := unmarshalJSONValue(value, v.Field(i), field.Tag.Get("format")); err != nil {
+				return fmt.Errorf("%s: %w", field.Name, err)
+			}
+		}
+		for name := range fields {
+			return fmt.Errorf("unknown field %s in %s", name, v.Type())
+		}
+		return nil
+	case reflect.Ptr:
+		if bytes.Equal(raw, []byte("null")) {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		return unmarshalJSONValue(raw, v.Elem(), format)
+	case reflect.Interface:
+		return errors.New("interface fields can't be decoded")
+	}
+	return json.Unmarshal(raw, v.Addr().Interface())
+}
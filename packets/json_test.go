@@ -0,0 +1,150 @@
+package packets
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Craftserve/potoq/packets/nbt"
+)
+
+// every packet NewPacket can return, including hand-written ones, is encoded back to the same bytes
+func TestFieldsJSONRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, g := range registeredPackets() {
+		for n := 0; n < 20; n++ {
+			packet := NewPacket(g.id, g.state, g.direction)
+			randomFill(rnd, packet)
+			data, err := MarshalFieldsJSON(packet)
+			if err != nil {
+				t.Fatalf("%s: marshal: %s", g.key(), err)
+			}
+			decoded := NewPacket(g.id, g.state, g.direction)
+			if err := UnmarshalFieldsJSON(data, decoded); err != nil {
+				t.Fatalf("%s: unmarshal: %s\njson: %s", g.key(), err, data)
+			}
+
+			var want, got bytes.Buffer
+			if err := packet.Serialize(&want); err != nil {
+				t.Fatalf("%s: serialize: %s", g.key(), err)
+			}
+			if err := decoded.Serialize(&got); err != nil {
+				t.Fatalf("%s: serialize of decoded packet: %s", g.key(), err)
+			}
+			if !bytes.Equal(got.Bytes(), want.Bytes()) {
+				t.Fatalf("%s: JSON round trip differs\njson: %s\ngot:  %x\nwant: %x", g.key(), data, got.Bytes(), want.Bytes())
+			}
+		}
+	}
+
+	// Go type of interface field isn't known, so it can't be decoded
+	var entry MetadataEntry
+	if err := UnmarshalFieldsJSON([]byte(`{"Index":0,"Type":0,"Value":1}`), &entry); err == nil {
+		t.Fatal("interface field decoded")
+	}
+}
+
+func TestPacketJSONEnvelope(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, entry := range specPackets {
+		typ := reflect.TypeOf(entry.packet).Elem()
+		packet := reflect.New(typ).Interface().(Packet)
+		randomFill(rnd, packet)
+		data, err := MarshalPacketJSON(packet, PLAY, entry.direction)
+		if err != nil {
+			t.Fatalf("%s: marshal: %s", typ.Name(), err)
+		}
+		decoded, state, direction, err := UnmarshalPacketJSON(data)
+		if err != nil {
+			t.Fatalf("%s: unmarshal: %s\njson: %s", typ.Name(), err, data)
+		}
+		if reflect.TypeOf(decoded) != reflect.TypeOf(packet) || state != PLAY || direction != entry.direction {
+			t.Fatalf("%s: decoded %T %s %s", typ.Name(), decoded, state, direction)
+		}
+	}
+
+	raw := NewRawPacket(0x22, []byte{1, 2, 3})
+	raw.DataLength = 300
+	data, err := MarshalPacketJSON(raw, PLAY, ClientBound)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"state":"PLAY","direction":"CB","id":"0x22","raw":"AQID","data_length":300}`
+	if string(data) != want {
+		t.Fatalf("raw packet: got %s, want %s", data, want)
+	}
+	decoded, _, _, err := UnmarshalPacketJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.(RawPacket); got.ID != raw.ID || got.DataLength != raw.DataLength || !bytes.Equal(got.Payload, raw.Payload) {
+		t.Fatalf("raw packet: decoded %+v", got)
+	}
+
+	// raw packets of compressed connections keep their threshold, so decoded ones are written the same
+	big := &PluginMessagePacketCB{Channel: "test:big", Payload: []byte(strings.Repeat("compressible ", 100))}
+	for _, threshold := range []int{NoCompression, 0, 256} {
+		input := writePackets(t, threshold, big)
+		raw, err := NewPacketReader(bytes.NewReader(input), threshold).ReadPacket()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := MarshalPacketJSON(raw, PLAY, ClientBound)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, _, _, err := UnmarshalPacketJSON(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := writePackets(t, threshold, decoded); !bytes.Equal(out, input) {
+			t.Errorf("threshold %d: decoded raw packet written as %x, expected %x", threshold, out, input)
+		}
+		if out, want := writePackets(t, NoCompression, decoded), writePackets(t, NoCompression, big); !bytes.Equal(out, want) {
+			t.Errorf("threshold %d: decoded raw packet decompressed to %x, expected %x", threshold, out, want)
+		}
+	}
+
+	if _, _, _, err := UnmarshalPacketJSON([]byte(`{"state":"PLAY","direction":"CB","id":"0x19","type":"TitlePacketCB","fields":{}}`)); err == nil {
+		t.Fatal("type mismatch not detected")
+	}
+}
+
+func TestPacketJSONFormat(t *testing.T) {
+	packet := &KickPacketCB{Message: `{"text": "bye"}`}
+	if data, _ := MarshalFieldsJSON(packet); string(data) != `{"Message":{"text":"bye"}}` {
+		t.Fatalf("chat: got %s", data)
+	}
+	packet.Message = "not json"
+	if data, _ := MarshalFieldsJSON(packet); string(data) != `{"Message":"not json"}` {
+		t.Fatalf("invalid chat: got %s", data)
+	}
+
+	block := &BlockEntityDataPacketCB{Data: nbt.TagCompound{"id": nbt.TagString("minecraft:sign")}}
+	data, err := MarshalFieldsJSON(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"Data":"{id:\"minecraft:sign\"}"`) {
+		t.Fatalf("nbt: got %s", data)
+	}
+
+	particle := &ParticlePacketCB{OffsetX: float32(math.NaN()), OffsetY: float32(math.Inf(-1))}
+	if data, err = MarshalFieldsJSON(particle); err != nil {
+		t.Fatal(err)
+	}
+	var decoded ParticlePacketCB
+	if err := UnmarshalFieldsJSON(data, &decoded); err != nil {
+		t.Fatalf("%s\njson: %s", err, data)
+	}
+	if !math.IsNaN(float64(decoded.OffsetX)) || !math.IsInf(float64(decoded.OffsetY), -1) {
+		t.Fatalf("floats: decoded %v %v from %s", decoded.OffsetX, decoded.OffsetY, data)
+	}
+
+	if err := UnmarshalFieldsJSON([]byte(`{"Mesage":"x"}`), packet); err == nil {
+		t.Fatal("unknown field not detected")
+	}
+}
@@ -55,7 +55,7 @@ func (packet *LoginSuccessPacket) Direction() Direction {
 // S->C LoginKickPacket
 
 type LoginKickPacket struct {
-	Message string `max_length:"256"`
+	Message string `max_length:"256" format:"chat"`
 }
 
 func (packet *LoginKickPacket) PacketID() VarInt {
@@ -0,0 +1,289 @@
+package nbt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf16"
+)
+
+// Same limits as vanilla uses for NBT received from network
+const (
+	DefaultMaxDepth = 512
+	DefaultMaxSize  = 2097152
+)
+
+var (
+	ErrTooDeep  = errors.New("nbt: nesting too deep")
+	ErrTooLarge = errors.New("nbt: data too large")
+)
+
+// Decoder reads NBT without reading ahead, so it can be used on packet stream
+type Decoder struct {
+	MaxDepth int   // nesting of lists and compounds
+	MaxSize  int64 // bytes read by single Decode
+
+	r    io.Reader
+	read int64
+	buf  [8]byte
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{MaxDepth: DefaultMaxDepth, MaxSize: DefaultMaxSize, r: r}
+}
+
+// Decode reads named root compound. TAG_End in place of root is nil compound, like vanilla sends missing NBT.
+func (d *Decoder) Decode() (root TagCompound, name string, err error) {
+	d.read = 0
+	typ, err := d.readType()
+	if err != nil || typ == TagTypeEnd {
+		return
+	}
+	if typ != TagTypeCompound {
+		return nil, "", fmt.Errorf("nbt: root tag is %s", typ)
+	}
+	if name, err = d.readString(); err != nil {
+		return
+	}
+	tag, err := d.readPayload(TagTypeCompound, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	return tag.(TagCompound), name, nil
+}
+
+func (d *Decoder) readPayload(typ TagType, depth int) (Tag, error) {
+	switch typ {
+	case TagTypeByte:
+		b, err := d.readN(1)
+		if err != nil {
+			return nil, err
+		}
+		return TagByte(b[0]), nil
+	case TagTypeShort:
+		b, err := d.readN(2)
+		if err != nil {
+			return nil, err
+		}
+		return TagShort(binary.BigEndian.Uint16(b)), nil
+	case TagTypeInt:
+		v, err := d.readInt()
+		if err != nil {
+			return nil, err
+		}
+		return TagInt(v), nil
+	case TagTypeLong:
+		b, err := d.readN(8)
+		if err != nil {
+			return nil, err
+		}
+		return TagLong(binary.BigEndian.Uint64(b)), nil
+	case TagTypeFloat:
+		b, err := d.readN(4)
+		if err != nil {
+			return nil, err
+		}
+		return TagFloat(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case TagTypeDouble:
+		b, err := d.readN(8)
+		if err != nil {
+			return nil, err
+		}
+		return TagDouble(math.Float64frombits(binary.BigEndian.Uint64(b))), nil
+	case TagTypeString:
+		s, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		return TagString(s), nil
+	case TagTypeByteArray:
+		n, err := d.readLength(1)
+		if err != nil {
+			return nil, err
+		}
+		data := make(TagByteArray, n)
+		_, err = io.ReadFull(d.r, data)
+		return data, err
+	case TagTypeIntArray:
+		n, err := d.readLength(4)
+		if err != nil {
+			return nil, err
+		}
+		data := make(TagIntArray, n)
+		err = binary.Read(d.r, binary.BigEndian, data)
+		return data, err
+	case TagTypeLongArray:
+		n, err := d.readLength(8)
+		if err != nil {
+			return nil, err
+		}
+		data := make(TagLongArray, n)
+		err = binary.Read(d.r, binary.BigEndian, data)
+		return data, err
+	case TagTypeList:
+		return d.readList(depth + 1)
+	case TagTypeCompound:
+		return d.readCompound(depth + 1)
+	}
+	return nil, fmt.Errorf("nbt: unknown tag type %d", typ)
+}
+
+func (d *Decoder) readList(depth int) (Tag, error) {
+	if depth > d.MaxDepth {
+		return nil, ErrTooDeep
+	}
+	elemType, err := d.readType()
+	if err != nil {
+		return nil, err
+	}
+	// every element takes at least one byte, so length is limited by MaxSize before allocation
+	n, err := d.readLength(1)
+	if err != nil {
+		return nil, err
+	}
+	if elemType == TagTypeEnd && n > 0 {
+		return nil, errors.New("nbt: list of TAG_End isn't empty")
+	}
+	// Tag takes 16 bytes, so bigger lists grow with elements actually read
+	capacity := n
+	if capacity > 1024 {
+		capacity = 1024
+	}
+	list := TagList{ElementType: elemType, Elements: make([]Tag, 0, capacity)}
+	for i := 0; i < n; i++ {
+		elem, err := d.readPayload(elemType, depth)
+		if err != nil {
+			return nil, err
+		}
+		list.Elements = append(list.Elements, elem)
+	}
+	return list, nil
+}
+
+func (d *Decoder) readCompound(depth int) (Tag, error) {
+	if depth > d.MaxDepth {
+		return nil, ErrTooDeep
+	}
+	compound := make(TagCompound)
+	for {
+		typ, err := d.readType()
+		if err != nil {
+			return nil, err
+		}
+		if typ == TagTypeEnd {
+			return compound, nil
+		}
+		name, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		if compound[name], err = d.readPayload(typ, depth); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// counts n bytes against MaxSize
+func (d *Decoder) account(n int64) error {
+	d.read += n
+	if d.read > d.MaxSize {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+// returned slice is valid until next read
+func (d *Decoder) readN(n int) ([]byte, error) {
+	if err := d.account(int64(n)); err != nil {
+		return nil, err
+	}
+	b := d.buf[:n]
+	_, err := io.ReadFull(d.r, b)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return b, err
+}
+
+func (d *Decoder) readType() (TagType, error) {
+	b, err := d.readN(1)
+	if err != nil {
+		return 0, err
+	}
+	return TagType(b[0]), nil
+}
+
+func (d *Decoder) readInt() (int32, error) {
+	b, err := d.readN(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(b)), nil
+}
+
+// reads int32 length of array with elements of given size and accounts them
+func (d *Decoder) readLength(size int64) (int, error) {
+	n, err := d.readInt()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("nbt: negative length %d", n)
+	}
+	return int(n), d.account(int64(n) * size)
+}
+
+func (d *Decoder) readString() (string, error) {
+	b, err := d.readN(2)
+	if err != nil {
+		return "", err
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if err = d.account(int64(n)); err != nil {
+		return "", err
+	}
+	data := make([]byte, n)
+	if _, err = io.ReadFull(d.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return decodeMUTF8(data)
+}
+
+// Java modified UTF-8: NUL is encoded as C0 80 and characters outside BMP as two 3-byte surrogates
+func decodeMUTF8(data []byte) (string, error) {
+	ascii := true
+	for _, c := range data {
+		if c == 0 || c >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return string(data), nil
+	}
+
+	units := make([]uint16, 0, len(data))
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c != 0 && c < 0x80:
+			units = append(units, uint16(c))
+			i++
+		case c&0xE0 == 0xC0 && i+1 < len(data) && data[i+1]&0xC0 == 0x80:
+			units = append(units, uint16(c&0x1F)<<6|uint16(data[i+1]&0x3F))
+			i += 2
+		case c&0xF0 == 0xE0 && i+2 < len(data) && data[i+1]&0xC0 == 0x80 && data[i+2]&0xC0 == 0x80:
+			units = append(units, uint16(c&0x0F)<<12|uint16(data[i+1]&0x3F)<<6|uint16(data[i+2]&0x3F))
+			i += 3
+		default:
+			return "", fmt.Errorf("nbt: invalid modified UTF-8 at byte %d", i)
+		}
+	}
+	// unpaired surrogates become U+FFFD
+	return string(utf16.Decode(units)), nil
+}
@@ -0,0 +1,177 @@
+package nbt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"unicode/utf16"
+)
+
+type Encoder struct {
+	w   io.Writer
+	buf [8]byte
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes named root compound, nil root is written as TAG_End. Keys of compounds are sorted.
+func (e *Encoder) Encode(root TagCompound, name string) error {
+	if root == nil {
+		return e.writeType(TagTypeEnd)
+	}
+	if err := e.writeType(TagTypeCompound); err != nil {
+		return err
+	}
+	if err := e.writeString(name); err != nil {
+		return err
+	}
+	return e.writePayload(root)
+}
+
+func (e *Encoder) writePayload(tag Tag) error {
+	switch t := tag.(type) {
+	case TagByte:
+		e.buf[0] = byte(t)
+		return e.write(1)
+	case TagShort:
+		binary.BigEndian.PutUint16(e.buf[:], uint16(t))
+		return e.write(2)
+	case TagInt:
+		return e.writeInt(int32(t))
+	case TagLong:
+		binary.BigEndian.PutUint64(e.buf[:], uint64(t))
+		return e.write(8)
+	case TagFloat:
+		binary.BigEndian.PutUint32(e.buf[:], math.Float32bits(float32(t)))
+		return e.write(4)
+	case TagDouble:
+		binary.BigEndian.PutUint64(e.buf[:], math.Float64bits(float64(t)))
+		return e.write(8)
+	case TagString:
+		return e.writeString(string(t))
+	case TagByteArray:
+		if err := e.writeLength(len(t)); err != nil {
+			return err
+		}
+		_, err := e.w.Write(t)
+		return err
+	case TagIntArray:
+		if err := e.writeLength(len(t)); err != nil {
+			return err
+		}
+		return binary.Write(e.w, binary.BigEndian, []int32(t))
+	case TagLongArray:
+		if err := e.writeLength(len(t)); err != nil {
+			return err
+		}
+		return binary.Write(e.w, binary.BigEndian, []int64(t))
+	case TagList:
+		if t.ElementType == TagTypeEnd && len(t.Elements) > 0 {
+			return errors.New("nbt: list of TAG_End isn't empty")
+		}
+		if err := e.writeType(t.ElementType); err != nil {
+			return err
+		}
+		if err := e.writeLength(len(t.Elements)); err != nil {
+			return err
+		}
+		for _, elem := range t.Elements {
+			if elem == nil || elem.Type() != t.ElementType {
+				return fmt.Errorf("nbt: %T in list of %s", elem, t.ElementType)
+			}
+			if err := e.writePayload(elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case TagCompound:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if t[k] == nil {
+				return fmt.Errorf("nbt: nil tag %q", k)
+			}
+			if err := e.writeType(t[k].Type()); err != nil {
+				return err
+			}
+			if err := e.writeString(k); err != nil {
+				return err
+			}
+			if err := e.writePayload(t[k]); err != nil {
+				return err
+			}
+		}
+		return e.writeType(TagTypeEnd)
+	}
+	return fmt.Errorf("nbt: unknown tag %T", tag)
+}
+
+func (e *Encoder) write(n int) error {
+	_, err := e.w.Write(e.buf[:n])
+	return err
+}
+
+func (e *Encoder) writeType(typ TagType) error {
+	e.buf[0] = byte(typ)
+	return e.write(1)
+}
+
+func (e *Encoder) writeInt(v int32) error {
+	binary.BigEndian.PutUint32(e.buf[:], uint32(v))
+	return e.write(4)
+}
+
+func (e *Encoder) writeLength(n int) error {
+	if n > math.MaxInt32 {
+		return errors.New("nbt: array too long")
+	}
+	return e.writeInt(int32(n))
+}
+
+func (e *Encoder) writeString(s string) error {
+	data := encodeMUTF8(s)
+	if len(data) > math.MaxUint16 {
+		return fmt.Errorf("nbt: string too long (%d bytes)", len(data))
+	}
+	binary.BigEndian.PutUint16(e.buf[:], uint16(len(data)))
+	if err := e.write(2); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, data)
+	return err
+}
+
+// see decodeMUTF8
+func encodeMUTF8(s string) string {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0 || s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s
+	}
+
+	data := make([]byte, 0, len(s)+len(s)/2)
+	for _, unit := range utf16.Encode([]rune(s)) {
+		switch {
+		case unit != 0 && unit < 0x80:
+			data = append(data, byte(unit))
+		case unit < 0x800:
+			data = append(data, 0xC0|byte(unit>>6), 0x80|byte(unit&0x3F))
+		default:
+			data = append(data, 0xE0|byte(unit>>12), 0x80|byte(unit>>6&0x3F), 0x80|byte(unit&0x3F))
+		}
+	}
+	return string(data)
+}
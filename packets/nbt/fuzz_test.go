@@ -0,0 +1,42 @@
+package nbt
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+)
+
+// Decoding is limited by MaxSize, memory allocated for single input must be too.
+func FuzzDecode(f *testing.F) {
+	var buf bytes.Buffer
+	NewEncoder(&buf).Encode(testCompound, "root")
+	f.Add(buf.Bytes())
+	f.Add([]byte{10, 0, 0, 9, 0, 0, 10, 0x7F, 0xFF, 0xFF, 0xFF})
+	f.Add([]byte{10, 0, 0, 9, 0, 0, 1, 0x00, 0x0F, 0xFF, 0xFF})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		root, name, err := NewDecoder(bytes.NewReader(data)).Decode()
+		runtime.ReadMemStats(&after)
+		if n := after.TotalAlloc - before.TotalAlloc; n > 4*DefaultMaxSize {
+			t.Fatalf("allocated %d bytes", n)
+		}
+		if err != nil {
+			return
+		}
+
+		// decoded NBT is encoded back to the same tags
+		var first, second bytes.Buffer
+		if err := NewEncoder(&first).Encode(root, name); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		again, _, err := NewDecoder(bytes.NewReader(first.Bytes())).Decode()
+		if err != nil {
+			t.Fatalf("decode of encoded: %v", err)
+		}
+		NewEncoder(&second).Encode(again, name)
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Fatalf("round trip differs\ngot:  %x\nwant: %x", second.Bytes(), first.Bytes())
+		}
+	})
+}
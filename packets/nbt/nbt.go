@@ -0,0 +1,231 @@
+// Package nbt reads and writes Named Binary Tag data in network format (uncompressed, big endian)
+// and converts it from and to SNBT, text format used by commands.
+package nbt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+type TagType byte
+
+const (
+	TagTypeEnd TagType = iota
+	TagTypeByte
+	TagTypeShort
+	TagTypeInt
+	TagTypeLong
+	TagTypeFloat
+	TagTypeDouble
+	TagTypeByteArray
+	TagTypeString
+	TagTypeList
+	TagTypeCompound
+	TagTypeIntArray
+	TagTypeLongArray
+)
+
+var tagTypeNames = [...]string{"TAG_End", "TAG_Byte", "TAG_Short", "TAG_Int", "TAG_Long", "TAG_Float", "TAG_Double",
+	"TAG_Byte_Array", "TAG_String", "TAG_List", "TAG_Compound", "TAG_Int_Array", "TAG_Long_Array"}
+
+func (t TagType) String() string {
+	if int(t) < len(tagTypeNames) {
+		return tagTypeNames[t]
+	}
+	return fmt.Sprintf("TAG_Unknown(%d)", byte(t))
+}
+
+// Tag is one of Tag* types below, String returns SNBT
+type Tag interface {
+	Type() TagType
+	String() string
+}
+
+type TagByte int8
+type TagShort int16
+type TagInt int32
+type TagLong int64
+type TagFloat float32
+type TagDouble float64
+type TagByteArray []byte
+type TagString string
+type TagIntArray []int32
+type TagLongArray []int64
+
+// ElementType is kept, so empty lists are written back unchanged
+type TagList struct {
+	ElementType TagType
+	Elements    []Tag
+}
+
+type TagCompound map[string]Tag
+
+func (TagByte) Type() TagType      { return TagTypeByte }
+func (TagShort) Type() TagType     { return TagTypeShort }
+func (TagInt) Type() TagType       { return TagTypeInt }
+func (TagLong) Type() TagType      { return TagTypeLong }
+func (TagFloat) Type() TagType     { return TagTypeFloat }
+func (TagDouble) Type() TagType    { return TagTypeDouble }
+func (TagByteArray) Type() TagType { return TagTypeByteArray }
+func (TagString) Type() TagType    { return TagTypeString }
+func (TagList) Type() TagType      { return TagTypeList }
+func (TagCompound) Type() TagType  { return TagTypeCompound }
+func (TagIntArray) Type() TagType  { return TagTypeIntArray }
+func (TagLongArray) Type() TagType { return TagTypeLongArray }
+
+func (t TagByte) String() string      { return Stringify(t) }
+func (t TagShort) String() string     { return Stringify(t) }
+func (t TagInt) String() string       { return Stringify(t) }
+func (t TagLong) String() string      { return Stringify(t) }
+func (t TagFloat) String() string     { return Stringify(t) }
+func (t TagDouble) String() string    { return Stringify(t) }
+func (t TagByteArray) String() string { return Stringify(t) }
+func (t TagString) String() string    { return Stringify(t) }
+func (t TagList) String() string      { return Stringify(t) }
+func (t TagCompound) String() string  { return Stringify(t) }
+func (t TagIntArray) String() string  { return Stringify(t) }
+func (t TagLongArray) String() string { return Stringify(t) }
+
+// %#v prints SNBT too, so dumps of packets show readable NBT
+func (t TagCompound) GoString() string { return Stringify(t) }
+func (t TagList) GoString() string     { return Stringify(t) }
+
+// > SNBT printing
+
+// Stringify returns SNBT of tag, keys of compounds are sorted
+func Stringify(tag Tag) string {
+	var b bytes.Buffer
+	writeSNBT(&b, tag)
+	return b.String()
+}
+
+func writeSNBT(b *bytes.Buffer, tag Tag) {
+	switch t := tag.(type) {
+	case TagByte:
+		b.WriteString(strconv.Itoa(int(t)) + "b")
+	case TagShort:
+		b.WriteString(strconv.Itoa(int(t)) + "s")
+	case TagInt:
+		b.WriteString(strconv.Itoa(int(t)))
+	case TagLong:
+		b.WriteString(strconv.FormatInt(int64(t), 10) + "L")
+	case TagFloat:
+		b.WriteString(strconv.FormatFloat(float64(t), 'g', -1, 32) + "f")
+	case TagDouble:
+		b.WriteString(strconv.FormatFloat(float64(t), 'g', -1, 64) + "d")
+	case TagString:
+		writeQuoted(b, string(t))
+	case TagByteArray:
+		b.WriteString("[B;")
+		for i, v := range t {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(int(int8(v))) + "b")
+		}
+		b.WriteByte(']')
+	case TagIntArray:
+		b.WriteString("[I;")
+		for i, v := range t {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(int(v)))
+		}
+		b.WriteByte(']')
+	case TagLongArray:
+		b.WriteString("[L;")
+		for i, v := range t {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.FormatInt(v, 10) + "L")
+		}
+		b.WriteByte(']')
+	case TagList:
+		b.WriteByte('[')
+		for i, v := range t.Elements {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeSNBT(b, v)
+		}
+		b.WriteByte(']')
+	case TagCompound:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if isUnquoted(k) {
+				b.WriteString(k)
+			} else {
+				writeQuoted(b, k)
+			}
+			b.WriteByte(':')
+			writeSNBT(b, t[k])
+		}
+		b.WriteByte('}')
+	default:
+		fmt.Fprintf(b, "<invalid %T>", tag)
+	}
+}
+
+func writeQuoted(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+}
+
+// characters of strings and keys which don't need quotes
+func isUnquotedChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+		c == '_' || c == '-' || c == '.' || c == '+'
+}
+
+func isUnquoted(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isUnquotedChar(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// > JSON: compounds are SNBT strings, nil compound is null
+
+func (t TagCompound) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(Stringify(t))
+}
+
+func (t *TagCompound) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = nil
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	compound, err := ParseCompound(s)
+	if err == nil {
+		*t = compound
+	}
+	return err
+}
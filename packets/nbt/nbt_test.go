@@ -0,0 +1,144 @@
+package nbt
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+var testCompound = TagCompound{
+	"byte":   TagByte(-1),
+	"short":  TagShort(300),
+	"int":    TagInt(-70000),
+	"long":   TagLong(1 << 40),
+	"float":  TagFloat(0.5),
+	"double": TagDouble(-2.25),
+	"bytes":  TagByteArray{1, 0xFF},
+	"ints":   TagIntArray{1, -2},
+	"longs":  TagLongArray{1 << 33},
+	"string": TagString("zażółć \x00 😀"),
+	"list":   TagList{ElementType: TagTypeString, Elements: []Tag{TagString("a"), TagString("b")}},
+	"empty":  TagList{ElementType: TagTypeEnd, Elements: []Tag{}},
+	"nested": TagCompound{"x y": TagList{ElementType: TagTypeCompound, Elements: []Tag{TagCompound{}}}},
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(testCompound, "root"); err != nil {
+		t.Fatal(err)
+	}
+	root, name, err := NewDecoder(&buf).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "root" || !reflect.DeepEqual(root, testCompound) {
+		t.Fatalf("decoded %q %s", name, root)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("%d bytes left", buf.Len())
+	}
+}
+
+func TestBinaryFormat(t *testing.T) {
+	var buf bytes.Buffer
+	NewEncoder(&buf).Encode(TagCompound{"a": TagString("\x00")}, "")
+	want := []byte{10, 0, 0, 8, 0, 1, 'a', 0, 2, 0xC0, 0x80, 0}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("got %x, want %x", buf.Bytes(), want)
+	}
+
+	buf.Reset()
+	NewEncoder(&buf).Encode(nil, "")
+	if root, _, err := NewDecoder(&buf).Decode(); err != nil || root != nil || !bytes.Equal(buf.Bytes(), []byte{}) {
+		t.Fatalf("TAG_End root: %v %v", root, err)
+	}
+}
+
+func TestDecodeLimits(t *testing.T) {
+	deep := []byte{10, 0, 0, 9, 0, 0} // compound with list named ""
+	for i := 0; i < DefaultMaxDepth+1; i++ {
+		deep = append(deep, 9, 0, 0, 0, 1) // list of one list
+	}
+	if _, _, err := NewDecoder(bytes.NewReader(deep)).Decode(); err != ErrTooDeep {
+		t.Fatalf("deep nesting: %v", err)
+	}
+
+	huge := []byte{10, 0, 0, 9, 0, 0, 10, 0x7F, 0xFF, 0xFF, 0xFF}
+	if _, _, err := NewDecoder(bytes.NewReader(huge)).Decode(); err != ErrTooLarge {
+		t.Fatalf("huge list: %v", err)
+	}
+
+	d := NewDecoder(bytes.NewReader([]byte{10, 0, 0, 7, 0, 0, 0, 0, 0, 100}))
+	d.MaxSize = 64
+	if _, _, err := d.Decode(); err != ErrTooLarge {
+		t.Fatalf("byte array over MaxSize: %v", err)
+	}
+
+	// 7 bytes: compound with tag named "", its payload is over MaxSize
+	for _, typ := range []TagType{TagTypeByte, TagTypeShort, TagTypeInt, TagTypeLong, TagTypeFloat, TagTypeDouble, TagTypeString} {
+		d := NewDecoder(bytes.NewReader([]byte{10, 0, 0, byte(typ), 0, 0, 1}))
+		d.MaxSize = 6
+		if _, _, err := d.Decode(); err != ErrTooLarge {
+			t.Fatalf("%s over MaxSize: %v", typ, err)
+		}
+	}
+}
+
+// list length fits in MaxSize, but elements aren't there, so memory isn't allocated for them
+func TestDecodeClaimedListLength(t *testing.T) {
+	n := DefaultMaxSize - 64
+	short := []byte{10, 0, 0, 9, 0, 0, 1, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n), 1, 2, 3}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, _, err := NewDecoder(bytes.NewReader(short)).Decode()
+	runtime.ReadMemStats(&after)
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("truncated list: %v", err)
+	}
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 1024*1024 {
+		t.Fatalf("allocated %d bytes for %d bytes of data", alloc, len(short))
+	}
+}
+
+func TestSNBT(t *testing.T) {
+	s := Stringify(testCompound)
+	want := `{byte:-1b,bytes:[B;1b,-1b],double:-2.25d,empty:[],float:0.5f,int:-70000,ints:[I;1,-2],` +
+		`list:["a","b"],long:1099511627776L,longs:[L;8589934592L],nested:{"x y":[{}]},short:300s,string:"zażółć ` + "\x00" + ` 😀"}`
+	if s != want {
+		t.Fatalf("Stringify\ngot:  %s\nwant: %s", s, want)
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// empty list has no element type in SNBT
+	if !reflect.DeepEqual(parsed.(TagCompound)["nested"], testCompound["nested"]) || parsed.String() != s {
+		t.Fatalf("parsed %s", parsed)
+	}
+
+	item, err := ParseCompound(`{display: {Name: '{"text":"Miecz"}', Lore: ["\"a\""]}, Damage: 3, Unbreakable: true, x: 1.5, y: abc}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantItem := TagCompound{
+		"display": TagCompound{
+			"Name": TagString(`{"text":"Miecz"}`),
+			"Lore": TagList{ElementType: TagTypeString, Elements: []Tag{TagString(`"a"`)}},
+		},
+		"Damage":      TagInt(3),
+		"Unbreakable": TagByte(1),
+		"x":           TagDouble(1.5),
+		"y":           TagString("abc"),
+	}
+	if !reflect.DeepEqual(item, wantItem) {
+		t.Fatalf("parsed %s, want %s", item, wantItem)
+	}
+
+	for _, invalid := range []string{`{a:1`, `[1,2b]`, `{a:1}x`, `"abc`, `[B;1,2]`, `{:1}`} {
+		if _, err := Parse(invalid); err == nil {
+			t.Fatalf("%s parsed", invalid)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package nbt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// > SNBT parsing, syntax of vanilla commands, e.g. {display:{Name:'{"text":"Miecz"}'},Damage:3}
+
+// Parse parses single SNBT value
+func Parse(s string) (Tag, error) {
+	p := &snbtParser{s: s}
+	tag, err := p.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos != len(p.s) {
+		return nil, p.errorf("trailing data")
+	}
+	return tag, nil
+}
+
+// ParseCompound parses SNBT of compound, e.g. item tag
+func ParseCompound(s string) (TagCompound, error) {
+	tag, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	compound, ok := tag.(TagCompound)
+	if !ok {
+		return nil, fmt.Errorf("nbt: %s is not compound", tag.Type())
+	}
+	return compound, nil
+}
+
+type snbtParser struct {
+	s   string
+	pos int
+}
+
+func (p *snbtParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("nbt: "+format+" at position %d of SNBT", append(args, p.pos)...)
+}
+
+func (p *snbtParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// next non-space character, 0 at end
+func (p *snbtParser) peek() byte {
+	p.skipSpace()
+	if p.pos == len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *snbtParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected '%c'", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *snbtParser) value(depth int) (Tag, error) {
+	if depth > DefaultMaxDepth {
+		return nil, ErrTooDeep
+	}
+	switch c := p.peek(); c {
+	case '{':
+		return p.compound(depth + 1)
+	case '[':
+		return p.list(depth + 1)
+	case '"', '\'':
+		s, err := p.quoted(c)
+		return TagString(s), err
+	}
+	token := p.unquoted()
+	if token == "" {
+		return nil, p.errorf("expected value")
+	}
+	return scalar(token), nil
+}
+
+func (p *snbtParser) compound(depth int) (Tag, error) {
+	p.pos++ // {
+	compound := make(TagCompound)
+	if p.peek() == '}' {
+		p.pos++
+		return compound, nil
+	}
+	for {
+		var key string
+		var err error
+		if c := p.peek(); c == '"' || c == '\'' {
+			key, err = p.quoted(c)
+		} else if key = p.unquoted(); key == "" {
+			err = p.errorf("expected key")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(':'); err != nil {
+			return nil, err
+		}
+		if compound[key], err = p.value(depth); err != nil {
+			return nil, err
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return compound, nil
+		default:
+			return nil, p.errorf("expected ',' or '}'")
+		}
+	}
+}
+
+// list [a,b] or array [B;1b,2b], [I;1,2], [L;1L,2L]
+func (p *snbtParser) list(depth int) (Tag, error) {
+	p.pos++ // [
+	p.skipSpace()
+	var arrayType byte
+	if p.pos+1 < len(p.s) && p.s[p.pos+1] == ';' && strings.IndexByte("BIL", p.s[p.pos]) >= 0 {
+		arrayType = p.s[p.pos]
+		p.pos += 2
+	}
+
+	var elems []Tag
+	if p.peek() == ']' {
+		p.pos++
+	} else {
+		for {
+			elem, err := p.value(depth)
+			if err != nil {
+				return nil, err
+			}
+			if len(elems) > 0 && elem.Type() != elems[0].Type() {
+				return nil, p.errorf("%s in list of %s", elem.Type(), elems[0].Type())
+			}
+			elems = append(elems, elem)
+			if p.peek() == ',' {
+				p.pos++
+				continue
+			}
+			if err = p.expect(']'); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	switch arrayType {
+	case 'B':
+		array := make(TagByteArray, len(elems))
+		for i, elem := range elems {
+			v, ok := elem.(TagByte)
+			if !ok {
+				return nil, p.errorf("%s in byte array", elem.Type())
+			}
+			array[i] = byte(v)
+		}
+		return array, nil
+	case 'I':
+		array := make(TagIntArray, len(elems))
+		for i, elem := range elems {
+			v, ok := elem.(TagInt)
+			if !ok {
+				return nil, p.errorf("%s in int array", elem.Type())
+			}
+			array[i] = int32(v)
+		}
+		return array, nil
+	case 'L':
+		array := make(TagLongArray, len(elems))
+		for i, elem := range elems {
+			v, ok := elem.(TagLong)
+			if !ok {
+				return nil, p.errorf("%s in long array", elem.Type())
+			}
+			array[i] = int64(v)
+		}
+		return array, nil
+	}
+	list := TagList{ElementType: TagTypeEnd, Elements: elems}
+	if len(elems) > 0 {
+		list.ElementType = elems[0].Type()
+	}
+	return list, nil
+}
+
+func (p *snbtParser) quoted(quote byte) (string, error) {
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.pos == len(p.s) || (p.s[p.pos] != '\\' && p.s[p.pos] != quote) {
+				return "", p.errorf("invalid escape")
+			}
+			c = p.s[p.pos]
+			p.pos++
+		}
+		b.WriteByte(c)
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *snbtParser) unquoted() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && isUnquotedChar(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// unquoted value is number with optional type suffix, true/false (byte) or string
+func scalar(token string) Tag {
+	switch token {
+	case "true":
+		return TagByte(1)
+	case "false":
+		return TagByte(0)
+	}
+	if !isNumber(token) {
+		return TagString(token)
+	}
+	number := token[:len(token)-1]
+	switch token[len(token)-1] {
+	case 'b', 'B':
+		if v, err := strconv.ParseInt(number, 10, 8); err == nil {
+			return TagByte(v)
+		}
+	case 's', 'S':
+		if v, err := strconv.ParseInt(number, 10, 16); err == nil {
+			return TagShort(v)
+		}
+	case 'l', 'L':
+		if v, err := strconv.ParseInt(number, 10, 64); err == nil {
+			return TagLong(v)
+		}
+	case 'f', 'F':
+		if v, err := strconv.ParseFloat(number, 32); err == nil {
+			return TagFloat(v)
+		}
+	case 'd', 'D':
+		if v, err := strconv.ParseFloat(number, 64); err == nil {
+			return TagDouble(v)
+		}
+	default:
+		if v, err := strconv.ParseInt(token, 10, 32); err == nil {
+			return TagInt(v)
+		}
+		// numbers without suffix are double only with decimal point, like in vanilla
+		if strings.IndexByte(token, '.') >= 0 {
+			if v, err := strconv.ParseFloat(token, 64); err == nil {
+				return TagDouble(v)
+			}
+		}
+	}
+	return TagString(token)
+}
+
+// digits, sign, decimal point and exponent with optional type suffix
+func isNumber(token string) bool {
+	if strings.IndexByte("bBsSlLfFdD", token[len(token)-1]) >= 0 {
+		token = token[:len(token)-1]
+	}
+	digits := false
+	for i := 0; i < len(token); i++ {
+		c := token[i]
+		if c >= '0' && c <= '9' {
+			digits = true
+		} else if strings.IndexByte("+-.eE", c) < 0 {
+			return false
+		}
+	}
+	return digits
+}
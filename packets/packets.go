@@ -5,15 +5,24 @@ import (
 	"io"
 )
 
+//go:generate go run ./internal/packetgen -spec play.spec
+
 const ProtocolVersion = 753
-const MaxPacketSize = 128 * 1024
+const MaxPacketSize = 2097151         // 3 byte VarInt, protocol limit
+const BufferedPacketSize = 128 * 1024 // bigger packets are streamed, see PacketReader.ReadPacket()
+const StreamHeadSize = 4 * 1024       // part of streamed packet read to find its id
 const MaxPacketID = 256
 const CompressThreshold = 512
 
+// Compression threshold of connections without compression. Like in vanilla, negative thresholds disable
+// compression and 0 means compressing all packets.
+const NoCompression = -1
+
 var GameVersion = ServerStatusVersion{"1.16.3", ProtocolVersion}
 
 type EntityID int32
 type VarInt int32
+type VarLong int64
 type Identifier string
 
 const IdentifierMaxLength = 32767
@@ -30,7 +39,7 @@ type Marshaler interface {
 }
 
 type Unmarshaler interface {
-	UnmarshalPacket(w io.Writer) error
+	UnmarshalPacket(r io.Reader) error
 }
 
 func NewPacket(packetId VarInt, state ConnState, direction Direction) (packet Packet) {
@@ -95,60 +104,10 @@ func ToString(packet Packet, direction Direction) string {
 		} else {
 			s = fmt.Sprintf("RAW TOO_LARGE %d", len(raw.Payload))
 		}
-	} else { // not raw
-		s = fmt.Sprintf("%#v", packet)
-		if len(s) > 256 {
-			s = fmt.Sprintf("%T", packet)
-		}
-	}
-	return fmt.Sprintf("%s_%02X %s", direction, packet.PacketID(), s)
-}
-
-func newPlayPacket(packetId VarInt, direction Direction) (packet Packet) {
-	if direction == ServerBound {
-		switch packetId {
-		case 0x03:
-			packet = new(ChatMessagePacketSB)
-		case 0x04:
-			packet = new(ClientStatusPacketSB)
-		case 0x05:
-			packet = new(ClientSettingsPacketSB)
-		case 0x06:
-			packet = new(TabCompletePacketSB)
-		case 0x0B:
-			packet = new(PluginMessagePacketSB)
-		}
+	} else if fields, err := MarshalFieldsJSON(packet); err == nil {
+		s = fmt.Sprintf("%T %s", packet, fields)
 	} else {
-		switch packetId {
-		case 0x39:
-			packet = new(RespawnPacketCB)
-		case 0x19:
-			packet = new(KickPacketCB)
-		case 0x24:
-			packet = new(JoinGamePacketCB)
-		case 0x17:
-			packet = new(PluginMessagePacketCB)
-		case 0x1D:
-			packet = new(GameStateChangePacketCB)
-		case 0x0E:
-			packet = new(ChatMessagePacketCB)
-		case 0x0F:
-			packet = new(TabCompletePacketCB)
-		case 0x32:
-			packet = new(PlayerListItemPacketCB)
-		case 0x4A:
-			packet = new(ScoreboardObjectivePacketCB)
-		case 0x4C:
-			packet = new(TeamsPacketCB)
-		}
+		s = fmt.Sprintf("%T JSON_ERROR %s", packet, err)
 	}
-	return
+	return fmt.Sprintf("%s_%02X %s", direction, packet.PacketID(), s)
 }
-
-type Position uint64
-
-// struct {
-// 	X int32
-// 	Y int32
-// 	Z int32
-// }
@@ -0,0 +1,677 @@
+# Play packets of Minecraft 1.16.3 (protocol 753), source of play_gen.go and newPlayPacket.
+# Regenerate with: go generate ./packets
+#
+# Packet:    cb|sb <id> <TypeName> [custom]
+# Struct:    struct <TypeName>
+# followed by fields in Go syntax, indented with tab, see tags in ioutils.go.
+# Custom packets are written by hand in play_*.go, only registered here.
+# Json chat is string with max_length 262144.
+
+# > clientbound
+
+cb 0x00 SpawnEntityPacketCB
+	EntityID                        EntityID `datatype:"VarInt"`
+	ObjectUUID                      uuid.UUID
+	Type                            VarInt
+	Position                        Vec3d
+	Pitch, Yaw                      Angle
+	Data                            int32
+	VelocityX, VelocityY, VelocityZ int16
+
+cb 0x01 SpawnExperienceOrbPacketCB
+	EntityID EntityID `datatype:"VarInt"`
+	Position Vec3d
+	Count    int16
+
+cb 0x02 SpawnLivingEntityPacketCB
+	EntityID                        EntityID `datatype:"VarInt"`
+	EntityUUID                      uuid.UUID
+	Type                            VarInt
+	Position                        Vec3d
+	Yaw, Pitch, HeadPitch           Angle
+	VelocityX, VelocityY, VelocityZ int16
+
+cb 0x03 SpawnPaintingPacketCB
+	EntityID   EntityID `datatype:"VarInt"`
+	EntityUUID uuid.UUID
+	Motive     VarInt
+	Location   BlockPosition
+	Facing     int8
+
+cb 0x04 SpawnPlayer custom
+
+cb 0x05 EntityAnimationPacketCB
+	EntityID  EntityID `datatype:"VarInt"`
+	Animation uint8
+
+struct Statistic
+	CategoryID  VarInt
+	StatisticID VarInt
+	Value       VarInt
+
+cb 0x06 StatisticsPacketCB
+	Statistics []Statistic `length_prefix:"VarInt" max_count:"65536"`
+
+cb 0x07 AcknowledgePlayerDiggingPacketCB
+	Location   BlockPosition
+	Block      VarInt
+	Status     VarInt
+	Successful bool
+
+cb 0x08 BlockBreakAnimationPacketCB
+	EntityID     EntityID `datatype:"VarInt"`
+	Location     BlockPosition
+	DestroyStage int8
+
+cb 0x09 BlockEntityDataPacketCB
+	Location BlockPosition
+	Action   uint8
+	Data     nbt.TagCompound
+
+cb 0x0A BlockActionPacketCB
+	Location    BlockPosition
+	ActionID    uint8
+	ActionParam uint8
+	BlockType   VarInt
+
+cb 0x0B BlockChangePacketCB
+	Location BlockPosition
+	BlockID  VarInt
+
+cb 0x0C BossBarPacketCB
+	UUID     uuid.UUID
+	Action   VarInt  // 0 add, 1 remove, 2 health, 3 title, 4 style, 5 flags
+	Title    string  `max_length:"262144" when:"Action==0,3" format:"chat"`
+	Health   float32 `when:"Action==0,2"`
+	Color    VarInt  `when:"Action==0,4"`
+	Division VarInt  `when:"Action==0,4"`
+	Flags    uint8   `when:"Action==0,5"`
+
+cb 0x0D ServerDifficultyPacketCB
+	Difficulty uint8
+	Locked     bool
+
+cb 0x0E ChatMessagePacketCB custom
+cb 0x0F TabCompletePacketCB custom
+
+cb 0x10 DeclareCommandsPacketCB custom
+
+cb 0x11 WindowConfirmationPacketCB
+	WindowID     int8
+	ActionNumber int16
+	Accepted     bool
+
+cb 0x12 CloseWindowPacketCB
+	WindowID uint8
+
+cb 0x13 WindowItemsPacketCB
+	WindowID uint8
+	Slots    []Slot `length_prefix:"int16" max_count:"1024"`
+
+cb 0x14 WindowPropertyPacketCB
+	WindowID uint8
+	Property int16
+	Value    int16
+
+cb 0x15 SetSlotPacketCB
+	WindowID int8
+	Slot     int16
+	Item     Slot
+
+cb 0x16 SetCooldownPacketCB
+	ItemID        VarInt
+	CooldownTicks VarInt
+
+cb 0x17 PluginMessagePacketCB custom
+
+cb 0x18 NamedSoundEffectPacketCB
+	SoundName     Identifier
+	Category      VarInt
+	X, Y, Z       int32 // fixed-point, 1/8 of block, see SoundPos
+	Volume, Pitch float32
+
+cb 0x19 KickPacketCB custom
+
+cb 0x1A EntityStatusPacketCB
+	EntityID EntityID `datatype:"int32"`
+	Status   int8
+
+struct ExplosionRecord
+	X, Y, Z int8
+
+cb 0x1B ExplosionPacketCB
+	X, Y, Z                                     float32
+	Strength                                    float32
+	Records                                     []ExplosionRecord `length_prefix:"int32" max_count:"65536"`
+	PlayerMotionX, PlayerMotionY, PlayerMotionZ float32
+
+cb 0x1C UnloadChunkPacketCB
+	ChunkX, ChunkZ int32
+
+cb 0x1D GameStateChangePacketCB custom
+
+cb 0x1E OpenHorseWindowPacketCB
+	WindowID  int8
+	SlotCount VarInt
+	EntityID  EntityID `datatype:"int32"`
+
+cb 0x1F KeepAlivePacketCB
+	ID int64
+
+cb 0x20 ChunkDataPacketCB
+	ChunkX, ChunkZ int32
+	FullChunk      bool
+	PrimaryBitMask VarInt
+	Heightmaps     nbt.TagCompound
+	Biomes         []VarInt          `length_prefix:"VarInt" max_count:"1024" when:"FullChunk==1"`
+	Data           []byte            `max_length:"2097151" length_prefix:"VarInt"`
+	BlockEntities  []nbt.TagCompound `length_prefix:"VarInt" max_count:"65536"`
+
+cb 0x21 EffectPacketCB
+	EffectID              int32
+	Location              BlockPosition
+	Data                  int32
+	DisableRelativeVolume bool
+
+cb 0x22 ParticlePacketCB
+	ID                        int32 // particle, selects fields of Data
+	LongDistance              bool
+	Position                  Vec3d
+	OffsetX, OffsetY, OffsetZ float32
+	Speed                     float32
+	ParticleCount             int32
+	Data                      ParticleData
+
+cb 0x23 UpdateLightPacketCB custom
+
+cb 0x24 JoinGamePacketCB custom
+
+struct MapIcon
+	Type        VarInt
+	X, Z        int8
+	Direction   int8
+	DisplayName string `max_length:"262144" optional:"bool" format:"chat"`
+
+cb 0x25 MapDataPacketCB
+	MapID            VarInt
+	Scale            int8
+	TrackingPosition bool
+	Locked           bool
+	Icons            []MapIcon `length_prefix:"VarInt" max_count:"65536"`
+	Columns          uint8
+	Rows             uint8  `when:"Columns!=0"`
+	X, Z             int8   `when:"Columns!=0"`
+	Data             []byte `max_length:"16384" length_prefix:"VarInt" when:"Columns!=0"`
+
+struct Trade
+	InputItem1      Slot
+	OutputItem      Slot
+	HasSecondItem   bool
+	InputItem2      Slot `when:"HasSecondItem==1"`
+	Disabled        bool
+	Uses            int32
+	MaxUses         int32
+	XP              int32
+	SpecialPrice    int32
+	PriceMultiplier float32
+	Demand          int32
+
+# trade count is unsigned byte, which isn't array length_prefix
+cb 0x26 TradeListPacketCB custom
+
+cb 0x27 EntityPositionPacketCB
+	EntityID               EntityID `datatype:"VarInt"`
+	DeltaX, DeltaY, DeltaZ int16
+	OnGround               bool
+
+cb 0x28 EntityPositionAndRotationPacketCB
+	EntityID               EntityID `datatype:"VarInt"`
+	DeltaX, DeltaY, DeltaZ int16
+	Yaw, Pitch             Angle
+	OnGround               bool
+
+cb 0x29 EntityRotationPacketCB
+	EntityID   EntityID `datatype:"VarInt"`
+	Yaw, Pitch Angle
+	OnGround   bool
+
+cb 0x2A EntityMovementPacketCB
+	EntityID EntityID `datatype:"VarInt"`
+
+cb 0x2B VehicleMovePacketCB
+	Position   Vec3d
+	Yaw, Pitch float32
+
+cb 0x2C OpenBookPacketCB
+	Hand VarInt
+
+cb 0x2D OpenWindowPacketCB
+	WindowID    VarInt
+	WindowType  VarInt
+	WindowTitle string `max_length:"262144" format:"chat"`
+
+cb 0x2E OpenSignEditorPacketCB
+	Location BlockPosition
+
+cb 0x2F CraftRecipeResponsePacketCB
+	WindowID int8
+	Recipe   Identifier
+
+cb 0x30 PlayerAbilitiesPacketCB
+	Flags               int8
+	FlyingSpeed         float32
+	FieldOfViewModifier float32
+
+cb 0x31 CombatEventPacketCB
+	Event    VarInt   // 0 enter combat, 1 end combat, 2 entity dead
+	Duration VarInt   `when:"Event==1"`
+	PlayerID VarInt   `when:"Event==2"`
+	EntityID EntityID `datatype:"int32" when:"Event==1,2"`
+	Message  string   `max_length:"262144" when:"Event==2" format:"chat"`
+
+cb 0x32 PlayerListItemPacketCB custom
+
+cb 0x33 FacePlayerPacketCB
+	FeetEyes       VarInt
+	Target         Vec3d
+	IsEntity       bool
+	EntityID       EntityID `datatype:"VarInt" when:"IsEntity==1"`
+	EntityFeetEyes VarInt   `when:"IsEntity==1"`
+
+cb 0x34 PlayerPositionAndLookPacketCB custom
+
+cb 0x35 UnlockRecipesPacketCB
+	Action                                             VarInt // 0 init, 1 add, 2 remove
+	CraftingBookOpen, CraftingBookFilterActive         bool
+	SmeltingBookOpen, SmeltingBookFilterActive         bool
+	BlastFurnaceBookOpen, BlastFurnaceBookFilterActive bool
+	SmokerBookOpen, SmokerBookFilterActive             bool
+	RecipeIDs                                          []Identifier
+	InitRecipeIDs                                      []Identifier `when:"Action==0"`
+
+cb 0x36 DestroyEntitiesPacketCB
+	EntityIDs []EntityID `datatype:"VarInt" length_prefix:"VarInt" max_count:"65536"`
+
+cb 0x37 RemoveEntityEffectPacketCB
+	EntityID EntityID `datatype:"VarInt"`
+	EffectID int8
+
+cb 0x38 ResourcePackSendCB custom
+cb 0x39 RespawnPacketCB custom
+
+cb 0x3A EntityHeadLookPacketCB
+	EntityID EntityID `datatype:"VarInt"`
+	HeadYaw  Angle
+
+cb 0x3B MultiBlockChangePacketCB
+	ChunkSectionPosition SectionPos
+	NoTrustEdges         bool
+	Blocks               []VarLong `length_prefix:"VarInt" max_count:"4096"` // block state id << 12 | x << 8 | z << 4 | y
+
+cb 0x3C SelectAdvancementTabPacketCB
+	Identifier Identifier `optional:"bool"`
+
+cb 0x3D WorldBorderPacketCB
+	Action                   VarInt  // 0 set size, 1 lerp size, 2 set center, 3 initialize, 4 warning time, 5 warning blocks
+	X, Z                     float64 `when:"Action==2,3"`
+	Diameter                 float64 `when:"Action==0"`
+	OldDiameter, NewDiameter float64 `when:"Action==1,3"`
+	Speed                    VarLong `when:"Action==1,3"`
+	PortalTeleportBoundary   VarInt  `when:"Action==3"`
+	WarningTime              VarInt  `when:"Action==3,4"`
+	WarningBlocks            VarInt  `when:"Action==3,5"`
+
+cb 0x3E CameraPacketCB custom
+
+cb 0x3F HeldItemChangePacketCB
+	Slot int8
+
+cb 0x40 UpdateViewPositionPacketCB
+	ChunkX, ChunkZ VarInt
+
+cb 0x41 UpdateViewDistancePacketCB
+	ViewDistance VarInt
+
+cb 0x42 SpawnPositionPacketCB custom
+
+cb 0x43 DisplayScoreboardPacketCB
+	Position  int8
+	ScoreName string `max_length:"16"`
+
+cb 0x44 EntityMetadataPacketCB custom
+
+cb 0x45 AttachEntityPacketCB
+	AttachedEntityID EntityID `datatype:"int32"`
+	HoldingEntityID  EntityID `datatype:"int32"`
+
+cb 0x46 EntityVelocityPacketCB
+	EntityID                        EntityID `datatype:"VarInt"`
+	VelocityX, VelocityY, VelocityZ int16
+
+# equipment array has no length, top bit of slot tells if another entry follows
+cb 0x47 EntityEquipmentPacketCB custom
+
+cb 0x48 SetExperiencePacketCB
+	ExperienceBar   float32
+	Level           VarInt
+	TotalExperience VarInt
+
+cb 0x49 UpdateHealthPacketCB
+	Health         float32
+	Food           VarInt
+	FoodSaturation float32
+
+cb 0x4A ScoreboardObjectivePacketCB custom
+
+cb 0x4B SetPassengersPacketCB
+	EntityID   EntityID   `datatype:"VarInt"`
+	Passengers []EntityID `datatype:"VarInt" length_prefix:"VarInt" max_count:"65536"`
+
+cb 0x4C TeamsPacketCB custom
+
+cb 0x4D UpdateScorePacketCB
+	EntityName    string `max_length:"40"`
+	Action        int8   // 0 update, 1 remove
+	ObjectiveName string `max_length:"16"`
+	Value         VarInt `when:"Action!=1"`
+
+cb 0x4E TimeUpdatePacketCB
+	WorldAge  int64
+	TimeOfDay int64
+
+cb 0x4F TitlePacketCB
+	Action                VarInt // 0 title, 1 subtitle, 2 action bar, 3 times, 4 hide, 5 reset
+	Text                  string `max_length:"262144" when:"Action==0,1,2" format:"chat"`
+	FadeIn, Stay, FadeOut int32  `when:"Action==3"`
+
+cb 0x50 EntitySoundEffectPacketCB
+	SoundID       VarInt
+	Category      VarInt
+	EntityID      EntityID `datatype:"VarInt"`
+	Volume, Pitch float32
+
+cb 0x51 SoundEffectPacketCB
+	SoundID       VarInt
+	Category      VarInt
+	X, Y, Z       int32 // fixed-point, 1/8 of block, see SoundPos
+	Volume, Pitch float32
+
+cb 0x52 StopSoundPacketCB
+	Flags  int8       // 1 source present, 2 sound present
+	Source VarInt     `when:"Flags==1,3"`
+	Sound  Identifier `when:"Flags==2,3"`
+
+cb 0x53 PlayerListTitlePacketCB custom
+
+cb 0x54 NBTQueryResponsePacketCB
+	TransactionID VarInt
+	NBT           nbt.TagCompound
+
+cb 0x55 CollectItemPacketCB
+	CollectedEntityID EntityID `datatype:"VarInt"`
+	CollectorEntityID EntityID `datatype:"VarInt"`
+	PickupItemCount   VarInt
+
+cb 0x56 EntityTeleportPacketCB
+	EntityID   EntityID `datatype:"VarInt"`
+	Position   Vec3d
+	Yaw, Pitch Angle
+	OnGround   bool
+
+struct AdvancementDisplay
+	Title, Description string `max_length:"262144" format:"chat"`
+	Icon               Slot
+	FrameType          VarInt
+	Flags              int32      // 0x01 has background texture, 0x02 show toast, 0x04 hidden
+	BackgroundTexture  Identifier `when:"Flags==1,3,5,7"`
+	X, Y               float32
+
+struct Advancement
+	Key          Identifier
+	HasParent    bool
+	Parent       Identifier `when:"HasParent==1"`
+	HasDisplay   bool
+	Display      AdvancementDisplay `when:"HasDisplay==1"`
+	Criteria     []Identifier
+	Requirements [][]string `length_prefix:"VarInt" max_count:"1024" max_length:"32767"` // any criterion of each array is required
+
+struct CriterionProgress
+	Criterion Identifier
+	Achieved  bool
+	Date      int64 `when:"Achieved==1"` // milliseconds since epoch
+
+struct AdvancementProgress
+	Key      Identifier
+	Criteria []CriterionProgress `length_prefix:"VarInt" max_count:"1024"`
+
+cb 0x57 AdvancementsPacketCB
+	Reset        bool
+	Advancements []Advancement `length_prefix:"VarInt" max_count:"65536"`
+	Removed      []Identifier
+	Progress     []AdvancementProgress `length_prefix:"VarInt" max_count:"65536"`
+
+struct AttributeModifier
+	UUID      uuid.UUID
+	Amount    float64
+	Operation int8
+
+struct EntityProperty
+	Key       Identifier
+	Value     float64
+	Modifiers []AttributeModifier `length_prefix:"VarInt" max_count:"1024"`
+
+cb 0x58 EntityPropertiesPacketCB
+	EntityID   EntityID         `datatype:"VarInt"`
+	Properties []EntityProperty `length_prefix:"int32" max_count:"1024"`
+
+cb 0x59 EntityEffectPacketCB
+	EntityID  EntityID `datatype:"VarInt"`
+	EffectID  int8
+	Amplifier int8
+	Duration  VarInt
+	Flags     int8
+
+cb 0x5A DeclareRecipesPacketCB custom
+
+struct NamedTag
+	Name    Identifier
+	Entries []VarInt `length_prefix:"VarInt" max_count:"65536"`
+
+cb 0x5B TagsPacketCB
+	BlockTags, ItemTags, FluidTags, EntityTags []NamedTag `length_prefix:"VarInt" max_count:"65536"`
+
+# > serverbound
+
+sb 0x00 TeleportConfirmPacketSB
+	TeleportID VarInt
+
+sb 0x01 QueryBlockNBTPacketSB
+	TransactionID VarInt
+	Location      BlockPosition
+
+sb 0x02 SetDifficultyPacketSB
+	NewDifficulty int8
+
+sb 0x03 ChatMessagePacketSB custom
+sb 0x04 ClientStatusPacketSB custom
+sb 0x05 ClientSettingsPacketSB custom
+sb 0x06 TabCompletePacketSB custom
+
+sb 0x07 WindowConfirmationPacketSB
+	WindowID     int8
+	ActionNumber int16
+	Accepted     bool
+
+sb 0x08 ClickWindowButtonPacketSB
+	WindowID int8
+	ButtonID int8
+
+sb 0x09 ClickWindowPacketSB
+	WindowID     uint8
+	Slot         int16
+	Button       int8
+	ActionNumber int16
+	Mode         VarInt
+	ClickedItem  Slot
+
+sb 0x0A CloseWindowPacketSB
+	WindowID uint8
+
+sb 0x0B PluginMessagePacketSB custom
+
+sb 0x0C EditBookPacketSB
+	NewBook   Slot
+	IsSigning bool
+	Hand      VarInt
+
+sb 0x0D QueryEntityNBTPacketSB
+	TransactionID VarInt
+	EntityID      EntityID `datatype:"VarInt"`
+
+sb 0x0E InteractEntityPacketSB
+	EntityID                  EntityID `datatype:"VarInt"`
+	Type                      VarInt   // 0 interact, 1 attack, 2 interact at
+	TargetX, TargetY, TargetZ float32  `when:"Type==2"`
+	Hand                      VarInt   `when:"Type==0,2"`
+	Sneaking                  bool
+
+sb 0x0F GenerateStructurePacketSB
+	Location    BlockPosition
+	Levels      VarInt
+	KeepJigsaws bool
+
+sb 0x10 KeepAlivePacketSB
+	ID int64
+
+sb 0x11 LockDifficultyPacketSB
+	Locked bool
+
+sb 0x12 PlayerPositionPacketSB
+	Position Vec3d // feet
+	OnGround bool
+
+sb 0x13 PlayerPositionAndRotationPacketSB
+	Position   Vec3d // feet
+	Yaw, Pitch float32
+	OnGround   bool
+
+sb 0x14 PlayerRotationPacketSB
+	Yaw, Pitch float32
+	OnGround   bool
+
+sb 0x15 PlayerMovementPacketSB
+	OnGround bool
+
+sb 0x16 VehicleMovePacketSB
+	Position   Vec3d
+	Yaw, Pitch float32
+
+sb 0x17 SteerBoatPacketSB
+	LeftPaddleTurning, RightPaddleTurning bool
+
+sb 0x18 PickItemPacketSB
+	SlotToUse VarInt
+
+sb 0x19 CraftRecipeRequestPacketSB
+	WindowID int8
+	Recipe   Identifier
+	MakeAll  bool
+
+sb 0x1A PlayerAbilitiesPacketSB
+	Flags int8
+
+sb 0x1B PlayerDiggingPacketSB
+	Status   VarInt
+	Location BlockPosition
+	Face     int8
+
+sb 0x1C EntityActionPacketSB
+	EntityID  EntityID `datatype:"VarInt"`
+	ActionID  VarInt
+	JumpBoost VarInt
+
+sb 0x1D SteerVehiclePacketSB
+	Sideways, Forward float32
+	Flags             uint8
+
+sb 0x1E SetRecipeBookStatePacketSB
+	BookID       VarInt
+	BookOpen     bool
+	FilterActive bool
+
+sb 0x1F SetDisplayedRecipePacketSB
+	RecipeID Identifier
+
+sb 0x20 NameItemPacketSB
+	ItemName string `max_length:"32767"`
+
+sb 0x21 ResourcePackStatusPacketSB
+	Result VarInt
+
+sb 0x22 AdvancementTabPacketSB
+	Action VarInt     // 0 opened tab, 1 closed screen
+	TabID  Identifier `when:"Action==0"`
+
+sb 0x23 SelectTradePacketSB
+	SelectedSlot VarInt
+
+sb 0x24 SetBeaconEffectPacketSB
+	PrimaryEffect, SecondaryEffect VarInt
+
+sb 0x25 HeldItemChangePacketSB
+	Slot int16
+
+sb 0x26 UpdateCommandBlockPacketSB
+	Location BlockPosition
+	Command  string `max_length:"32767"`
+	Mode     VarInt
+	Flags    int8
+
+sb 0x27 UpdateCommandBlockMinecartPacketSB
+	EntityID    EntityID `datatype:"VarInt"`
+	Command     string   `max_length:"32767"`
+	TrackOutput bool
+
+sb 0x28 CreativeInventoryActionPacketSB
+	Slot        int16
+	ClickedItem Slot
+
+sb 0x29 UpdateJigsawBlockPacketSB
+	Location           BlockPosition
+	Name, Target, Pool Identifier
+	FinalState         string `max_length:"32767"`
+	JointType          string `max_length:"32767"`
+
+sb 0x2A UpdateStructureBlockPacketSB
+	Location                  BlockPosition
+	Action                    VarInt
+	Mode                      VarInt
+	Name                      string `max_length:"32767"`
+	OffsetX, OffsetY, OffsetZ int8
+	SizeX, SizeY, SizeZ       int8
+	Mirror                    VarInt
+	Rotation                  VarInt
+	Metadata                  string `max_length:"128"`
+	Integrity                 float32
+	Seed                      VarLong
+	Flags                     int8
+
+sb 0x2B UpdateSignPacketSB
+	Location                   BlockPosition
+	Line1, Line2, Line3, Line4 string `max_length:"384"`
+
+sb 0x2C AnimationPacketSB
+	Hand VarInt
+
+sb 0x2D SpectatePacketSB
+	TargetPlayer uuid.UUID
+
+sb 0x2E PlayerBlockPlacementPacketSB
+	Hand                      VarInt
+	Location                  BlockPosition
+	Face                      VarInt
+	CursorX, CursorY, CursorZ float32
+	InsideBlock               bool
+
+sb 0x2F UseItemPacketSB
+	Hand VarInt
@@ -3,11 +3,13 @@ package packets
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/RyanW02/NamedBinaryTagParser/nbt"
+	"github.com/Craftserve/potoq/packets/nbt"
 	"github.com/google/uuid"
 	"io"
-	"io/ioutil"
+	"math/bits"
+	"strings"
 )
 
 // > 0x39 RespawnPacketCB
@@ -42,7 +44,7 @@ func (packet *RespawnPacketCB) Serialize(writer io.Writer) (err error) {
 // > 0x19 KickPacketCB
 
 type KickPacketCB struct {
-	Message string `max_length:"256"`
+	Message string `max_length:"262144" format:"chat"`
 }
 
 func (packet *KickPacketCB) PacketID() VarInt {
@@ -123,8 +125,8 @@ func (packet *JoinGamePacketCB) Serialize(writer io.Writer) (err error) {
 // > 0x17 PluginMessagePacketCB
 
 type PluginMessagePacketCB struct {
-	Channel string `max_length:"64"`
-	Payload []byte
+	Channel string `max_length:"128"`
+	Payload []byte `max_length:"1048576" length_prefix:"eof"`
 }
 
 func (packet *PluginMessagePacketCB) PacketID() VarInt {
@@ -136,18 +138,11 @@ func (packet *PluginMessagePacketCB) Direction() Direction {
 }
 
 func (packet *PluginMessagePacketCB) Parse(reader io.Reader) (err error) {
-	packet.Channel, err = ReadMinecraftString(reader, 128)
-	if err != nil {
-		return
-	}
-	packet.Payload, err = ioutil.ReadAll(reader)
-	return
+	return ReadMinecraftStruct(reader, packet)
 }
 
 func (packet *PluginMessagePacketCB) Serialize(writer io.Writer) (err error) {
-	WriteMinecraftString(writer, packet.Channel)
-	_, err = writer.Write(packet.Payload)
-	return
+	return WriteMinecraftStruct(writer, packet)
 }
 
 func (packet *PluginMessagePacketCB) MakePayloadReader() *bytes.Reader {
@@ -180,7 +175,7 @@ func (packet *GameStateChangePacketCB) Serialize(writer io.Writer) (err error) {
 // > 0x0E ChatMessagePacketCB
 
 type ChatMessagePacketCB struct {
-	Message  string `max_length:"32767"`
+	Message  string `max_length:"32767" format:"chat"`
 	Position uint8
 	Sender   uuid.UUID
 }
@@ -228,16 +223,15 @@ func (packet *ChatMessagePacketCB) SetText(text string) error {
 // > 0x0F TabCompletePacketCB
 
 type TabCompleteMatch struct {
-	Match   string
-	Tooltip string
+	Match   string `max_length:"32767"`
+	Tooltip string `max_length:"262144" optional:"bool" format:"chat"`
 }
 
 type TabCompletePacketCB struct {
 	TransactionId VarInt
 	Start         VarInt
 	Length        VarInt
-	Count         VarInt
-	Matches       []TabCompleteMatch `mcignore:"-"`
+	Matches       []TabCompleteMatch `length_prefix:"VarInt" max_count:"32767"`
 }
 
 func (packet *TabCompletePacketCB) PacketID() VarInt {
@@ -248,62 +242,12 @@ func (packet *TabCompletePacketCB) Direction() Direction {
 	return ClientBound
 }
 
-func (packet *TabCompletePacketCB) Parse(reader io.Reader) (err error) {
-	err = ReadMinecraftStruct(reader, packet)
-	if err != nil || packet.Count < 1 {
-		return
-	}
-	if packet.Count > 32767 {
-		return fmt.Errorf("invalid match count: %d", packet.Count)
-	}
-
-	packet.Matches = make([]TabCompleteMatch, int(packet.Count))
-	for i := range packet.Matches {
-		packet.Matches[i].Match, err = ReadMinecraftString(reader, 4096)
-		if err != nil {
-			return
-		}
-
-		var has_tooltip bool
-		has_tooltip, err = ReadBool(reader)
-		if err != nil {
-			return
-		}
-		if has_tooltip {
-			packet.Matches[i].Tooltip, err = ReadMinecraftString(reader, 4096)
-			if err != nil {
-				return
-			}
-		}
-	}
-	return
+func (packet *TabCompletePacketCB) Parse(reader io.Reader) error {
+	return ReadMinecraftStruct(reader, packet)
 }
 
-func (packet *TabCompletePacketCB) Serialize(writer io.Writer) (err error) {
-	packet.Count = VarInt(len(packet.Matches))
-	err = WriteMinecraftStruct(writer, packet)
-	if err != nil {
-		return err
-	}
-
-	for _, v := range packet.Matches {
-		err = WriteMinecraftString(writer, v.Match)
-		if err != nil {
-			return
-		}
-
-		err = WriteBool(writer, v.Tooltip != "")
-		if err != nil {
-			return
-		}
-		if v.Tooltip != "" {
-			err = WriteMinecraftString(writer, v.Tooltip)
-			if err != nil {
-				return
-			}
-		}
-	}
-	return
+func (packet *TabCompletePacketCB) Serialize(writer io.Writer) error {
+	return WriteMinecraftStruct(writer, packet)
 }
 
 // > 0x32 PlayerListItemPacketCB
@@ -315,18 +259,19 @@ const (
 	REMOVE_PLAYER
 )
 
+// Fields sent depend on Action of packet
 type PlayerListItem struct {
-	UUID        uuid.UUID      // Players UUID, sent in all actions, including REMOVE
-	Name        string         `max_length:"16"` // ADD_PLAYER
-	Properties  []AuthProperty // ADD_PLAYER
-	GameMode    VarInt         // ADD_PLAYER, UPDATE_GAME_MODE
-	Ping        VarInt         // ADD_PLAYER, UPDATE_LATENCY
-	DisplayName string         `max_length:"64"` // ADD_PLAYER, UPDATE_DISPLAY_NAME # json string
+	UUID        uuid.UUID      // sent in all actions, including REMOVE
+	Name        string         `max_length:"128" when:"Action==0"`
+	Properties  []AuthProperty `length_prefix:"VarInt" max_count:"16" when:"Action==0"`
+	GameMode    VarInt         `when:"Action==0,1"`
+	Ping        VarInt         `when:"Action==0,2"`
+	DisplayName string         `max_length:"32767" optional:"bool" when:"Action==0,3" format:"chat"`
 }
 
 type PlayerListItemPacketCB struct {
 	Action VarInt
-	Items  []PlayerListItem
+	Items  []PlayerListItem `length_prefix:"VarInt" max_count:"16384"`
 }
 
 func (packet *PlayerListItemPacketCB) PacketID() VarInt {
@@ -337,129 +282,19 @@ func (packet *PlayerListItemPacketCB) Direction() Direction {
 	return ClientBound
 }
 
-func (packet *PlayerListItemPacketCB) Parse(reader io.Reader) (err error) {
-	packet.Action, err = ReadVarInt(reader)
-	length, err := ReadVarInt(reader)
-	if err != nil {
-		return err
-	}
-	if length < 0 || length > 16384 {
-		return fmt.Errorf("PlayerListItemPacketCB bad item count %d", length)
-	}
-	packet.Items = make([]PlayerListItem, int(length))
-	for i := range packet.Items {
-		v := PlayerListItem{}
-		_, err = io.ReadFull(reader, []byte(v.UUID[:]))
-		if err != nil {
-			return
-		}
-		switch packet.Action {
-		case ADD_PLAYER:
-			v.Name, _ = ReadMinecraftString(reader, 128)
-			pnum, err := ReadVarInt(reader)
-			if err != nil {
-				return err
-			}
-			if pnum < 0 || pnum > 16 {
-				return fmt.Errorf("PlayerListItemPacketCB bad property count %d", pnum)
-			}
-			v.Properties = make([]AuthProperty, pnum)
-			for propIndex, prop := range v.Properties {
-				prop.Name, _ = ReadMinecraftString(reader, 32767)
-				prop.Value, _ = ReadMinecraftString(reader, 32767)
-				isSigned, err := ReadBool(reader)
-				if isSigned {
-					prop.Signature, err = ReadMinecraftString(reader, 32767)
-				}
-				if err != nil {
-					return err
-				}
-				v.Properties[propIndex] = prop
-			}
-			v.GameMode, _ = ReadVarInt(reader)
-			v.Ping, _ = ReadVarInt(reader)
-			var hasDisplayName bool
-			hasDisplayName, err = ReadBool(reader)
-			if hasDisplayName {
-				v.DisplayName, err = ReadMinecraftString(reader, 32767)
-			}
-		case UPDATE_GAME_MODE:
-			v.GameMode, err = ReadVarInt(reader)
-		case UPDATE_LATENCY:
-			v.Ping, err = ReadVarInt(reader)
-		case UPDATE_DISPLAY_NAME:
-			var hasDisplayName bool
-			hasDisplayName, err = ReadBool(reader)
-			if hasDisplayName {
-				v.DisplayName, err = ReadMinecraftString(reader, 32767)
-			}
-		case REMOVE_PLAYER:
-			// no data after uuid
-		}
-		packet.Items[i] = v
-		if err != nil {
-			return err
-		}
-	}
-	return
+func (packet *PlayerListItemPacketCB) Parse(reader io.Reader) error {
+	return ReadMinecraftStruct(reader, packet)
 }
 
-func (packet *PlayerListItemPacketCB) Serialize(writer io.Writer) (err error) {
-	WriteVarInt(writer, packet.Action)
-	err = WriteVarInt(writer, VarInt(len(packet.Items)))
-	if err != nil {
-		return err
-	}
-	for _, v := range packet.Items {
-		_, err = writer.Write([]byte(v.UUID[:]))
-		if err != nil {
-			return
-		}
-		switch packet.Action {
-		case ADD_PLAYER:
-			WriteMinecraftString(writer, v.Name)
-			WriteVarInt(writer, VarInt(len(v.Properties)))
-			for _, prop := range v.Properties {
-				WriteMinecraftString(writer, prop.Name)
-				WriteMinecraftString(writer, prop.Value)
-				err = WriteBool(writer, prop.Signature != "")
-				if prop.Signature != "" {
-					err = WriteMinecraftString(writer, prop.Signature)
-				}
-				if err != nil {
-					return
-				}
-			}
-			WriteVarInt(writer, v.GameMode)
-			WriteVarInt(writer, v.Ping)
-			err = WriteBool(writer, v.DisplayName != "")
-			if v.DisplayName != "" {
-				err = WriteMinecraftString(writer, v.DisplayName)
-			}
-		case UPDATE_GAME_MODE:
-			err = WriteVarInt(writer, v.GameMode)
-		case UPDATE_LATENCY:
-			err = WriteVarInt(writer, v.Ping)
-		case UPDATE_DISPLAY_NAME:
-			err = WriteBool(writer, v.DisplayName != "")
-			if v.DisplayName != "" {
-				err = WriteMinecraftString(writer, v.DisplayName)
-			}
-		case REMOVE_PLAYER:
-			// no data after uuid
-		}
-		if err != nil {
-			return err
-		}
-	}
-	return
+func (packet *PlayerListItemPacketCB) Serialize(writer io.Writer) error {
+	return WriteMinecraftStruct(writer, packet)
 }
 
 // 0x53 Player List Title (Header/Footer)
 
 type PlayerListTitlePacketCB struct {
-	Header string `max_length:"1024"`
-	Footer string `max_length:"1024"`
+	Header string `max_length:"262144" format:"chat"`
+	Footer string `max_length:"262144" format:"chat"`
 }
 
 func (packet *PlayerListTitlePacketCB) PacketID() VarInt {
@@ -484,8 +319,9 @@ func (packet *PlayerListTitlePacketCB) Serialize(writer io.Writer) (err error) {
 
 type ScoreboardObjectivePacketCB struct {
 	Name  string `max_length:"16"`
-	Value string `max_length:"16"`
-	Mode  byte
+	Mode  byte   // 0 create, 1 remove, 2 update
+	Value string `max_length:"262144" when:"Mode==0,2" format:"chat"`
+	Type  VarInt `when:"Mode==0,2"` // 0 integer, 1 hearts
 }
 
 func (packet *ScoreboardObjectivePacketCB) PacketID() VarInt {
@@ -506,16 +342,28 @@ func (packet *ScoreboardObjectivePacketCB) Serialize(writer io.Writer) (err erro
 	return
 }
 
-// > 0x4C TeamsPacketCB // TODO aktualizacja 1.13
+// > 0x4C TeamsPacketCB
+
+const (
+	TEAM_CREATE byte = iota
+	TEAM_REMOVE
+	TEAM_UPDATE
+	TEAM_ADD_ENTITIES
+	TEAM_REMOVE_ENTITIES
+)
 
 type TeamsPacketCB struct {
-	Name         string `max_length:"16"`
-	Mode         byte
-	DisplayName  string
-	Prefix       string
-	Suffix       string
-	FriendlyFire int8
-	Players      []string
+	Name              string `max_length:"16"`
+	Mode              byte   // TEAM_*
+	DisplayName       string `max_length:"262144" when:"Mode==0,2" format:"chat"`
+	FriendlyFlags     byte   `when:"Mode==0,2"`
+	NameTagVisibility string `max_length:"32" when:"Mode==0,2"`
+	CollisionRule     string `max_length:"32" when:"Mode==0,2"`
+	Color             VarInt `when:"Mode==0,2"`
+	Prefix            string `max_length:"262144" when:"Mode==0,2" format:"chat"`
+	Suffix            string `max_length:"262144" when:"Mode==0,2" format:"chat"`
+	// player names or entity UUIDs
+	Players []string `max_length:"40" length_prefix:"VarInt" max_count:"1024" when:"Mode==0,3,4"`
 }
 
 func (packet *TeamsPacketCB) PacketID() VarInt {
@@ -526,71 +374,12 @@ func (packet *TeamsPacketCB) Direction() Direction {
 	return ClientBound
 }
 
-func (packet *TeamsPacketCB) Parse(reader io.Reader) (err error) {
-	packet.Name, err = ReadMinecraftString(reader, 64)
-	packet.Mode, err = ReadUnsignedByte(reader)
-
-	if (packet.Mode == 0) || (packet.Mode == 2) { // create and update
-		packet.DisplayName, err = ReadMinecraftString(reader, 64)
-		packet.Prefix, err = ReadMinecraftString(reader, 16)
-		packet.Suffix, err = ReadMinecraftString(reader, 16)
-		packet.FriendlyFire, err = ReadByte(reader)
-	}
-
-	if err != nil {
-		return
-	}
-
-	if (packet.Mode == 0) || (packet.Mode == 3) || (packet.Mode == 4) { // create, add player, remove player
-		var player_count int16
-		player_count, err = ReadShort(reader)
-		if err != nil {
-			return
-		}
-		if (player_count < 0) || (player_count > 1024) {
-			return fmt.Errorf("Unexpected player_count in TeamsPacket: %d", player_count)
-		}
-		packet.Players = make([]string, player_count)
-		for i := 0; i < int(player_count); i++ {
-			packet.Players[int(i)], err = ReadMinecraftString(reader, 16)
-			if err != nil {
-				return
-			}
-		}
-	}
-
-	return
+func (packet *TeamsPacketCB) Parse(reader io.Reader) error {
+	return ReadMinecraftStruct(reader, packet)
 }
 
-func (packet *TeamsPacketCB) Serialize(writer io.Writer) (err error) {
-	WriteMinecraftString(writer, packet.Name)
-	WriteUnsignedByte(writer, packet.Mode)
-
-	if (packet.Mode == 0) || (packet.Mode == 2) { // create and update
-		WriteMinecraftString(writer, packet.DisplayName)
-		WriteMinecraftString(writer, packet.Prefix)
-		WriteMinecraftString(writer, packet.Suffix)
-		err = WriteByte(writer, packet.FriendlyFire)
-	}
-
-	if err != nil {
-		return
-	}
-
-	if (packet.Mode == 0) || (packet.Mode == 3) || (packet.Mode == 4) { // create, add player, remove player
-		if len(packet.Players) > 1024 {
-			return fmt.Errorf("TeamsPacket.Players too long! %d elements", len(packet.Players))
-		}
-
-		WriteShort(writer, int16(len(packet.Players)))
-		for _, v := range packet.Players {
-			err = WriteMinecraftString(writer, v)
-			if err != nil {
-				return
-			}
-		}
-	}
-	return
+func (packet *TeamsPacketCB) Serialize(writer io.Writer) error {
+	return WriteMinecraftStruct(writer, packet)
 }
 
 // > 0x4A TimeUpdatePacketCB
@@ -686,13 +475,11 @@ func (packet *TeamsPacketCB) Serialize(writer io.Writer) (err error) {
 // 0x04 Spawn Player
 
 type SpawnPlayer struct {
-	EntityID EntityID
-	UUID     string
-	X        int32
-	Y        int32
-	Z        int32
-	Yaw      int8
-	Pitch    int8
+	EntityID EntityID `datatype:"VarInt"`
+	UUID     uuid.UUID
+	Position Vec3d
+	Yaw      Angle
+	Pitch    Angle
 }
 
 func (packet *SpawnPlayer) PacketID() VarInt {
@@ -703,74 +490,12 @@ func (packet *SpawnPlayer) Direction() Direction {
 	return ClientBound
 }
 
-func (packet *SpawnPlayer) Parse(reader io.Reader) (err error) {
-	entityID, err := ReadVarInt(reader)
-	if err != nil {
-		return err
-	}
-	packet.EntityID = EntityID(entityID)
-	packet.UUID, err = ReadMinecraftString(reader, 64)
-	if err != nil {
-		return err
-	}
-	dataCount, err := ReadVarInt(reader)
-	if err != nil {
-		return err
-	}
-
-	if dataCount > 1024 {
-		return fmt.Errorf("SpawnPlayer.Data too long! %d elements", dataCount)
-	}
-
-	packet.X, err = ReadInt(reader)
-	if err != nil {
-		return err
-	}
-	packet.Y, err = ReadInt(reader)
-	if err != nil {
-		return err
-	}
-	packet.Z, err = ReadInt(reader)
-	if err != nil {
-		return err
-	}
-	packet.Yaw, err = ReadByte(reader)
-	if err != nil {
-		return err
-	}
-	packet.Pitch, err = ReadByte(reader)
-
-	return
+func (packet *SpawnPlayer) Parse(reader io.Reader) error {
+	return ReadMinecraftStruct(reader, packet)
 }
 
-func (packet *SpawnPlayer) Serialize(writer io.Writer) (err error) {
-	err = WriteVarInt(writer, VarInt(packet.EntityID))
-	if err != nil {
-		return err
-	}
-	err = WriteMinecraftString(writer, packet.UUID)
-	if err != nil {
-		return err
-	}
-
-	err = WriteInt(writer, packet.X)
-	if err != nil {
-		return err
-	}
-	err = WriteInt(writer, packet.Y)
-	if err != nil {
-		return err
-	}
-	err = WriteInt(writer, packet.Z)
-	if err != nil {
-		return err
-	}
-	err = WriteByte(writer, packet.Yaw)
-	if err != nil {
-		return err
-	}
-
-	return WriteByte(writer, packet.Pitch)
+func (packet *SpawnPlayer) Serialize(writer io.Writer) error {
+	return WriteMinecraftStruct(writer, packet)
 }
 
 // 0x16 Window Property
@@ -827,7 +552,7 @@ func (packet *PlayerPositionAndLookPacketCB) Direction() Direction {
 
 // 0x42 Spawn Position
 type SpawnPositionPacketCB struct {
-	Position Position
+	Position BlockPosition
 }
 
 func (packet *SpawnPositionPacketCB) PacketID() VarInt {
@@ -871,8 +596,8 @@ func (packet *CameraPacketCB) Direction() Direction {
 // 0x38 ResourcePack
 
 type ResourcePackSendCB struct {
-	Url  string
-	Hash string
+	Url  string `max_length:"32767"`
+	Hash string `max_length:"40"`
 }
 
 func (packet *ResourcePackSendCB) PacketID() VarInt {
@@ -890,3 +615,773 @@ func (packet *ResourcePackSendCB) Serialize(writer io.Writer) error {
 func (packet *ResourcePackSendCB) Direction() Direction {
 	return ClientBound
 }
+
+// > 0x10 DeclareCommandsPacketCB
+
+// type of CommandNode, lowest two bits of its Flags
+const (
+	COMMAND_NODE_ROOT byte = iota
+	COMMAND_NODE_LITERAL
+	COMMAND_NODE_ARGUMENT
+)
+
+const (
+	COMMAND_NODE_EXECUTABLE  byte = 0x04
+	COMMAND_NODE_REDIRECT    byte = 0x08
+	COMMAND_NODE_SUGGESTIONS byte = 0x10
+)
+
+const maxCommandNodes = 65536
+
+type DeclareCommandsPacketCB struct {
+	Nodes     []CommandNode
+	RootIndex VarInt
+}
+
+// Fields other than Flags and Children are present on wire only for node types and flags given in comments
+type CommandNode struct {
+	Flags           byte       // COMMAND_NODE_* type | flags
+	Children        []VarInt   // indexes in Nodes
+	RedirectNode    VarInt     // COMMAND_NODE_REDIRECT
+	Name            string     // literal and argument nodes
+	Parser          Identifier // argument nodes
+	Properties      CommandProperties
+	SuggestionsType Identifier // argument nodes with COMMAND_NODE_SUGGESTIONS
+}
+
+// Properties of argument parsers, fields not used by node Parser are ignored
+type CommandProperties struct {
+	Flags          byte    // brigadier numbers (0x01 has min, 0x02 has max), minecraft:entity, minecraft:score_holder
+	Min, Max       float64 // brigadier:double, brigadier:float
+	IntMin, IntMax int64   // brigadier:integer, brigadier:long
+	StringType     VarInt  // brigadier:string
+	Decimals       bool    // minecraft:range
+}
+
+// parsers of 1.16.3 without properties, unknown parser can't be skipped
+var commandParsers = map[Identifier]bool{
+	"brigadier:bool": true, "minecraft:game_profile": true, "minecraft:block_pos": true,
+	"minecraft:column_pos": true, "minecraft:vec3": true, "minecraft:vec2": true,
+	"minecraft:block_state": true, "minecraft:block_predicate": true, "minecraft:item_stack": true,
+	"minecraft:item_predicate": true, "minecraft:color": true, "minecraft:component": true,
+	"minecraft:message": true, "minecraft:nbt_compound_tag": true, "minecraft:nbt_tag": true,
+	"minecraft:nbt_path": true, "minecraft:objective": true, "minecraft:objective_criteria": true,
+	"minecraft:operation": true, "minecraft:particle": true, "minecraft:angle": true,
+	"minecraft:rotation": true, "minecraft:scoreboard_slot": true, "minecraft:swizzle": true,
+	"minecraft:team": true, "minecraft:item_slot": true, "minecraft:resource_location": true,
+	"minecraft:mob_effect": true, "minecraft:function": true, "minecraft:entity_anchor": true,
+	"minecraft:int_range": true, "minecraft:float_range": true, "minecraft:item_enchantment": true,
+	"minecraft:entity_summon": true, "minecraft:dimension": true, "minecraft:uuid": true,
+	"minecraft:time": true,
+}
+
+func (packet *DeclareCommandsPacketCB) PacketID() VarInt {
+	return 0x10
+}
+
+func (packet *DeclareCommandsPacketCB) Direction() Direction {
+	return ClientBound
+}
+
+func (packet *DeclareCommandsPacketCB) Parse(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if n, err = readCount(br, "VarInt", maxCommandNodes); err != nil {
+		return
+	}
+	packet.Nodes = make([]CommandNode, 0, countCapacity(n))
+	for i := 0; i < n; i++ {
+		var node CommandNode
+		if err = node.parse(br); err != nil {
+			return
+		}
+		packet.Nodes = append(packet.Nodes, node)
+	}
+	packet.RootIndex, err = ReadVarIntFrom(br)
+	return
+}
+
+func (packet *DeclareCommandsPacketCB) Serialize(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeCount(writer, "VarInt", maxCommandNodes, len(packet.Nodes)); err != nil {
+		return
+	}
+	for i := range packet.Nodes {
+		if err = packet.Nodes[i].serialize(writer); err != nil {
+			return
+		}
+	}
+	return WriteVarInt(writer, packet.RootIndex)
+}
+
+func (node *CommandNode) parse(br byter) (err error) {
+	if node.Flags, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	typ := node.Flags & 0x03
+	if typ > COMMAND_NODE_ARGUMENT {
+		return fmt.Errorf("DeclareCommands: invalid node type %d", typ)
+	}
+	var n int
+	if n, err = readCount(br, "VarInt", maxCommandNodes); err != nil {
+		return
+	}
+	node.Children = make([]VarInt, 0, countCapacity(n))
+	for i := 0; i < n; i++ {
+		var child VarInt
+		if child, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+		node.Children = append(node.Children, child)
+	}
+	if node.Flags&COMMAND_NODE_REDIRECT != 0 {
+		if node.RedirectNode, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	}
+	if typ == COMMAND_NODE_ROOT {
+		return
+	}
+	if node.Name, err = ReadMinecraftString(br, 32767); err != nil || typ == COMMAND_NODE_LITERAL {
+		return
+	}
+	if node.Parser, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	if err = node.Properties.parse(br, node.Parser); err != nil {
+		return
+	}
+	if node.Flags&COMMAND_NODE_SUGGESTIONS != 0 {
+		node.SuggestionsType, err = ReadIdentifier(br)
+	}
+	return
+}
+
+func (node *CommandNode) serialize(writer io.Writer) (err error) {
+	typ := node.Flags & 0x03
+	if typ > COMMAND_NODE_ARGUMENT {
+		return fmt.Errorf("DeclareCommands: invalid node type %d", typ)
+	}
+	if err = WriteUnsignedByte(writer, node.Flags); err != nil {
+		return
+	}
+	if err = writeCount(writer, "VarInt", maxCommandNodes, len(node.Children)); err != nil {
+		return
+	}
+	for _, child := range node.Children {
+		if err = WriteVarInt(writer, child); err != nil {
+			return
+		}
+	}
+	if node.Flags&COMMAND_NODE_REDIRECT != 0 {
+		if err = WriteVarInt(writer, node.RedirectNode); err != nil {
+			return
+		}
+	}
+	if typ == COMMAND_NODE_ROOT {
+		return
+	}
+	if err = WriteMinecraftString(writer, node.Name); err != nil || typ == COMMAND_NODE_LITERAL {
+		return
+	}
+	if err = WriteIdentifier(writer, node.Parser); err != nil {
+		return
+	}
+	if err = node.Properties.serialize(writer, node.Parser); err != nil {
+		return
+	}
+	if node.Flags&COMMAND_NODE_SUGGESTIONS != 0 {
+		err = WriteIdentifier(writer, node.SuggestionsType)
+	}
+	return
+}
+
+func (props *CommandProperties) parse(br byter, parser Identifier) (err error) {
+	switch parser {
+	case "brigadier:double", "brigadier:float", "brigadier:integer", "brigadier:long":
+		if props.Flags, err = ReadUnsignedByte(br); err != nil {
+			return
+		}
+		if props.Flags&0x01 != 0 {
+			if props.Min, props.IntMin, err = readCommandBound(br, parser); err != nil {
+				return
+			}
+		}
+		if props.Flags&0x02 != 0 {
+			props.Max, props.IntMax, err = readCommandBound(br, parser)
+		}
+	case "brigadier:string":
+		props.StringType, err = ReadVarIntFrom(br)
+	case "minecraft:entity", "minecraft:score_holder":
+		props.Flags, err = ReadUnsignedByte(br)
+	case "minecraft:range":
+		props.Decimals, err = ReadBool(br)
+	default:
+		if !commandParsers[parser] {
+			err = fmt.Errorf("DeclareCommands: unknown parser %q", parser)
+		}
+	}
+	return
+}
+
+func (props *CommandProperties) serialize(writer io.Writer, parser Identifier) (err error) {
+	switch parser {
+	case "brigadier:double", "brigadier:float", "brigadier:integer", "brigadier:long":
+		if err = WriteUnsignedByte(writer, props.Flags); err != nil {
+			return
+		}
+		if props.Flags&0x01 != 0 {
+			if err = writeCommandBound(writer, parser, props.Min, props.IntMin); err != nil {
+				return
+			}
+		}
+		if props.Flags&0x02 != 0 {
+			err = writeCommandBound(writer, parser, props.Max, props.IntMax)
+		}
+	case "brigadier:string":
+		err = WriteVarInt(writer, props.StringType)
+	case "minecraft:entity", "minecraft:score_holder":
+		err = WriteUnsignedByte(writer, props.Flags)
+	case "minecraft:range":
+		err = WriteBool(writer, props.Decimals)
+	default:
+		if !commandParsers[parser] {
+			err = fmt.Errorf("DeclareCommands: unknown parser %q", parser)
+		}
+	}
+	return
+}
+
+// min or max of brigadier number parser, floats are returned in first value
+func readCommandBound(br byter, parser Identifier) (float64, int64, error) {
+	switch parser {
+	case "brigadier:double":
+		v, err := ReadDouble(br)
+		return v, 0, err
+	case "brigadier:float":
+		v, err := ReadFloat(br)
+		return float64(v), 0, err
+	case "brigadier:integer":
+		v, err := ReadInt(br)
+		return 0, int64(v), err
+	default:
+		v, err := ReadLong(br)
+		return 0, v, err
+	}
+}
+
+func writeCommandBound(writer io.Writer, parser Identifier, f float64, i int64) error {
+	switch parser {
+	case "brigadier:double":
+		return WriteDouble(writer, f)
+	case "brigadier:float":
+		return WriteFloat(writer, float32(f))
+	case "brigadier:integer":
+		return WriteInt(writer, int32(i))
+	default:
+		return WriteLong(writer, i)
+	}
+}
+
+// > 0x23 UpdateLightPacketCB
+
+// light array of one chunk section, 4 bits per block
+const lightArrayLength = 2048
+
+type UpdateLightPacketCB struct {
+	ChunkX, ChunkZ                         VarInt
+	TrustEdges                             bool
+	SkyLightMask, BlockLightMask           VarInt
+	EmptySkyLightMask, EmptyBlockLightMask VarInt
+	SkyLight                               [][]byte // one array for each bit set in SkyLightMask
+	BlockLight                             [][]byte // one array for each bit set in BlockLightMask
+}
+
+func (packet *UpdateLightPacketCB) PacketID() VarInt {
+	return 0x23
+}
+
+func (packet *UpdateLightPacketCB) Direction() Direction {
+	return ClientBound
+}
+
+func (packet *UpdateLightPacketCB) Parse(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.ChunkX, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.ChunkZ, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.TrustEdges, err = ReadBool(br); err != nil {
+		return
+	}
+	for _, mask := range []*VarInt{&packet.SkyLightMask, &packet.BlockLightMask, &packet.EmptySkyLightMask, &packet.EmptyBlockLightMask} {
+		if *mask, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+	}
+	if packet.SkyLight, err = readLightArrays(br, packet.SkyLightMask); err != nil {
+		return
+	}
+	packet.BlockLight, err = readLightArrays(br, packet.BlockLightMask)
+	return
+}
+
+func (packet *UpdateLightPacketCB) Serialize(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.ChunkX); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.ChunkZ); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.TrustEdges); err != nil {
+		return
+	}
+	for _, mask := range []VarInt{packet.SkyLightMask, packet.BlockLightMask, packet.EmptySkyLightMask, packet.EmptyBlockLightMask} {
+		if err = WriteVarInt(writer, mask); err != nil {
+			return
+		}
+	}
+	if err = writeLightArrays(writer, packet.SkyLightMask, packet.SkyLight); err != nil {
+		return
+	}
+	return writeLightArrays(writer, packet.BlockLightMask, packet.BlockLight)
+}
+
+func readLightArrays(br byter, mask VarInt) (arrays [][]byte, err error) {
+	arrays = make([][]byte, bits.OnesCount32(uint32(mask)))
+	for i := range arrays {
+		if arrays[i], err = readBytesField(br, "VarInt", lightArrayLength); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func writeLightArrays(writer io.Writer, mask VarInt, arrays [][]byte) (err error) {
+	if len(arrays) != bits.OnesCount32(uint32(mask)) {
+		return fmt.Errorf("UpdateLight: %d light arrays for mask %#x", len(arrays), uint32(mask))
+	}
+	for _, array := range arrays {
+		if err = writeBytesField(writer, "VarInt", lightArrayLength, array); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// > 0x44 EntityMetadataPacketCB
+
+type EntityMetadataPacketCB struct {
+	EntityID EntityID
+	Metadata EntityMetadata
+}
+
+func (packet *EntityMetadataPacketCB) PacketID() VarInt {
+	return 0x44
+}
+
+func (packet *EntityMetadataPacketCB) Direction() Direction {
+	return ClientBound
+}
+
+func (packet *EntityMetadataPacketCB) Parse(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	return packet.Metadata.Parse(br)
+}
+
+func (packet *EntityMetadataPacketCB) Serialize(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	return packet.Metadata.Serialize(writer)
+}
+
+// > 0x26 TradeListPacketCB
+
+type TradeListPacketCB struct {
+	WindowID          VarInt
+	Trades            []Trade // at most 255
+	VillagerLevel     VarInt
+	Experience        VarInt
+	IsRegularVillager bool
+	CanRestock        bool
+}
+
+func (packet *TradeListPacketCB) PacketID() VarInt {
+	return 0x26
+}
+
+func (packet *TradeListPacketCB) Direction() Direction {
+	return ClientBound
+}
+
+func (packet *TradeListPacketCB) Parse(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.WindowID, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	var n byte
+	if n, err = ReadUnsignedByte(br); err != nil {
+		return
+	}
+	packet.Trades = make([]Trade, n)
+	for i := range packet.Trades {
+		if err = ReadMinecraftStruct(br, &packet.Trades[i]); err != nil {
+			return
+		}
+	}
+	if packet.VillagerLevel, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.Experience, err = ReadVarIntFrom(br); err != nil {
+		return
+	}
+	if packet.IsRegularVillager, err = ReadBool(br); err != nil {
+		return
+	}
+	packet.CanRestock, err = ReadBool(br)
+	return
+}
+
+func (packet *TradeListPacketCB) Serialize(writer io.Writer) (err error) {
+	if len(packet.Trades) > 255 {
+		return fmt.Errorf("TradeList: too many trades: %d", len(packet.Trades))
+	}
+	writer = asByteWriter(writer)
+	if err = WriteVarInt(writer, packet.WindowID); err != nil {
+		return
+	}
+	if err = WriteUnsignedByte(writer, byte(len(packet.Trades))); err != nil {
+		return
+	}
+	for i := range packet.Trades {
+		if err = WriteMinecraftStruct(writer, &packet.Trades[i]); err != nil {
+			return
+		}
+	}
+	if err = WriteVarInt(writer, packet.VillagerLevel); err != nil {
+		return
+	}
+	if err = WriteVarInt(writer, packet.Experience); err != nil {
+		return
+	}
+	if err = WriteBool(writer, packet.IsRegularVillager); err != nil {
+		return
+	}
+	return WriteBool(writer, packet.CanRestock)
+}
+
+// > 0x47 EntityEquipmentPacketCB
+
+const (
+	EQUIPMENT_MAIN_HAND byte = iota
+	EQUIPMENT_OFF_HAND
+	EQUIPMENT_BOOTS
+	EQUIPMENT_LEGGINGS
+	EQUIPMENT_CHESTPLATE
+	EQUIPMENT_HELMET
+	equipmentSlots
+)
+
+type EquipmentEntry struct {
+	Slot byte // EQUIPMENT_*
+	Item Slot
+}
+
+// Equipment has at least one entry, on wire top bit of Slot means that another entry follows
+type EntityEquipmentPacketCB struct {
+	EntityID  EntityID
+	Equipment []EquipmentEntry
+}
+
+func (packet *EntityEquipmentPacketCB) PacketID() VarInt {
+	return 0x47
+}
+
+func (packet *EntityEquipmentPacketCB) Direction() Direction {
+	return ClientBound
+}
+
+func (packet *EntityEquipmentPacketCB) Parse(reader io.Reader) (err error) {
+	br := asByter(reader)
+	if packet.EntityID, err = readEntityIDField(br, "VarInt"); err != nil {
+		return
+	}
+	packet.Equipment = packet.Equipment[:0]
+	for {
+		if len(packet.Equipment) >= int(equipmentSlots) {
+			return errors.New("EntityEquipment: too many entries")
+		}
+		var slot byte
+		if slot, err = ReadUnsignedByte(br); err != nil {
+			return
+		}
+		entry := EquipmentEntry{Slot: slot &^ 0x80}
+		if err = entry.Item.Parse(br); err != nil {
+			return
+		}
+		packet.Equipment = append(packet.Equipment, entry)
+		if slot&0x80 == 0 {
+			return
+		}
+	}
+}
+
+func (packet *EntityEquipmentPacketCB) Serialize(writer io.Writer) (err error) {
+	if len(packet.Equipment) == 0 {
+		return errors.New("EntityEquipment: no entries")
+	}
+	writer = asByteWriter(writer)
+	if err = writeEntityIDField(writer, "VarInt", packet.EntityID); err != nil {
+		return
+	}
+	for i, entry := range packet.Equipment {
+		if entry.Slot&0x80 != 0 {
+			return fmt.Errorf("EntityEquipment: invalid slot %d", entry.Slot)
+		}
+		slot := entry.Slot
+		if i < len(packet.Equipment)-1 {
+			slot |= 0x80
+		}
+		if err = WriteUnsignedByte(writer, slot); err != nil {
+			return
+		}
+		if err = entry.Item.Serialize(writer); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// > 0x5A DeclareRecipesPacketCB
+
+const maxRecipes = 65536
+
+type DeclareRecipesPacketCB struct {
+	Recipes []Recipe
+}
+
+// Any of items matches ingredient
+type Ingredient []Slot
+
+// Fields other than Type, ID are present on wire only for recipe types given in comments
+type Recipe struct {
+	Type          Identifier // minecraft:crafting_shaped etc., prefix minecraft: is optional
+	ID            Identifier
+	Group         string       // crafting and cooking
+	Width, Height VarInt       // crafting_shaped, it has Width*Height ingredients
+	Ingredients   []Ingredient // crafting; single one for cooking and stonecutting; base and addition for smithing
+	Result        Slot         // all except crafting_special_*
+	Experience    float32      // cooking
+	CookingTime   VarInt       // cooking
+}
+
+func (packet *DeclareRecipesPacketCB) PacketID() VarInt {
+	return 0x5A
+}
+
+func (packet *DeclareRecipesPacketCB) Direction() Direction {
+	return ClientBound
+}
+
+func (packet *DeclareRecipesPacketCB) Parse(reader io.Reader) (err error) {
+	br := asByter(reader)
+	var n int
+	if n, err = readCount(br, "VarInt", maxRecipes); err != nil {
+		return
+	}
+	packet.Recipes = make([]Recipe, 0, countCapacity(n))
+	for i := 0; i < n; i++ {
+		var recipe Recipe
+		if err = recipe.parse(br); err != nil {
+			return
+		}
+		packet.Recipes = append(packet.Recipes, recipe)
+	}
+	return
+}
+
+func (packet *DeclareRecipesPacketCB) Serialize(writer io.Writer) (err error) {
+	writer = asByteWriter(writer)
+	if err = writeCount(writer, "VarInt", maxRecipes, len(packet.Recipes)); err != nil {
+		return
+	}
+	for i := range packet.Recipes {
+		if err = packet.Recipes[i].serialize(writer); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// kind of recipe data, recipes which have none return empty string
+func recipeKind(typ Identifier) (string, error) {
+	kind := strings.TrimPrefix(string(typ), "minecraft:")
+	switch kind {
+	case "crafting_shapeless", "crafting_shaped", "stonecutting", "smithing":
+		return kind, nil
+	case "smelting", "blasting", "smoking", "campfire_cooking":
+		return "cooking", nil
+	}
+	if strings.HasPrefix(kind, "crafting_special_") {
+		return "", nil
+	}
+	return "", fmt.Errorf("DeclareRecipes: unknown recipe type %q", typ)
+}
+
+func (recipe *Recipe) parse(br byter) (err error) {
+	if recipe.Type, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	if recipe.ID, err = ReadIdentifier(br); err != nil {
+		return
+	}
+	var kind string
+	if kind, err = recipeKind(recipe.Type); err != nil || kind == "" {
+		return
+	}
+	if kind != "smithing" {
+		if recipe.Group, err = ReadMinecraftString(br, 32767); err != nil {
+			return
+		}
+	}
+	n := 1
+	switch kind {
+	case "crafting_shapeless":
+		if n, err = readCount(br, "VarInt", maxIngredients); err != nil {
+			return
+		}
+	case "crafting_shaped":
+		if recipe.Width, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+		if recipe.Height, err = ReadVarIntFrom(br); err != nil {
+			return
+		}
+		if n, err = recipe.shapedCount(); err != nil {
+			return
+		}
+	case "smithing":
+		n = 2
+	}
+	recipe.Ingredients = make([]Ingredient, 0, countCapacity(n))
+	for i := 0; i < n; i++ {
+		var ingredient Ingredient
+		if ingredient, err = readIngredient(br); err != nil {
+			return
+		}
+		recipe.Ingredients = append(recipe.Ingredients, ingredient)
+	}
+	if err = recipe.Result.Parse(br); err != nil || kind != "cooking" {
+		return
+	}
+	if recipe.Experience, err = ReadFloat(br); err != nil {
+		return
+	}
+	recipe.CookingTime, err = ReadVarIntFrom(br)
+	return
+}
+
+func (recipe *Recipe) serialize(writer io.Writer) (err error) {
+	kind, err := recipeKind(recipe.Type)
+	if err != nil {
+		return
+	}
+	n := 1
+	switch kind {
+	case "":
+		n = 0
+	case "crafting_shapeless":
+		n = len(recipe.Ingredients)
+	case "crafting_shaped":
+		if n, err = recipe.shapedCount(); err != nil {
+			return
+		}
+	case "smithing":
+		n = 2
+	}
+	if len(recipe.Ingredients) != n {
+		return fmt.Errorf("DeclareRecipes: %s recipe %s has %d ingredients, %d expected", recipe.Type, recipe.ID, len(recipe.Ingredients), n)
+	}
+	if err = WriteIdentifier(writer, recipe.Type); err != nil {
+		return
+	}
+	if err = WriteIdentifier(writer, recipe.ID); err != nil || kind == "" {
+		return
+	}
+	if kind != "smithing" {
+		if err = WriteMinecraftString(writer, recipe.Group); err != nil {
+			return
+		}
+	}
+	switch kind {
+	case "crafting_shapeless":
+		if err = writeCount(writer, "VarInt", maxIngredients, n); err != nil {
+			return
+		}
+	case "crafting_shaped":
+		if err = WriteVarInt(writer, recipe.Width); err != nil {
+			return
+		}
+		if err = WriteVarInt(writer, recipe.Height); err != nil {
+			return
+		}
+	}
+	for _, ingredient := range recipe.Ingredients {
+		if err = writeIngredient(writer, ingredient); err != nil {
+			return
+		}
+	}
+	if err = recipe.Result.Serialize(writer); err != nil || kind != "cooking" {
+		return
+	}
+	if err = WriteFloat(writer, recipe.Experience); err != nil {
+		return
+	}
+	return WriteVarInt(writer, recipe.CookingTime)
+}
+
+const maxIngredients = 1024
+
+func (recipe *Recipe) shapedCount() (int, error) {
+	n := int64(recipe.Width) * int64(recipe.Height)
+	if recipe.Width < 0 || recipe.Height < 0 || n > maxIngredients {
+		return 0, fmt.Errorf("DeclareRecipes: invalid shaped recipe size %dx%d", recipe.Width, recipe.Height)
+	}
+	return int(n), nil
+}
+
+func readIngredient(br byter) (ingredient Ingredient, err error) {
+	var n int
+	if n, err = readCount(br, "VarInt", maxIngredients); err != nil {
+		return
+	}
+	ingredient = make(Ingredient, 0, countCapacity(n))
+	for i := 0; i < n; i++ {
+		var item Slot
+		if err = item.Parse(br); err != nil {
+			return
+		}
+		ingredient = append(ingredient, item)
+	}
+	return
+}
+
+func writeIngredient(writer io.Writer, ingredient Ingredient) (err error) {
+	if err = writeCount(writer, "VarInt", maxIngredients, len(ingredient)); err != nil {
+		return
+	}
+	for i := range ingredient {
+		if err = ingredient[i].Serialize(writer); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,157 @@
+package packets
+
+import (
+	"io"
+	"math"
+)
+
+// > coordinates used by play packets
+
+// Block coordinates, sent as int64 packed 26/12/26 bits (x, y, z)
+type BlockPosition struct {
+	X, Y, Z int32
+}
+
+func (p BlockPosition) pack() uint64 {
+	return uint64(p.X)&0x3FFFFFF<<38 | uint64(p.Z)&0x3FFFFFF<<12 | uint64(p.Y)&0xFFF
+}
+
+func unpackBlockPosition(v uint64) BlockPosition {
+	return BlockPosition{
+		X: int32(int64(v) >> 38),
+		Y: int32(int64(v<<52) >> 52),
+		Z: int32(int64(v<<26) >> 38),
+	}
+}
+
+func (p BlockPosition) Chunk() ChunkPos {
+	return ChunkPos{p.X >> 4, p.Z >> 4}
+}
+
+func (p BlockPosition) Section() SectionPos {
+	return SectionPos{p.X >> 4, p.Y >> 4, p.Z >> 4}
+}
+
+// Center of block
+func (p BlockPosition) Center() Vec3d {
+	return Vec3d{float64(p.X) + 0.5, float64(p.Y) + 0.5, float64(p.Z) + 0.5}
+}
+
+func ReadBlockPosition(reader io.Reader) (BlockPosition, error) {
+	v, err := readUint(reader, 8)
+	return unpackBlockPosition(v), err
+}
+
+func WriteBlockPosition(writer io.Writer, p BlockPosition) error {
+	return writeUint(writer, p.pack(), 8)
+}
+
+// Position of entity, sent as three doubles
+type Vec3d struct {
+	X, Y, Z float64
+}
+
+// Block containing position
+func (v Vec3d) Block() BlockPosition {
+	return BlockPosition{int32(math.Floor(v.X)), int32(math.Floor(v.Y)), int32(math.Floor(v.Z))}
+}
+
+func (v Vec3d) Chunk() ChunkPos {
+	return v.Block().Chunk()
+}
+
+func (v Vec3d) Add(o Vec3d) Vec3d {
+	return Vec3d{v.X + o.X, v.Y + o.Y, v.Z + o.Z}
+}
+
+func (v Vec3d) Sub(o Vec3d) Vec3d {
+	return Vec3d{v.X - o.X, v.Y - o.Y, v.Z - o.Z}
+}
+
+// Chunk coordinates (block >> 4), packets send them as separate fields
+type ChunkPos struct {
+	X, Z int32
+}
+
+// First block of chunk at y = 0
+func (c ChunkPos) Block() BlockPosition {
+	return BlockPosition{c.X << 4, 0, c.Z << 4}
+}
+
+// Chunk section coordinates, sent as int64 packed 22/22/20 bits (x, z, y) in Multi Block Change
+type SectionPos struct {
+	X, Y, Z int32
+}
+
+func (s SectionPos) pack() uint64 {
+	return uint64(s.X)&0x3FFFFF<<42 | uint64(s.Z)&0x3FFFFF<<20 | uint64(s.Y)&0xFFFFF
+}
+
+func unpackSectionPos(v uint64) SectionPos {
+	return SectionPos{
+		X: int32(int64(v) >> 42),
+		Y: int32(int64(v<<44) >> 44),
+		Z: int32(int64(v<<22) >> 42),
+	}
+}
+
+func (s SectionPos) Chunk() ChunkPos {
+	return ChunkPos{s.X, s.Z}
+}
+
+// First block of section
+func (s SectionPos) Block() BlockPosition {
+	return BlockPosition{s.X << 4, s.Y << 4, s.Z << 4}
+}
+
+func ReadSectionPos(reader io.Reader) (SectionPos, error) {
+	v, err := readUint(reader, 8)
+	return unpackSectionPos(v), err
+}
+
+func WriteSectionPos(writer io.Writer, s SectionPos) error {
+	return writeUint(writer, s.pack(), 8)
+}
+
+// Rotation in 1/256 of full turn, sent as unsigned byte
+type Angle uint8
+
+func AngleFromDegrees(degrees float32) Angle {
+	return Angle(int(math.Floor(float64(degrees)*256/360)) & 0xFF)
+}
+
+// Degrees in range [0, 360)
+func (a Angle) Degrees() float32 {
+	return float32(a) * 360 / 256
+}
+
+func ReadAngle(reader io.Reader) (Angle, error) {
+	v, err := ReadUnsignedByte(reader)
+	return Angle(v), err
+}
+
+func WriteAngle(writer io.Writer, a Angle) error {
+	return WriteUnsignedByte(writer, uint8(a))
+}
+
+// > fixed-point values
+
+// Entity Position packets move entity by delta in 1/4096 of block, returns delta between positions
+// or false if it doesn't fit in int16 (Entity Teleport must be sent then)
+func MoveDelta(from, to float64) (int16, bool) {
+	d := math.Round((to - from) * 4096)
+	return int16(d), d >= math.MinInt16 && d <= math.MaxInt16
+}
+
+func ApplyDelta(pos float64, delta int16) float64 {
+	return pos + float64(delta)/4096
+}
+
+// Sound packets send position in 1/8 of block
+func SoundCoord(pos float64) int32 {
+	return int32(math.Floor(pos * 8))
+}
+
+func SoundPos(x, y, z int32) Vec3d {
+	return Vec3d{float64(x) / 8, float64(y) / 8, float64(z) / 8}
+}
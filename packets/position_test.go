@@ -0,0 +1,58 @@
+package packets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestBlockPosition(t *testing.T) {
+	// example from protocol documentation
+	pos := BlockPosition{18357644, 831, -20882616}
+	var buf bytes.Buffer
+	if err := (&SpawnPositionPacketCB{pos}).Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if v := binary.BigEndian.Uint64(buf.Bytes()); v != 0x4607632C15B4833F {
+		t.Fatalf("packed %X", v)
+	}
+	parsed := new(SpawnPositionPacketCB)
+	if err := parsed.Parse(&buf); err != nil || parsed.Position != pos {
+		t.Fatalf("parsed %+v, %v", parsed.Position, err)
+	}
+
+	for _, p := range []BlockPosition{{-1, -1, -1}, {-33554432, -2048, 33554431}, {0, 2047, -5}} {
+		if got := unpackBlockPosition(p.pack()); got != p {
+			t.Fatalf("%+v unpacked as %+v", p, got)
+		}
+	}
+	if c := (BlockPosition{-1, 64, 17}).Chunk(); c != (ChunkPos{-1, 1}) {
+		t.Fatalf("chunk %+v", c)
+	}
+	if b := (Vec3d{-0.5, 64.9, 3}).Block(); b != (BlockPosition{-1, 64, 3}) {
+		t.Fatalf("block %+v", b)
+	}
+	for _, s := range []SectionPos{{-1, -1, -1}, {-2097152, 524287, 2097151}} {
+		if got := unpackSectionPos(s.pack()); got != s {
+			t.Fatalf("%+v unpacked as %+v", s, got)
+		}
+	}
+}
+
+func TestAngleAndDelta(t *testing.T) {
+	if a := AngleFromDegrees(90); a != 64 {
+		t.Fatalf("90 degrees is %d", a)
+	}
+	if a := AngleFromDegrees(-90); a != 192 {
+		t.Fatalf("-90 degrees is %d", a)
+	}
+	if d := Angle(128).Degrees(); d != 180 {
+		t.Fatalf("angle 128 is %f degrees", d)
+	}
+	if d, ok := MoveDelta(10, 10.5); !ok || d != 2048 || ApplyDelta(10, d) != 10.5 {
+		t.Fatalf("delta %d %v", d, ok)
+	}
+	if _, ok := MoveDelta(0, 8); ok {
+		t.Fatal("delta of 8 blocks fits in int16")
+	}
+}
@@ -1,7 +1,9 @@
 package packets
 
 import (
+	"errors"
 	"io"
+	"io/ioutil"
 
 	bufpool "github.com/libp2p/go-buffer-pool"
 )
@@ -11,7 +13,50 @@ var BufferPool bufpool.BufferPool
 type RawPacket struct {
 	ID         VarInt
 	DataLength VarInt
-	Payload    []byte // includes uncompressed DataLength
+	Payload    []byte // includes uncompressed DataLength, only head of streamed packets
+	rest       *io.LimitedReader
+	compthres  VarInt // threshold of connection packet was read from, writers with other one recompress it
+}
+
+// Uncompressed packet, payload starts with packet id. It can be written with any PacketWriter.
+func NewRawPacket(id VarInt, payload []byte) RawPacket {
+	return RawPacket{ID: id, Payload: payload, compthres: NoCompression}
+}
+
+// Streamed packets are read from connection while being written, see PacketReader.ReadPacket().
+// They are valid only until next ReadPacket or Payload call on reader which returned them.
+func (packet RawPacket) Streamed() bool {
+	return packet.rest != nil
+}
+
+// Length of whole packet, including streamed part
+func (packet RawPacket) Size() int {
+	if packet.rest != nil {
+		return len(packet.Payload) + int(packet.rest.N)
+	}
+	return len(packet.Payload)
+}
+
+// Returns uncompressed packet data after id. Data of streamed packets isn't available,
+// it's consumed from connection when packet is written or parsed.
+func (packet RawPacket) Data() ([]byte, error) {
+	if packet.Streamed() {
+		return nil, errors.New("RawPacket.Data: streamed packet")
+	}
+	r := packetReader{compthres: packet.compthres, maxsize: MaxPacketSize}
+	var header RawPacket
+	if err := r.readHeader(&header, packet.Payload); err != nil {
+		return nil, err
+	}
+	payload, err := r.Payload()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(payload)
+	if r.inflated != nil {
+		BufferPool.Put(r.inflated)
+	}
+	return data, err
 }
 
 func (packet RawPacket) PacketID() VarInt {
@@ -32,7 +77,7 @@ func (packet RawPacket) Serialize(writer io.Writer) (err error) {
 
 // func (packet *RawPacket) ReadRawData(reader io.Reader) (err error) {
 // 	if packet.Payload == nil {
-// 		packet.Payload = make([]byte, MaxPacketSize) // allocate 128k reusable buffer.
+// 		packet.Payload = make([]byte, MaxPacketSize) // allocate reusable buffer.
 // 	}
 // 	packet.Payload = packet.Payload[:cap(packet.Payload)]
 // 	var done, n int
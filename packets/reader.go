@@ -3,13 +3,22 @@ package packets
 import (
 	"bytes"
 	"compress/zlib"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 )
 
 type PacketReader interface {
 	ReadPacket() (RawPacket, error)
 	Payload() (io.Reader, error)
+	// true if next packet is already fully buffered and ReadPacket won't wait for network
+	PacketBuffered() bool
+}
+
+type peeker interface {
+	Buffered() int
+	Peek(n int) ([]byte, error)
 }
 
 type readResetter interface {
@@ -24,106 +33,199 @@ type byter interface {
 
 type packetReader struct {
 	compthres VarInt
+	maxsize   VarInt
 	input     io.Reader
 	byter     byter
 	zliber    readResetter
+	zbyter    dummyByteReader // zliber as io.ByteReader
 	slicer    bytes.Reader
 	payload   io.Reader
+	id        VarInt
+	datalen   VarInt
+	inflated  []byte // pooled buffer with decompressed payload, see Payload()
+	inflater  bytes.Reader
+	head      []byte            // already read part of streamed packet
+	rest      *io.LimitedReader // unread part of streamed packet
 	err       error
 }
 
+// Negative compressThreshold (NoCompression) means connection without compression
 func NewPacketReader(input io.Reader, compressThreshold int) PacketReader {
+	return NewPacketReaderSize(input, compressThreshold, MaxPacketSize)
+}
+
+// Same as NewPacketReader, but packets bigger than maxPacketSize are rejected
+func NewPacketReaderSize(input io.Reader, compressThreshold int, maxPacketSize int) PacketReader {
 	r := &packetReader{
 		compthres: VarInt(compressThreshold),
+		maxsize:   VarInt(maxPacketSize),
 		input:     input,
 	}
 	if br, ok := input.(byter); ok {
 		r.byter = br
 	} else {
-		r.byter = dummyByteReader{Reader: input}
+		r.byter = &dummyByteReader{Reader: input}
 	}
 	return r
 }
 
+// Packets bigger than BufferedPacketSize are streamed, only first StreamHeadSize bytes are read
+// into RawPacket.Payload, rest is read directly from input when packet is written or parsed.
+// Remaining data of streamed packet is discarded by next ReadPacket call.
 func (r *packetReader) ReadPacket() (RawPacket, error) {
 	if r.err != nil {
 		return RawPacket{}, r.err
 	}
 	r.slicer.Reset(nil) // free pointers to last packets, we'll probably be locked on network below
+	if r.inflated != nil {
+		r.inflater.Reset(nil)
+		BufferPool.Put(r.inflated)
+		r.inflated = nil
+	}
+	if r.rest != nil {
+		_, r.err = io.Copy(ioutil.Discard, r.rest)
+		r.head, r.rest = nil, nil
+		if r.err != nil {
+			return RawPacket{}, r.err
+		}
+	}
 
 	var packet_length VarInt
 	packet_length, r.err = ReadVarInt(r.byter)
 	if r.err != nil {
 		return RawPacket{}, r.err
 	}
-	if packet_length > MaxPacketSize {
-		r.err = fmt.Errorf("Packet is too big! %d > %d", packet_length, MaxPacketSize)
+	if packet_length > r.maxsize || packet_length < 0 {
+		r.err = fmt.Errorf("Packet is too big! %d > %d", packet_length, r.maxsize)
 		return RawPacket{}, r.err
 	}
 
+	var head = packet_length
+	if packet_length > BufferedPacketSize {
+		head = StreamHeadSize
+	}
 	var raw = RawPacket{
-		Payload: BufferPool.Get(int(packet_length)),
+		Payload:   BufferPool.Get(int(head)),
+		compthres: r.compthres,
 	}
 	_, r.err = io.ReadFull(r.input, raw.Payload)
 	if r.err != nil {
 		return RawPacket{}, r.err
 	}
-	r.slicer.Reset(raw.Payload)
+	if head < packet_length {
+		r.head = raw.Payload
+		r.rest = &io.LimitedReader{R: r.input, N: int64(packet_length - head)}
+		raw.rest = r.rest
+	}
 
-	if r.compthres > 0 {
-		raw.DataLength, r.err = ReadVarInt(&r.slicer)
-		if r.err != nil {
-			return RawPacket{}, r.err
+	r.err = r.readHeader(&raw, raw.Payload)
+	if r.err != nil {
+		return RawPacket{}, r.err
+	}
+	return raw, nil
+}
+
+// reads DataLength and packet id from data, sets up r.payload
+func (r *packetReader) readHeader(raw *RawPacket, data []byte) (err error) {
+	r.slicer.Reset(data)
+
+	if r.compthres >= 0 {
+		raw.DataLength, err = ReadVarInt(&r.slicer)
+		if err != nil {
+			return
 		}
 		if raw.DataLength > 0 && raw.DataLength < r.compthres {
-			r.err = fmt.Errorf("Compressed data below threshold! %d < %d", raw.DataLength, r.compthres)
-			return RawPacket{}, r.err
+			return fmt.Errorf("Compressed data below threshold! %d < %d", raw.DataLength, r.compthres)
+		}
+		if raw.DataLength > r.maxsize || raw.DataLength < 0 { // it's size of buffer allocated by Payload()
+			return fmt.Errorf("Compressed data is too big! %d > %d", raw.DataLength, r.maxsize)
 		}
 	}
 
 	if raw.DataLength > 0 {
 		if r.zliber == nil {
 			var readCloser io.ReadCloser
-			readCloser, r.err = zlib.NewReader(&r.slicer)
-			if r.err == nil {
+			readCloser, err = zlib.NewReader(&r.slicer)
+			if err == nil {
 				r.zliber = readCloser.(readResetter)
 			}
 		} else {
-			r.err = r.zliber.Reset(&r.slicer, nil)
+			err = r.zliber.Reset(&r.slicer, nil)
 		}
-		if r.err != nil {
-			return RawPacket{}, r.err
+		if err != nil {
+			return
 		}
 		r.payload = r.zliber
+		r.zbyter.Reader = r.zliber
+		raw.ID, err = ReadVarIntFrom(&r.zbyter)
 	} else {
 		r.payload = &r.slicer
+		raw.ID, err = ReadVarIntFrom(&r.slicer)
 	}
-
-	raw.ID, r.err = ReadVarInt(r.payload) // TODO: to alokuje dummyByteReader za kazdym razem
-	if r.err != nil {
-		return RawPacket{}, r.err
+	r.id, r.datalen = raw.ID, raw.DataLength
+	if raw.rest != nil && (err == io.EOF || err == io.ErrUnexpectedEOF) {
+		return fmt.Errorf("Packet id not found in first %d bytes of streamed packet", len(data))
+	}
+	if err != nil {
+		return
 	}
 	if raw.ID > MaxPacketID || raw.ID < 0 {
-		r.err = fmt.Errorf("Invalid packet_id %d", raw.ID)
-		return RawPacket{}, r.err
+		return fmt.Errorf("Invalid packet_id %d", raw.ID)
 	}
-
-	return raw, nil
+	return nil
 }
 
 // this io.Reader is valid between calls to ReadPacket
 func (r *packetReader) Payload() (io.Reader, error) {
-	if r.payload == r.zliber {
-		var buf = bytes.NewBuffer(nil)
-		_, r.err = io.Copy(buf, r.zliber)
+	if r.rest != nil { // streamed packet, it has to be read whole to be parsed
+		var raw RawPacket
+		data := make([]byte, int64(len(r.head))+r.rest.N)
+		copy(data, r.head)
+		_, r.err = io.ReadFull(r.rest, data[len(r.head):])
+		r.head, r.rest = nil, nil
+		if r.err == nil {
+			r.err = r.readHeader(&raw, data)
+		}
 		if r.err != nil {
 			return nil, r.err
 		}
-		r.payload = buf
+	}
+	if r.payload == r.zliber { // decompress to pooled buffer, DataLength includes already read packet id
+		size := int(r.datalen) - varIntSize(r.id)
+		if size < 0 {
+			r.err = fmt.Errorf("Invalid DataLength %d", r.datalen)
+			return nil, r.err
+		}
+		r.inflated = BufferPool.Get(size)
+		_, r.err = io.ReadFull(r.zliber, r.inflated)
+		if r.err == nil { // zlib checksum is verified at the end of stream
+			if _, err := r.zbyter.ReadByte(); err != io.EOF {
+				r.err = fmt.Errorf("Decompressed data longer than DataLength %d: %v", r.datalen, err)
+			}
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		r.inflater.Reset(r.inflated)
+		r.payload = &r.inflater
 	}
 	return r.payload, nil
 }
 
+// works only if input is bufio.Reader (or anything else with Peek), otherwise it's always false
+func (r *packetReader) PacketBuffered() bool {
+	p, ok := r.input.(peeker)
+	if !ok || r.err != nil || r.rest != nil && r.rest.N > 0 {
+		return false
+	}
+	buf, _ := p.Peek(p.Buffered())
+	packet_length, n := binary.Uvarint(buf)
+	if n <= 0 || n > 5 {
+		return false
+	}
+	return uint64(len(buf)-n) >= packet_length
+}
+
 // func (w *packetWriter) HijackInput() io.Reader {
 // 	return w.input
 // }
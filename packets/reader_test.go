@@ -0,0 +1,172 @@
+package packets
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+)
+
+func writePackets(t *testing.T, threshold int, packets ...Packet) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewPacketWriter(&buf, threshold)
+	for _, packet := range packets {
+		if err := w.WritePacket(packet, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// uncompressed packet with given id and length of whole packet (without length prefix)
+func rawFrame(id VarInt, length int) []byte {
+	var buf bytes.Buffer
+	WriteVarInt(&buf, VarInt(length))
+	n := buf.Len()
+	WriteVarInt(&buf, id)
+	buf.Write(make([]byte, length-(buf.Len()-n)))
+	return buf.Bytes()
+}
+
+// > streamed packets, bigger than BufferedPacketSize
+
+func TestStreamedPacket(t *testing.T) {
+	data := make([]byte, 1024*1024)
+	rand.New(rand.NewSource(1)).Read(data) // doesn't compress, so packet stays big with compression enabled
+	big := &PluginMessagePacketCB{Channel: "test:big", Payload: data}
+	next := &CameraPacketCB{ID: 7}
+
+	for _, threshold := range []int{NoCompression, 256} {
+		first := writePackets(t, threshold, big)
+		stream := writePackets(t, threshold, big, next)
+		newReader := func() PacketReader {
+			return NewPacketReader(bufio.NewReaderSize(bytes.NewReader(stream), 64*1024), threshold)
+		}
+
+		// forwarded without parsing
+		r := newReader()
+		raw, err := r.ReadPacket()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !raw.Streamed() || len(raw.Payload) != StreamHeadSize || raw.ID != big.PacketID() {
+			t.Fatalf("threshold %d: packet %02X not streamed, head %d", threshold, raw.ID, len(raw.Payload))
+		}
+		var prefix bytes.Buffer
+		WriteVarInt(&prefix, VarInt(raw.Size()))
+		if raw.Size() != len(first)-prefix.Len() {
+			t.Fatalf("threshold %d: size %d of %d bytes packet", threshold, raw.Size(), len(first))
+		}
+		if r.PacketBuffered() {
+			t.Fatalf("threshold %d: next packet buffered before streamed one was read", threshold)
+		}
+		if out := writePackets(t, threshold, raw); !bytes.Equal(out, first) {
+			t.Fatalf("threshold %d: forwarded packet differs", threshold)
+		}
+		if _, err = ParsePackets(r, &CameraPacketCB{}); err != nil {
+			t.Fatalf("threshold %d: packet after streamed one: %v", threshold, err)
+		}
+
+		// parsed
+		r = newReader()
+		var parsed PluginMessagePacketCB
+		if _, err = ParsePackets(r, &parsed); err != nil {
+			t.Fatal(err)
+		}
+		if parsed.Channel != big.Channel || !bytes.Equal(parsed.Payload, data) {
+			t.Fatalf("threshold %d: parsed packet differs", threshold)
+		}
+		var camera CameraPacketCB
+		if _, err = ParsePackets(r, &camera); err != nil || camera.ID != next.ID {
+			t.Fatalf("threshold %d: packet after parsed streamed one: %v %v", threshold, camera, err)
+		}
+
+		// skipped, remaining data is discarded by next ReadPacket
+		r = newReader()
+		if _, err = r.ReadPacket(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ParsePackets(r, &CameraPacketCB{}); err != nil {
+			t.Fatalf("threshold %d: packet after skipped streamed one: %v", threshold, err)
+		}
+	}
+}
+
+// serverbound packets are limited like in handler (MaxServerboundPacketSize), clientbound ones can have up to MaxPacketSize
+func TestMaxPacketSize(t *testing.T) {
+	const serverbound = 128 * 1024
+
+	for _, c := range []struct {
+		length  int
+		maxsize int
+		ok      bool
+	}{
+		{serverbound, serverbound, true},
+		{serverbound + 1, serverbound, false},
+		{serverbound + 1, MaxPacketSize, true},
+		{MaxPacketSize, MaxPacketSize, true},
+		{MaxPacketSize + 1, MaxPacketSize, false},
+	} {
+		frame := rawFrame(0x17, c.length)
+		r := NewPacketReaderSize(bytes.NewReader(frame), NoCompression, c.maxsize)
+		raw, err := r.ReadPacket()
+		if !c.ok {
+			if err == nil {
+				t.Errorf("%d bytes packet accepted with limit %d", c.length, c.maxsize)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d bytes packet with limit %d: %v", c.length, c.maxsize, err)
+			continue
+		}
+		if out := writePackets(t, NoCompression, raw); !bytes.Equal(out, frame) {
+			t.Errorf("%d bytes packet: forwarded %d bytes", c.length, len(out))
+		}
+
+		r = NewPacketReaderSize(bytes.NewReader(frame), NoCompression, c.maxsize)
+		if _, err = r.ReadPacket(); err != nil {
+			t.Fatal(err)
+		}
+		payload, err := r.Payload()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(payload)
+		if err != nil || len(data) != c.length-1 {
+			t.Errorf("%d bytes packet: read %d bytes of data: %v", c.length, len(data), err)
+		}
+	}
+}
+
+// DataLength is size of buffer allocated for decompressed data, so it can't be bigger than packet size limit
+func TestCompressedDataLengthLimit(t *testing.T) {
+	const maxsize = 128 * 1024
+	var compressed bytes.Buffer
+	z := zlib.NewWriter(&compressed)
+	z.Write([]byte{0x03, 'x'})
+	z.Close()
+
+	for _, datalen := range []VarInt{maxsize + 1, 1<<31 - 1, -1} {
+		var payload bytes.Buffer
+		WriteVarInt(&payload, datalen)
+		payload.Write(compressed.Bytes())
+		var frame bytes.Buffer
+		WriteVarInt(&frame, VarInt(payload.Len()))
+		payload.WriteTo(&frame)
+
+		checkAlloc(t, func() {
+			r := NewPacketReaderSize(bytes.NewReader(frame.Bytes()), 256, maxsize)
+			if _, err := r.ReadPacket(); err == nil {
+				_, err = r.Payload()
+				t.Errorf("DataLength %d accepted, payload error: %v", datalen, err)
+			}
+		})
+	}
+}
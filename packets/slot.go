@@ -0,0 +1,56 @@
+package packets
+
+import (
+	"github.com/Craftserve/potoq/packets/nbt"
+	"io"
+)
+
+// Slot is item stack in 1.16 format, empty slot has Present == false
+type Slot struct {
+	Present bool
+	ItemID  VarInt          `when:"Present==1"`
+	Count   int8            `when:"Present==1"`
+	NBT     nbt.TagCompound `when:"Present==1"` // nil if item has no NBT
+}
+
+func (slot *Slot) Parse(reader io.Reader) error {
+	return ReadMinecraftStruct(reader, slot)
+}
+
+func (slot *Slot) Serialize(writer io.Writer) error {
+	return WriteMinecraftStruct(writer, slot)
+}
+
+// Returns custom name of item (json chat) from display.Name tag
+func (slot *Slot) DisplayName() (string, bool) {
+	name, ok := slot.display()["Name"].(nbt.TagString)
+	return string(name), ok
+}
+
+// Returns lines of lore (json chat) from display.Lore tag
+func (slot *Slot) Lore() []string {
+	list, _ := slot.display()["Lore"].(nbt.TagList)
+	return tagStrings(list)
+}
+
+// Returns pages of writable (plain text) or written (json chat) book
+func (slot *Slot) BookPages() []string {
+	list, _ := slot.NBT["pages"].(nbt.TagList)
+	return tagStrings(list)
+}
+
+func (slot *Slot) display() nbt.TagCompound {
+	display, _ := slot.NBT["display"].(nbt.TagCompound)
+	return display
+}
+
+// string elements of list, other tags are skipped
+func tagStrings(list nbt.TagList) []string {
+	var lines []string
+	for _, tag := range list.Elements {
+		if s, ok := tag.(nbt.TagString); ok {
+			lines = append(lines, string(s))
+		}
+	}
+	return lines
+}
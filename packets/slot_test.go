@@ -0,0 +1,41 @@
+package packets
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/Craftserve/potoq/packets/nbt"
+)
+
+func TestSlotHelpers(t *testing.T) {
+	slot := &Slot{Present: true, ItemID: 942, Count: 1, NBT: nbt.TagCompound{
+		"display": nbt.TagCompound{
+			"Name": nbt.TagString(`{"text":"Miecz"}`),
+			"Lore": nbt.TagList{ElementType: nbt.TagTypeString, Elements: []nbt.Tag{nbt.TagString(`"a"`), nbt.TagString(`"b"`)}},
+		},
+	}}
+	var buf bytes.Buffer
+	if err := slot.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	parsed := new(Slot)
+	if err := parsed.Parse(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := parsed.DisplayName(); !ok || name != `{"text":"Miecz"}` {
+		t.Fatalf("DisplayName: %q %v", name, ok)
+	}
+	if lore := parsed.Lore(); !reflect.DeepEqual(lore, []string{`"a"`, `"b"`}) {
+		t.Fatalf("Lore: %q", lore)
+	}
+
+	empty := new(Slot)
+	if _, ok := empty.DisplayName(); ok || empty.Lore() != nil || empty.BookPages() != nil {
+		t.Fatal("empty slot has display data")
+	}
+	buf.Reset()
+	if err := empty.Serialize(&buf); err != nil || !bytes.Equal(buf.Bytes(), []byte{0}) {
+		t.Fatalf("empty slot encoded as %x, %v", buf.Bytes(), err)
+	}
+}
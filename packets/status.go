@@ -89,7 +89,7 @@ func (packet *StatusPingPacketCB) Serialize(writer io.Writer) error {
 }
 
 func (packet *StatusPingPacketCB) Direction() Direction {
-	return ServerBound
+	return ClientBound
 }
 
 type StatusPingPacketSB struct {
@@ -109,5 +109,5 @@ func (packet *StatusPingPacketSB) Serialize(writer io.Writer) error {
 }
 
 func (packet *StatusPingPacketSB) Direction() Direction {
-	return ClientBound
+	return ServerBound
 }
@@ -1,139 +1,215 @@
-package packets
-
-import (
-	"bytes"
-	"compress/zlib"
-	"encoding/binary"
-	"io"
-)
-
-type PacketWriter interface {
-	WritePacket(packet Packet, flush bool) error
-	Flush() error
-}
-
-type flusher interface {
-	Flush() error
-}
-
-type packetWriter struct {
-	err       error
-	out       io.Writer
-	flusher   flusher
-	buf       bytes.Buffer
-	zliber    *zlib.Writer
-	compthres int
-}
-
-func NewPacketWriter(out io.Writer, compress_threshold int) PacketWriter {
-	w := &packetWriter{
-		out:       out,
-		buf:       *bytes.NewBuffer(BufferPool.Get(MaxPacketSize)),
-		compthres: compress_threshold,
-	}
-	if flusher, ok := out.(flusher); ok {
-		w.flusher = flusher
-	}
-	return w
-}
-
-func (w *packetWriter) WritePacket(packet Packet, flush bool) error {
-	if w.err != nil {
-		return w.err
-	}
-
-	if raw, is_raw := packet.(RawPacket); is_raw {
-		w.err = WriteVarInt(w.out, VarInt(len(raw.Payload))) // packet length
-		if w.err != nil {
-			return w.err
-		}
-		_, w.err = w.out.Write(raw.Payload)
-		if flush {
-			return w.Flush()
-		}
-		return w.err
-	}
-
-	// serialize packet to buffer
-	w.buf.Reset()
-	w.err = WriteVarInt(&w.buf, packet.PacketID())
-	if w.err != nil {
-		return w.err
-	}
-	w.err = packet.Serialize(&w.buf) // dodac tutaj sprawdzanie czy pakiet implementuje MarshalPacket, jak nie to structy
-	if w.err != nil {
-		return w.err
-	}
-
-	if w.compthres < 1 {
-		// compression disabled
-		w.err = WriteVarInt(w.out, VarInt(w.buf.Len()))
-		if w.err != nil {
-			return w.err
-		}
-		_, w.err = io.Copy(w.out, &w.buf)
-		if flush {
-			return w.Flush()
-		}
-		return w.err
-	}
-
-	// > compression enabled
-	// packet header changes after set compression packet
-	var datalen int = 0
-	if w.buf.Len() >= w.compthres {
-		// only packets above given threshold are compressed
-		// smalled packets have datalen=0 which means that data is sent raw
-		datalen = w.buf.Len()
-		// fmt.Print("datalen", w.buf.Len())
-
-		if w.zliber == nil {
-			w.zliber = zlib.NewWriter(&w.buf)
-		} else {
-			w.zliber.Reset(&w.buf)
-		}
-
-		_, w.err = io.CopyN(w.zliber, &w.buf, int64(datalen))
-		if w.err != nil {
-			return w.err
-		}
-
-		w.err = w.zliber.Close()
-		if w.err != nil {
-			return w.err
-		}
-	}
-
-	var datalen_buf [binary.MaxVarintLen64]byte
-	datalen_size := binary.PutUvarint(datalen_buf[:], uint64(uint32(int64(datalen))))
-
-	w.err = WriteVarInt(w.out, VarInt(datalen_size+w.buf.Len())) // packet length
-	if w.err != nil {
-		return w.err
-	}
-
-	_, w.err = w.out.Write(datalen_buf[:datalen_size])
-	if w.err != nil {
-		return w.err
-	}
-
-	_, w.err = io.Copy(w.out, &w.buf)
-	if flush {
-		return w.Flush()
-	}
-	return w.err
-}
-
-func (w *packetWriter) Flush() error {
-	if w.err != nil {
-		return w.err
-	}
-	if w.flusher != nil {
-		w.err = w.flusher.Flush()
-	}
-	return w.err
-}
-
-func (w *packetWriter) HijackOutput() io.Writer {
-	return w.out
-}
+package packets
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+type PacketWriter interface {
+	WritePacket(packet Packet, flush bool) error
+	Flush() error
+}
+
+type flusher interface {
+	Flush() error
+}
+
+// zlib level used by packet writers created after change
+var CompressionLevel = zlib.DefaultCompression
+
+type packetWriter struct {
+	err       error
+	out       io.Writer
+	flusher   flusher
+	buf       bytes.Buffer
+	zliber    *zlib.Writer
+	unzliber  readResetter // used to recompress raw packets
+	compthres int
+	level     int
+
+	// buffers for packet header, local arrays passed to out.Write would escape to heap
+	length_buf  [binary.MaxVarintLen64]byte
+	datalen_buf [binary.MaxVarintLen64]byte
+}
+
+// Negative compress_threshold (NoCompression) means connection without compression
+func NewPacketWriter(out io.Writer, compress_threshold int) PacketWriter {
+	w := &packetWriter{
+		out:       out,
+		buf:       *bytes.NewBuffer(BufferPool.Get(BufferedPacketSize)),
+		compthres: compress_threshold,
+		level:     CompressionLevel,
+	}
+	if flusher, ok := out.(flusher); ok {
+		w.flusher = flusher
+	}
+	return w
+}
+
+func (w *packetWriter) WritePacket(packet Packet, flush bool) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	raw, is_raw := packet.(RawPacket)
+	if is_raw && (raw.compthres == VarInt(w.compthres) || raw.compthres < 0 && w.compthres < 0) {
+		w.err = w.writeVarInt(VarInt(raw.Size())) // packet length
+		if w.err != nil {
+			return w.err
+		}
+		_, w.err = w.out.Write(raw.Payload)
+		if w.err == nil && raw.rest != nil {
+			var n int64
+			n, w.err = io.Copy(w.out, raw.rest)
+			if w.err == nil && raw.rest.N > 0 {
+				w.err = fmt.Errorf("Streamed packet truncated after %d bytes", len(raw.Payload)+int(n))
+			}
+		}
+		if flush {
+			return w.Flush()
+		}
+		return w.err
+	}
+
+	// serialize packet to buffer
+	w.buf.Reset()
+	if is_raw { // raw packet from connection with other compression threshold
+		w.err = w.decompressRaw(raw)
+	} else {
+		w.err = WriteVarInt(&w.buf, packet.PacketID())
+		if w.err == nil {
+			w.err = packet.Serialize(&w.buf)
+		}
+	}
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.compthres < 0 {
+		// compression disabled
+		w.err = w.writeVarInt(VarInt(w.buf.Len()))
+		if w.err != nil {
+			return w.err
+		}
+		_, w.err = io.Copy(w.out, &w.buf)
+		if flush {
+			return w.Flush()
+		}
+		return w.err
+	}
+
+	// > compression enabled
+	// packet header changes after set compression packet
+	var datalen int = 0
+	if w.buf.Len() >= w.compthres {
+		// only packets above given threshold are compressed
+		// smalled packets have datalen=0 which means that data is sent raw
+		datalen = w.buf.Len()
+		// fmt.Print("datalen", w.buf.Len())
+
+		if w.zliber == nil {
+			w.zliber, w.err = zlib.NewWriterLevel(&w.buf, w.level)
+			if w.err != nil {
+				return w.err
+			}
+		} else {
+			w.zliber.Reset(&w.buf)
+		}
+
+		_, w.err = io.CopyN(w.zliber, &w.buf, int64(datalen))
+		if w.err != nil {
+			return w.err
+		}
+
+		w.err = w.zliber.Close()
+		if w.err != nil {
+			return w.err
+		}
+	}
+
+	datalen_size := binary.PutUvarint(w.datalen_buf[:], uint64(uint32(int64(datalen))))
+
+	w.err = w.writeVarInt(VarInt(datalen_size + w.buf.Len())) // packet length
+	if w.err != nil {
+		return w.err
+	}
+
+	_, w.err = w.out.Write(w.datalen_buf[:datalen_size])
+	if w.err != nil {
+		return w.err
+	}
+
+	_, w.err = io.Copy(w.out, &w.buf)
+	if flush {
+		return w.Flush()
+	}
+	return w.err
+}
+
+// writes uncompressed id and data of raw packet to w.buf
+func (w *packetWriter) decompressRaw(raw RawPacket) (err error) {
+	data := raw.Payload
+	if raw.rest != nil { // streamed packet, we need it whole
+		data = make([]byte, raw.Size())
+		copy(data, raw.Payload)
+		_, err = io.ReadFull(raw.rest, data[len(raw.Payload):])
+		if err != nil {
+			return
+		}
+	}
+	if raw.compthres < 0 {
+		_, err = w.buf.Write(data)
+		return
+	}
+
+	r := bytes.NewReader(data)
+	_, err = ReadVarInt(r) // DataLength, already in raw
+	if err != nil {
+		return
+	}
+	if raw.DataLength == 0 {
+		_, err = r.WriteTo(&w.buf)
+		return
+	}
+
+	if w.unzliber == nil {
+		var readCloser io.ReadCloser
+		readCloser, err = zlib.NewReader(r)
+		if err == nil {
+			w.unzliber = readCloser.(readResetter)
+		}
+	} else {
+		err = w.unzliber.Reset(r, nil)
+	}
+	if err != nil {
+		return
+	}
+	n, err := io.Copy(&w.buf, w.unzliber)
+	if err == nil && n != int64(raw.DataLength) {
+		err = fmt.Errorf("Bad DataLength of recompressed packet %d != %d", n, raw.DataLength)
+	}
+	return
+}
+
+func (w *packetWriter) writeVarInt(c VarInt) error {
+	n := binary.PutUvarint(w.length_buf[:], uint64(uint32(c)))
+	_, err := w.out.Write(w.length_buf[:n])
+	return err
+}
+
+func (w *packetWriter) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.flusher != nil {
+		w.err = w.flusher.Flush()
+	}
+	return w.err
+}
+
+func (w *packetWriter) HijackOutput() io.Writer {
+	return w.out
+}
@@ -0,0 +1,52 @@
+package packets
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// raw packets are forwarded as they are between connections with same threshold,
+// other ones are decompressed and compressed again like packets serialized by writer
+func TestWriteRawPacketThresholds(t *testing.T) {
+	big := &PluginMessagePacketCB{Channel: "test:big", Payload: []byte(strings.Repeat("compressible ", 100))}
+	small := &CameraPacketCB{ID: 7}
+	thresholds := []int{NoCompression, 0, 64, 256, 4096}
+
+	for _, from := range thresholds {
+		for _, to := range thresholds {
+			for _, packet := range []Packet{big, small} {
+				input := writePackets(t, from, packet)
+				raw, err := NewPacketReader(bytes.NewReader(input), from).ReadPacket()
+				if err != nil {
+					t.Fatalf("%d -> %d: %v", from, to, err)
+				}
+				want := writePackets(t, to, packet)
+				if out := writePackets(t, to, raw); !bytes.Equal(out, want) {
+					t.Errorf("%d -> %d: %T written as %x, expected %x", from, to, packet, out, want)
+				}
+			}
+		}
+	}
+}
+
+// vanilla server with network-compression-threshold=0 compresses all packets
+func TestCompressAllPackets(t *testing.T) {
+	data := writePackets(t, 0, &CameraPacketCB{ID: 7})
+	r := NewPacketReader(bytes.NewReader(data), 0)
+	raw, err := r.ReadPacket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.DataLength == 0 {
+		t.Fatalf("packet not compressed: %x", data)
+	}
+	var camera CameraPacketCB
+	payload, err := r.Payload()
+	if err == nil {
+		err = camera.Parse(payload)
+	}
+	if err != nil || camera.ID != 7 {
+		t.Fatalf("parsed %v: %v", camera, err)
+	}
+}
@@ -23,7 +23,26 @@ var PingHandler func(packet *packets.HandshakePacket) packets.ServerStatus
 var PreLoginHandler func(handler *Handler) error
 var LoginHandler func(handler *Handler, login_err error) error
 
+// Opens connections to upstreams, can be replaced eg. to connect through other network or in tests
+var DialUpstream = func(addr string) (net.Conn, error) {
+	return net.Dial("tcp", addr)
+}
+
+// Settings of players connected through single listener, see ServeListener
+type ListenerConfig struct {
+	CompressThreshold int // negative disables compression (eg. for players in local network), 0 compresses all packets
+}
+
+var DefaultListenerConfig = ListenerConfig{
+	CompressThreshold: packets.CompressThreshold,
+}
+
 func Serve(listener *net.TCPListener) {
+	ServeListener(listener, DefaultListenerConfig)
+}
+
+// Same as Serve, but with custom settings. Can be called for many listeners at once.
+func ServeListener(listener *net.TCPListener, config ListenerConfig) {
 	err := LoadUpstreams("upstreams.yml")
 	if err != nil {
 		panic(err)
@@ -41,6 +60,7 @@ func Serve(listener *net.TCPListener) {
 		}
 
 		handler := NewHandler(socket)
+		handler.CompressThreshold = config.CompressThreshold
 		go handler.Handle()
 	}
 }
@@ -52,7 +72,7 @@ func LoadUpstreams(fName string) (err error) {
 		return
 	}
 
-	var up = make(map[string]string)
+	var up = make(map[string]upstreamConfig)
 	err = yaml.Unmarshal(data, up)
 	if err != nil {
 		return
@@ -60,13 +80,13 @@ func LoadUpstreams(fName string) (err error) {
 
 	ups := make(map[string]*ReconnectCommand)
 
-	for name, addr := range up {
-		if _, err = net.ResolveTCPAddr("tcp4", addr); err != nil {
-			err = fmt.Errorf("RegisterUpstream %q %q tcp error %s", name, addr, err)
+	for name, cfg := range up {
+		if _, err = net.ResolveTCPAddr("tcp4", cfg.Addr); err != nil {
+			err = fmt.Errorf("RegisterUpstream %q %q tcp error %s", name, cfg.Addr, err)
 			return
 		}
 
-		ups[name] = &ReconnectCommand{Name: name, Addr: addr}
+		ups[name] = &ReconnectCommand{Name: name, Addr: cfg.Addr, CompressThreshold: cfg.Compression}
 	}
 
 	if len(ups) <= 0 {
@@ -80,3 +100,29 @@ func LoadUpstreams(fName string) (err error) {
 
 	return
 }
+
+// upstreams.yml entry, either "name: address" or mapping with address and expected compression threshold:
+//
+//	local:
+//	  addr: localhost:25566
+//	  compression: -1
+type upstreamConfig struct {
+	Addr        string `yaml:"addr"`
+	Compression *int   `yaml:"compression"`
+}
+
+func (cfg *upstreamConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&cfg.Addr); err == nil {
+		return nil
+	}
+	type plain upstreamConfig
+	return unmarshal((*plain)(cfg))
+}
+
+// all negative thresholds mean disabled compression, packets package uses NoCompression for that
+func normalizeThreshold(threshold int) int {
+	if threshold < 0 {
+		return packets.NoCompression
+	}
+	return threshold
+}
@@ -0,0 +1,169 @@
+// Package potoqtest runs potoq.Handler over in-memory connections (net.Pipe), with test player on one side
+// and test upstream server on the other, so filters can be tested without Minecraft client and server.
+//
+//	upstream := potoqtest.NewUpstream("lobby")
+//	defer upstream.Close()
+//	player := potoqtest.Connect(t, upstream, "tester")
+//	defer player.Close()
+//	server := upstream.Accept(t)
+//	player.Send(t, &packets.ChatMessagePacketSB{Message: "hello"})
+//	server.Expect(t, &packets.ChatMessagePacketSB{})
+//
+// Players log in in offline mode, so neither encryption nor Mojang API is used. PreLoginHandler and
+// LoginHandler accepting them are installed if potoq doesn't have them set.
+package potoqtest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Craftserve/potoq/packets"
+)
+
+// How long Receive, Expect and Accept wait
+var Timeout = 5 * time.Second
+
+// One end of connection with handler in PLAY state. Packets from handler are read in background,
+// so handler never blocks on writes to test connection.
+type Conn struct {
+	conn      net.Conn
+	direction packets.Direction // of received packets
+	writer    packets.PacketWriter
+	writeLock sync.Mutex
+	received  chan receivedPacket
+	err       error // why received was closed
+}
+
+type receivedPacket struct {
+	id   packets.VarInt
+	data []byte
+}
+
+func newConn(conn net.Conn, direction packets.Direction, reader packets.PacketReader, writer packets.PacketWriter) *Conn {
+	c := &Conn{
+		conn:      conn,
+		direction: direction,
+		writer:    writer,
+		received:  make(chan receivedPacket, 1024),
+	}
+	go c.read(reader)
+	return c
+}
+
+func (c *Conn) read(reader packets.PacketReader) {
+	defer close(c.received)
+	for {
+		raw, err := reader.ReadPacket()
+		if err != nil {
+			c.err = err
+			return
+		}
+		payload, err := reader.Payload()
+		if err != nil {
+			c.err = err
+			return
+		}
+		data, err := ioutil.ReadAll(payload)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.received <- receivedPacket{raw.ID, data}
+	}
+}
+
+// Writes packet to handler and flushes it
+func (c *Conn) Send(t testing.TB, packet packets.Packet) {
+	t.Helper()
+	c.writeLock.Lock()
+	err := c.writer.WritePacket(packet, true)
+	c.writeLock.Unlock()
+	if err != nil {
+		t.Fatalf("potoqtest: send %T: %v", packet, err)
+	}
+}
+
+// waits for next packet from handler, error is returned after deadline or when connection is closed
+func (c *Conn) next(deadline <-chan time.Time) (receivedPacket, error) {
+	select {
+	case p, ok := <-c.received:
+		if !ok {
+			return p, fmt.Errorf("connection closed: %v", c.err)
+		}
+		return p, nil
+	case <-deadline:
+		return receivedPacket{}, fmt.Errorf("no packet after %s", Timeout)
+	}
+}
+
+// whole data has to be consumed by Parse
+func (p receivedPacket) parse(packet packets.Packet) error {
+	reader := bytes.NewReader(p.data)
+	if err := packet.Parse(reader); err != nil {
+		return fmt.Errorf("parse of %T: %w", packet, err)
+	}
+	if reader.Len() > 0 {
+		return fmt.Errorf("%d bytes left after %T", reader.Len(), packet)
+	}
+	return nil
+}
+
+// Waits for next packet from handler and parses it
+func (c *Conn) Receive(t testing.TB) packets.Packet {
+	t.Helper()
+	p, err := c.next(time.After(Timeout))
+	if err != nil {
+		t.Fatalf("potoqtest: receive %s: %v", c.direction, err)
+	}
+	packet := packets.NewPacket(p.id, packets.PLAY, c.direction)
+	if packet == nil {
+		t.Fatalf("potoqtest: receive %s: unknown packet %02X", c.direction, p.id)
+	}
+	if err = p.parse(packet); err != nil {
+		t.Fatalf("potoqtest: receive %s: %v", c.direction, err)
+	}
+	return packet
+}
+
+// Skips packets until one with id of want arrives and parses it into want
+func (c *Conn) Expect(t testing.TB, want packets.Packet) {
+	t.Helper()
+	deadline := time.After(Timeout)
+	for {
+		p, err := c.next(deadline)
+		if err != nil {
+			t.Fatalf("potoqtest: expected %T: %v", want, err)
+		}
+		if p.id == want.PacketID() {
+			if err = p.parse(want); err != nil {
+				t.Fatalf("potoqtest: expected %T: %v", want, err)
+			}
+			return
+		}
+	}
+}
+
+// Skips packets until handler closes connection
+func (c *Conn) ExpectClosed(t testing.TB) {
+	t.Helper()
+	deadline := time.After(Timeout)
+	for {
+		select {
+		case _, ok := <-c.received:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("potoqtest: %s connection still open after %s", c.direction, Timeout)
+		}
+	}
+}
+
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,240 @@
+package potoqtest
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Craftserve/potoq"
+	"github.com/Craftserve/potoq/packets"
+	"github.com/google/uuid"
+)
+
+var setupOnce sync.Once
+
+var upstreamsLock sync.Mutex
+var upstreams = make(map[string]*Upstream) // by Addr
+
+// installs dialer of test upstreams and login handlers for offline players
+func setup() {
+	setupOnce.Do(func() {
+		dial := potoq.DialUpstream
+		potoq.DialUpstream = func(addr string) (net.Conn, error) {
+			upstreamsLock.Lock()
+			upstream := upstreams[addr]
+			upstreamsLock.Unlock()
+			if upstream == nil {
+				return dial(addr)
+			}
+			client, server := net.Pipe()
+			select {
+			case upstream.conns <- server:
+				return client, nil
+			default:
+				return nil, fmt.Errorf("potoqtest: too many connections to %s waiting for Accept", upstream.Name)
+			}
+		}
+		if potoq.PreLoginHandler == nil {
+			potoq.PreLoginHandler = func(handler *potoq.Handler) error { return nil }
+		}
+		if potoq.LoginHandler == nil {
+			potoq.LoginHandler = func(handler *potoq.Handler, login_err error) error {
+				if login_err == potoq.ErrUnauthenticated {
+					return nil
+				}
+				return login_err
+			}
+		}
+	})
+}
+
+// > player
+
+// Player side of handler
+type Player struct {
+	*Conn
+	Handler *potoq.Handler
+	UUID    uuid.UUID
+}
+
+// Connects player to new handler and logs in as nickname in offline mode. Handler connects to upstream
+// afterwards, its connection has to be taken with upstream.Accept. Setup functions are called before
+// handler starts, eg. to add packet filters or change CompressThreshold.
+func Connect(t testing.TB, upstream *Upstream, nickname string, setup_funcs ...func(*potoq.Handler)) *Player {
+	t.Helper()
+	setup()
+	client, server := net.Pipe()
+	handler := potoq.NewHandler(server)
+	handler.Authenticator = nil
+	handler.UpstreamName = upstream.Name
+	handler.DownstreamAddr = "127.0.0.1"
+	for _, f := range setup_funcs {
+		f(handler)
+	}
+	go handler.Handle()
+
+	client.SetDeadline(time.Now().Add(Timeout))
+	defer client.SetDeadline(time.Time{})
+	fail := func(err error) *Player {
+		client.Close()
+		t.Fatalf("potoqtest: login of %s: %v", nickname, err)
+		return nil
+	}
+	w := packets.NewPacketWriter(client, packets.NoCompression)
+	handshake := &packets.HandshakePacket{
+		Protocol:  packets.ProtocolVersion,
+		Host:      "localhost",
+		Port:      25565,
+		NextState: packets.VarInt(packets.LOGIN),
+	}
+	if err := w.WritePacket(handshake, false); err != nil {
+		return fail(err)
+	}
+	if err := w.WritePacket(&packets.LoginStartPacket{Nickname: nickname}, true); err != nil {
+		return fail(err)
+	}
+
+	r := packets.NewPacketReader(client, packets.NoCompression)
+	var success packets.LoginSuccessPacket
+	for {
+		packet, err := packets.ParsePackets(r, &packets.LoginCompressionPacket{}, &packets.LoginKickPacket{}, &success)
+		if err != nil {
+			return fail(err)
+		}
+		switch p := packet.(type) {
+		case *packets.LoginCompressionPacket:
+			r = packets.NewPacketReader(client, int(p.Threshold))
+			w = packets.NewPacketWriter(client, int(p.Threshold))
+			continue
+		case *packets.LoginKickPacket:
+			return fail(fmt.Errorf("kicked: %s", p.Message))
+		}
+		break
+	}
+	return &Player{
+		Conn:    newConn(client, packets.ClientBound, r, w),
+		Handler: handler,
+		UUID:    success.UID,
+	}
+}
+
+// Disconnects player and waits until handler ends, so nickname can be used again
+func (p *Player) Close() error {
+	err := p.Conn.Close()
+	select {
+	case <-p.Handler.Dead():
+	case <-time.After(Timeout):
+		return fmt.Errorf("potoqtest: handler of %s still running after %s", p.Handler.Nickname, Timeout)
+	}
+	return err
+}
+
+// > upstream
+
+// Upstream server registered in potoq.UpstreamServerMap, its connections are in-memory pipes
+type Upstream struct {
+	Name string
+	Addr string
+	// compression threshold sent to handler, negative (default) disables compression, 0 compresses all packets
+	Threshold int
+
+	conns chan net.Conn
+}
+
+// Server side of handler's upstream connection
+type UpstreamConn struct {
+	*Conn
+	Handshake packets.HandshakePacket // Host includes forwarding data
+	Nickname  string
+	// BungeeCord forwarding data from handshake
+	ForwardedIP string
+	UUID        uuid.UUID
+}
+
+func NewUpstream(name string) *Upstream {
+	setup()
+	u := &Upstream{
+		Name:      name,
+		Addr:      "potoqtest/" + name,
+		Threshold: packets.NoCompression,
+		conns:     make(chan net.Conn, 16),
+	}
+	upstreamsLock.Lock()
+	upstreams[u.Addr] = u
+	upstreamsLock.Unlock()
+
+	potoq.UpstreamLock.Lock()
+	if potoq.UpstreamServerMap == nil {
+		potoq.UpstreamServerMap = make(map[string]*potoq.ReconnectCommand)
+	}
+	potoq.UpstreamServerMap[name] = &potoq.ReconnectCommand{Name: name, Addr: u.Addr}
+	potoq.UpstreamLock.Unlock()
+	return u
+}
+
+// Unregisters upstream, handlers can't connect to it anymore
+func (u *Upstream) Close() {
+	upstreamsLock.Lock()
+	delete(upstreams, u.Addr)
+	upstreamsLock.Unlock()
+	potoq.UpstreamLock.Lock()
+	delete(potoq.UpstreamServerMap, u.Name)
+	potoq.UpstreamLock.Unlock()
+}
+
+// Waits for handler's connection, it isn't logged in yet. Can be served by testserver.Server.
+func (u *Upstream) AcceptConn(t testing.TB) net.Conn {
+	t.Helper()
+	select {
+	case conn := <-u.conns:
+		return conn
+	case <-time.After(Timeout):
+		t.Fatalf("potoqtest: no connection to %s after %s", u.Name, Timeout)
+		return nil
+	}
+}
+
+// Waits for handler's connection and logs it in
+func (u *Upstream) Accept(t testing.TB) *UpstreamConn {
+	t.Helper()
+	conn := u.AcceptConn(t)
+	conn.SetDeadline(time.Now().Add(Timeout))
+	defer conn.SetDeadline(time.Time{})
+	fail := func(err error) *UpstreamConn {
+		conn.Close()
+		t.Fatalf("potoqtest: login to %s: %v", u.Name, err)
+		return nil
+	}
+
+	up := &UpstreamConn{}
+	r := packets.NewPacketReader(conn, packets.NoCompression)
+	if _, err := packets.ParsePackets(r, &up.Handshake); err != nil {
+		return fail(err)
+	}
+	if parts := strings.Split(up.Handshake.Host, "\x00"); len(parts) >= 3 {
+		up.ForwardedIP = parts[1]
+		up.UUID, _ = uuid.Parse(parts[2])
+	}
+	var login_start packets.LoginStartPacket
+	if _, err := packets.ParsePackets(r, &login_start); err != nil {
+		return fail(err)
+	}
+	up.Nickname = login_start.Nickname
+
+	w := packets.NewPacketWriter(conn, packets.NoCompression)
+	if u.Threshold >= 0 {
+		if err := w.WritePacket(&packets.LoginCompressionPacket{Threshold: packets.VarInt(u.Threshold)}, true); err != nil {
+			return fail(err)
+		}
+		r = packets.NewPacketReader(conn, u.Threshold)
+		w = packets.NewPacketWriter(conn, u.Threshold)
+	}
+	if err := w.WritePacket(&packets.LoginSuccessPacket{UID: up.UUID, Username: up.Nickname}, true); err != nil {
+		return fail(err)
+	}
+	up.Conn = newConn(conn, packets.ServerBound, r, w)
+	return up
+}
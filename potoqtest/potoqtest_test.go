@@ -0,0 +1,63 @@
+package potoqtest
+
+import (
+	"testing"
+
+	"github.com/Craftserve/potoq"
+	"github.com/Craftserve/potoq/packets"
+)
+
+func TestSession(t *testing.T) {
+	for _, threshold := range []int{packets.NoCompression, 0, 64} {
+		upstream := NewUpstream("lobby")
+		upstream.Threshold = threshold
+		player := Connect(t, upstream, "tester", func(handler *potoq.Handler) {
+			handler.CompressThreshold = threshold
+			handler.AddPacketFilter(&packets.ChatMessagePacketSB{}, func(handler *potoq.Handler, packet packets.Packet) error {
+				if packet.(*packets.ChatMessagePacketSB).Message == "drop" {
+					return potoq.ErrDropPacket
+				}
+				return nil
+			})
+		})
+		server := upstream.Accept(t)
+		if server.Nickname != "tester" || server.UUID != potoq.OfflinePlayerUUID("tester") || server.UUID != player.UUID {
+			t.Fatalf("logged in as %s %s, player %s", server.Nickname, server.UUID, player.UUID)
+		}
+		if server.ForwardedIP != "127.0.0.1" {
+			t.Fatalf("forwarded ip %q", server.ForwardedIP)
+		}
+
+		player.Send(t, &packets.ChatMessagePacketSB{Message: "drop"})
+		player.Send(t, &packets.ChatMessagePacketSB{Message: "hello"})
+		var chat packets.ChatMessagePacketSB
+		server.Expect(t, &chat)
+		if chat.Message != "hello" {
+			t.Fatalf("upstream got %q", chat.Message)
+		}
+
+		server.Send(t, &packets.KeepAlivePacketCB{ID: 42})
+		if keepalive, ok := player.Receive(t).(*packets.KeepAlivePacketCB); !ok || keepalive.ID != 42 {
+			t.Fatalf("player got %#v", keepalive)
+		}
+
+		server.Send(t, packets.NewIngameKickTxt("bye"))
+		server.Close()
+		player.Expect(t, &packets.KickPacketCB{})
+		player.ExpectClosed(t)
+		if err := player.Close(); err != nil {
+			t.Fatal(err)
+		}
+		upstream.Close()
+	}
+}
+
+func TestUnknownUpstream(t *testing.T) {
+	upstream := NewUpstream("gone")
+	upstream.Close()
+	player := Connect(t, upstream, "tester2")
+	var kick packets.KickPacketCB
+	player.Expect(t, &kick)
+	player.ExpectClosed(t)
+	player.Close()
+}
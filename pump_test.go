@@ -0,0 +1,113 @@
+package potoq
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Craftserve/potoq/packets"
+	"gopkg.in/tomb.v1"
+)
+
+// > benchmarks, synthetic load of 1000 players each sending stream of chat packets through its own handler
+
+const benchPlayers = 1000
+
+func benchStream(b *testing.B, n int) []byte {
+	var buf bytes.Buffer
+	w := packets.NewPacketWriter(&buf, packets.CompressThreshold)
+	for i := 0; i < n; i++ {
+		err := w.WritePacket(&packets.ChatMessagePacketSB{Message: "/benchmark message"}, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func benchHandler(filtered bool) *Handler {
+	handler := &Handler{
+		Nickname:    "bench",
+		FlushPolicy: DefaultFlushPolicy,
+		UpstreamW:   packets.NewPacketWriter(bufio.NewWriter(ioutil.Discard), packets.CompressThreshold),
+	}
+	if filtered {
+		handler.AddReadOnlyPacketFilter(&packets.ChatMessagePacketSB{}, func(*Handler, packets.Packet) error {
+			return nil
+		})
+	}
+	return handler
+}
+
+func benchmarkForwarding(b *testing.B, filtered bool, forward func(handler *Handler, reader packets.PacketReader)) {
+	per_player := b.N/benchPlayers + 1
+	stream := benchStream(b, per_player)
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < benchPlayers; i++ {
+		handler := benchHandler(filtered)
+		reader := packets.NewPacketReader(bufio.NewReader(bytes.NewReader(stream)), packets.CompressThreshold)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			forward(handler, reader)
+		}()
+	}
+	wg.Wait()
+
+	b.StopTimer()
+	b.ReportMetric(float64(time.Since(start).Nanoseconds())/float64(per_player*benchPlayers), "ns/packet")
+}
+
+func pumpForward(handler *Handler, reader packets.PacketReader) {
+	tmb := &tomb.Tomb{}
+	handler.pump(reader, packets.ServerBound, tmb)
+}
+
+// replica of forwarding used before pumps: read_packets goroutine feeding MainLoop through channel
+func channelForward(handler *Handler, reader packets.PacketReader) {
+	var queued int32
+	packet_chan := make(chan queuedPacket, 16)
+	go func() {
+		defer close(packet_chan)
+		for {
+			packet, err := handler.readPacket(reader, packets.ServerBound)
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&queued, 1)
+			packet_chan <- packet
+		}
+	}()
+
+	var state flushState
+	for packet := range packet_chan {
+		flush := state.add(&handler.FlushPolicy, packet.size(), int(atomic.AddInt32(&queued, -1)), time.Now())
+		if err := handler.handlePacket(packet, packets.ServerBound, handler.UpstreamW, flush); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func BenchmarkForwardChannels(b *testing.B) {
+	benchmarkForwarding(b, false, channelForward)
+}
+
+func BenchmarkForwardPump(b *testing.B) {
+	benchmarkForwarding(b, false, pumpForward)
+}
+
+func BenchmarkForwardChannelsFiltered(b *testing.B) {
+	benchmarkForwarding(b, true, channelForward)
+}
+
+func BenchmarkForwardPumpFiltered(b *testing.B) {
+	benchmarkForwarding(b, true, pumpForward)
+}
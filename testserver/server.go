@@ -0,0 +1,327 @@
+// Package testserver is minimal stand-in for Minecraft server, used as upstream of proxy in local
+// development and tests. It logs players in (with BungeeCord legacy forwarding), spawns them
+// in empty void world, sends keep alives, echoes chat and kicks players when asked to:
+//
+//	/kick [reason]  in chat kicks player with reason
+//	Server.KickAfter kicks every player after given time
+//
+// Nothing else is simulated, packets other than chat, keep alives and teleport confirmations are ignored.
+package testserver
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Craftserve/potoq/packets"
+	"github.com/Craftserve/potoq/packets/nbt"
+	"github.com/google/uuid"
+)
+
+type Server struct {
+	// compression threshold sent to players, negative disables compression and 0 compresses all packets,
+	// like network-compression-threshold of vanilla server
+	Threshold int
+	// kick players connecting without BungeeCord forwarding data in handshake, like Spigot with bungeecord: true
+	RequireForwarding bool
+	// interval of keep alives, player which doesn't answer for 2 intervals is kicked, 0 means 15s like vanilla
+	KeepAliveInterval time.Duration
+	// if set, players are kicked with KickMessage after this time
+	KickAfter   time.Duration
+	KickMessage string
+	// connection errors are logged here, nil means standard logger
+	ErrorLog *log.Logger
+}
+
+// Accepts connections until listener is closed
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			if err := s.ServeConn(conn); err != nil && err != io.EOF {
+				s.logf("%s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+// Handles single connection until player disconnects or is kicked, conn is closed afterwards
+func (s *Server) ServeConn(conn net.Conn) error {
+	defer conn.Close()
+	sess := &session{server: s, conn: conn}
+	if err := sess.login(); err != nil {
+		return err
+	}
+	return sess.play()
+}
+
+// > login
+
+// Player data from BungeeCord legacy forwarding: "host\x00ip\x00uuid[\x00properties json]" in handshake host
+type Forwarding struct {
+	Host       string
+	IP         string
+	UUID       uuid.UUID
+	Properties string // json array, empty if absent
+}
+
+// Parses forwarding data from handshake host, ok is false if there isn't any
+func ParseForwarding(host string) (fwd Forwarding, ok bool) {
+	parts := strings.Split(host, "\x00")
+	if len(parts) != 3 && len(parts) != 4 {
+		return Forwarding{Host: host}, false
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return Forwarding{Host: host}, false
+	}
+	fwd = Forwarding{Host: parts[0], IP: parts[1], UUID: id}
+	if len(parts) == 4 {
+		fwd.Properties = parts[3]
+	}
+	return fwd, true
+}
+
+type session struct {
+	server    *Server
+	conn      net.Conn
+	reader    packets.PacketReader
+	writer    packets.PacketWriter
+	writeLock sync.Mutex
+	nickname  string
+	uuid      uuid.UUID
+}
+
+// UUID of player in offline mode server, like UUID.nameUUIDFromBytes in Java
+func offlineUUID(nickname string) uuid.UUID {
+	id := uuid.UUID(md5.Sum([]byte("OfflinePlayer:" + nickname)))
+	id[6] = id[6]&0x0f | 0x30 // version 3
+	id[8] = id[8]&0x3f | 0x80 // RFC 4122 variant
+	return id
+}
+
+func (sess *session) login() error {
+	sess.conn.SetDeadline(time.Now().Add(30 * time.Second))
+	defer sess.conn.SetDeadline(time.Time{})
+	sess.reader = packets.NewPacketReader(sess.conn, packets.NoCompression)
+	sess.writer = packets.NewPacketWriter(sess.conn, packets.NoCompression)
+
+	var handshake packets.HandshakePacket
+	if _, err := packets.ParsePackets(sess.reader, &handshake); err != nil {
+		return fmt.Errorf("handshake: %w", err)
+	}
+	if packets.ConnState(handshake.NextState) != packets.LOGIN {
+		return fmt.Errorf("handshake: unsupported next state %d", handshake.NextState)
+	}
+	var login_start packets.LoginStartPacket
+	if _, err := packets.ParsePackets(sess.reader, &login_start); err != nil {
+		return fmt.Errorf("login start: %w", err)
+	}
+	sess.nickname = login_start.Nickname
+
+	if handshake.Protocol != packets.ProtocolVersion {
+		return sess.loginKick(fmt.Sprintf("Outdated server! I'm still on %s", packets.GameVersion.Name))
+	}
+	fwd, ok := ParseForwarding(handshake.Host)
+	if !ok && sess.server.RequireForwarding {
+		return sess.loginKick("If you wish to use IP forwarding, please enable it in your BungeeCord config as well!")
+	}
+	if ok {
+		sess.uuid = fwd.UUID
+	} else {
+		sess.uuid = offlineUUID(sess.nickname)
+	}
+
+	if threshold := sess.server.Threshold; threshold >= 0 {
+		err := sess.writer.WritePacket(&packets.LoginCompressionPacket{Threshold: packets.VarInt(threshold)}, true)
+		if err != nil {
+			return err
+		}
+		sess.reader = packets.NewPacketReader(sess.conn, threshold)
+		sess.writer = packets.NewPacketWriter(sess.conn, threshold)
+	}
+	return sess.writer.WritePacket(&packets.LoginSuccessPacket{UID: sess.uuid, Username: sess.nickname}, true)
+}
+
+func (sess *session) loginKick(reason string) error {
+	sess.writer.WritePacket(packets.NewLoginKick(&packets.ChatMessage{Text: reason}), true)
+	return fmt.Errorf("%s kicked during login: %s", sess.nickname, reason)
+}
+
+// > play
+
+// Returned by ServeConn when player was kicked
+var ErrKicked = errors.New("kicked")
+
+func (sess *session) write(packet packets.Packet) error {
+	sess.writeLock.Lock()
+	defer sess.writeLock.Unlock()
+	return sess.writer.WritePacket(packet, true)
+}
+
+func (sess *session) kick(reason string) error {
+	if err := sess.write(packets.NewIngameKickTxt(reason)); err != nil {
+		return err
+	}
+	return fmt.Errorf("%s %w: %s", sess.nickname, ErrKicked, reason)
+}
+
+func (sess *session) play() error {
+	if err := sess.write(NewJoinGame()); err != nil {
+		return err
+	}
+	spawn := &packets.PlayerPositionAndLookPacketCB{X: 0.5, Y: 64, Z: 0.5, TeleportID: 1}
+	if err := sess.write(spawn); err != nil {
+		return err
+	}
+
+	incoming := make(chan packets.Packet)
+	read_err := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		read_err <- sess.readPackets(incoming, done)
+	}()
+
+	interval := sess.server.KeepAliveInterval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	keepalive := time.NewTicker(interval)
+	defer keepalive.Stop()
+	var kick_timer <-chan time.Time
+	if sess.server.KickAfter > 0 {
+		kick_timer = time.After(sess.server.KickAfter)
+	}
+	var pending_id int64 // keep alive without answer
+	var pending_since time.Time
+
+	for {
+		select {
+		case packet := <-incoming:
+			switch p := packet.(type) {
+			case *packets.KeepAlivePacketSB:
+				if p.ID != pending_id {
+					return sess.kick("Invalid keep alive")
+				}
+				pending_id = 0
+			case *packets.ChatMessagePacketSB:
+				if err := sess.chat(p.Message); err != nil {
+					return err
+				}
+			}
+		case err := <-read_err:
+			return err
+		case now := <-keepalive.C:
+			if pending_id != 0 {
+				if now.Sub(pending_since) >= 2*interval {
+					return sess.kick("Timed out")
+				}
+				continue
+			}
+			pending_id, pending_since = now.UnixNano(), now
+			if err := sess.write(&packets.KeepAlivePacketCB{ID: pending_id}); err != nil {
+				return err
+			}
+		case <-kick_timer:
+			return sess.kick(sess.server.KickMessage)
+		}
+	}
+}
+
+// parses packets handled by server, other ones are skipped
+func (sess *session) readPackets(incoming chan<- packets.Packet, done <-chan struct{}) error {
+	for {
+		packet, err := packets.ParsePackets(sess.reader,
+			&packets.KeepAlivePacketSB{}, &packets.ChatMessagePacketSB{}, &packets.TeleportConfirmPacketSB{})
+		if _, unexpected := err.(packets.ErrUnexpectedPacket); unexpected {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case incoming <- packet:
+		case <-done:
+			return nil
+		}
+	}
+}
+
+func (sess *session) chat(message string) error {
+	if message == "/kick" || strings.HasPrefix(message, "/kick ") {
+		reason := strings.TrimSpace(strings.TrimPrefix(message, "/kick"))
+		if reason == "" {
+			reason = "Kicked by test server"
+		}
+		return sess.kick(reason)
+	}
+	echo := &packets.ChatMessagePacketCB{Position: 0, Sender: sess.uuid}
+	if err := echo.SetMsg(&packets.ChatMessage{Text: "<" + sess.nickname + "> " + message}); err != nil {
+		return err
+	}
+	return sess.write(echo)
+}
+
+// > void world
+
+// vanilla 1.16.3 overworld dimension type, world has no chunks
+const overworldSNBT = `{piglin_safe:0b,natural:1b,ambient_light:0.0f,infiniburn:"minecraft:infiniburn_overworld",` +
+	`respawn_anchor_works:0b,has_skylight:1b,bed_works:1b,effects:"minecraft:overworld",has_raids:1b,` +
+	`logical_height:256,coordinate_scale:1.0d,ultrawarm:0b,has_ceiling:0b}`
+
+// client requires at least plains biome in codec
+const plainsSNBT = `{precipitation:"rain",depth:0.125f,temperature:0.8f,scale:0.05f,downfall:0.4f,category:"plains",` +
+	`effects:{sky_color:7907327,water_fog_color:329011,fog_color:12638463,water_color:4159204}}`
+
+// JoinGame of spectator in void overworld, dimension codec has single dimension type and biome
+func NewJoinGame() *packets.JoinGamePacketCB {
+	dimension, err := nbt.ParseCompound(overworldSNBT)
+	if err != nil {
+		panic(err)
+	}
+	plains, err := nbt.ParseCompound(plainsSNBT)
+	if err != nil {
+		panic(err)
+	}
+	registry := func(typ string, name string, element nbt.TagCompound) nbt.TagCompound {
+		entry := nbt.TagCompound{"name": nbt.TagString(name), "id": nbt.TagInt(0), "element": element}
+		return nbt.TagCompound{
+			"type":  nbt.TagString(typ),
+			"value": nbt.TagList{ElementType: nbt.TagTypeCompound, Elements: []nbt.Tag{entry}},
+		}
+	}
+	return &packets.JoinGamePacketCB{
+		PlayerEntity:     1,
+		GameMode:         3,
+		PreviousGameMode: 255, // none
+		AllWorldNames:    []packets.Identifier{"minecraft:overworld"},
+		DimensionCodec: nbt.TagCompound{
+			"minecraft:dimension_type": registry("minecraft:dimension_type", "minecraft:overworld", dimension),
+			"minecraft:worldgen/biome": registry("minecraft:worldgen/biome", "minecraft:plains", plains),
+		},
+		Dimension:           dimension,
+		DimensionId:         "minecraft:overworld",
+		MaxPlayers:          20,
+		ViewDistance:        2,
+		EnableRespawnScreen: true,
+		IsFlat:              true,
+	}
+}
@@ -0,0 +1,105 @@
+package testserver
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Craftserve/potoq/packets"
+	"github.com/Craftserve/potoq/potoqtest"
+)
+
+// player connected through proxy to test server
+func connect(t *testing.T, server *Server, nickname string) (*potoqtest.Player, chan error) {
+	upstream := potoqtest.NewUpstream("testserver")
+	t.Cleanup(upstream.Close)
+	player := potoqtest.Connect(t, upstream, nickname)
+	t.Cleanup(func() { player.Close() })
+	served := make(chan error, 1)
+	conn := upstream.AcceptConn(t)
+	go func() { served <- server.ServeConn(conn) }()
+	return player, served
+}
+
+func TestPlay(t *testing.T) {
+	server := &Server{Threshold: 64, RequireForwarding: true}
+	player, served := connect(t, server, "tester")
+
+	var join packets.JoinGamePacketCB
+	player.Expect(t, &join)
+	if _, ok := join.DimensionCodec["minecraft:dimension_type"]; !ok || join.DimensionId != "minecraft:overworld" {
+		t.Fatalf("join game: %v %s", join.DimensionCodec, join.DimensionId)
+	}
+	player.Expect(t, &packets.PlayerPositionAndLookPacketCB{})
+
+	player.Send(t, &packets.ChatMessagePacketSB{Message: "hello"})
+	var chat packets.ChatMessagePacketCB
+	player.Expect(t, &chat)
+	var text packets.ChatMessage
+	if err := json.Unmarshal([]byte(chat.Message), &text); err != nil || text.Text != "<tester> hello" || chat.Sender != player.UUID {
+		t.Fatalf("echo: %s from %s: %v", chat.Message, chat.Sender, err)
+	}
+
+	player.Send(t, &packets.ChatMessagePacketSB{Message: "/kick bye"})
+	var kick packets.KickPacketCB
+	player.Expect(t, &kick)
+	if !strings.Contains(kick.Message, "bye") {
+		t.Fatalf("kick message %s", kick.Message)
+	}
+	player.ExpectClosed(t)
+	if err := <-served; !errors.Is(err, ErrKicked) {
+		t.Fatalf("ServeConn: %v", err)
+	}
+}
+
+// next keep alive is sent only after answer to previous one, player which doesn't answer is kicked
+func TestKeepAlive(t *testing.T) {
+	server := &Server{KeepAliveInterval: 10 * time.Millisecond}
+	player, served := connect(t, server, "tester3")
+	var first, second packets.KeepAlivePacketCB
+	player.Expect(t, &first)
+	player.Send(t, &packets.KeepAlivePacketSB{ID: first.ID})
+	player.Expect(t, &second)
+	if second.ID == first.ID {
+		t.Fatalf("same keep alive id %d", first.ID)
+	}
+	var kick packets.KickPacketCB
+	player.Expect(t, &kick)
+	if !strings.Contains(kick.Message, "Timed out") {
+		t.Fatalf("kick message %s", kick.Message)
+	}
+	if err := <-served; !errors.Is(err, ErrKicked) {
+		t.Fatalf("ServeConn: %v", err)
+	}
+}
+
+func TestKickAfter(t *testing.T) {
+	server := &Server{KickAfter: 10 * time.Millisecond, KickMessage: "time is up"}
+	player, served := connect(t, server, "tester2")
+	var kick packets.KickPacketCB
+	player.Expect(t, &kick)
+	if !strings.Contains(kick.Message, "time is up") {
+		t.Fatalf("kick message %s", kick.Message)
+	}
+	if err := <-served; !errors.Is(err, ErrKicked) {
+		t.Fatalf("ServeConn: %v", err)
+	}
+}
+
+func TestParseForwarding(t *testing.T) {
+	fwd, ok := ParseForwarding("mc.example.com\x00203.0.113.7\x00dd823a0c-b94a-369f-acd6-ddd287e3180e\x00[]")
+	if !ok || fwd.Host != "mc.example.com" || fwd.IP != "203.0.113.7" || fwd.UUID.String() != "dd823a0c-b94a-369f-acd6-ddd287e3180e" || fwd.Properties != "[]" {
+		t.Fatalf("got %+v %v", fwd, ok)
+	}
+	if _, ok := ParseForwarding("mc.example.com"); ok {
+		t.Fatal("plain host parsed as forwarding")
+	}
+}
+
+func TestOfflineUUID(t *testing.T) {
+	if id := offlineUUID("Notch"); id.String() != "b50ad385-829d-3141-a216-7e7d7539ba7f" {
+		t.Fatalf("got %s", id)
+	}
+}
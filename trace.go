@@ -0,0 +1,337 @@
+package potoq
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Craftserve/potoq/packets"
+	"github.com/Craftserve/potoq/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// > packet tracing of single player, started with Handler.StartTrace, staff command or TraceHTTPHandler
+
+// Directory of trace files, each trace is written to <nickname>_<start time>.jsonl
+var TraceDir = "trace"
+
+// Trace file is rotated after TraceMaxSize bytes, TraceKeep rotated files are kept
+var TraceMaxSize int64 = 64 * 1024 * 1024
+var TraceKeep = 4
+
+// Selects traced packets, zero value traces everything
+type TraceOptions struct {
+	Direction  packets.Direction // 0 traces both directions
+	PacketIDs  []packets.VarInt  // empty traces all ids
+	ParsedOnly bool              // skip packets forwarded without parsing (no filter needs them)
+}
+
+// Parses options from words like "cb", "sb", "parsed" and packet ids ("0x0E", "14", "0x0E,0x22")
+func ParseTraceOptions(args []string) (opts TraceOptions, err error) {
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "":
+		case "cb":
+			opts.Direction = packets.ClientBound
+		case "sb":
+			opts.Direction = packets.ServerBound
+		case "parsed":
+			opts.ParsedOnly = true
+		default:
+			for _, s := range strings.Split(arg, ",") {
+				id, perr := strconv.ParseInt(s, 0, 32)
+				if perr != nil || id < 0 || id >= packets.MaxPacketID {
+					return opts, fmt.Errorf("invalid trace option %q", s)
+				}
+				opts.PacketIDs = append(opts.PacketIDs, packets.VarInt(id))
+			}
+		}
+	}
+	return
+}
+
+// inverse of ParseTraceOptions
+func (opts TraceOptions) String() string {
+	var words []string
+	if opts.Direction != 0 {
+		words = append(words, strings.ToLower(opts.Direction.String()))
+	}
+	if opts.ParsedOnly {
+		words = append(words, "parsed")
+	}
+	if len(opts.PacketIDs) > 0 {
+		ids := make([]string, len(opts.PacketIDs))
+		for i, id := range opts.PacketIDs {
+			ids[i] = fmt.Sprintf("0x%02X", id)
+		}
+		words = append(words, strings.Join(ids, ","))
+	}
+	if len(words) == 0 {
+		return "all"
+	}
+	return strings.Join(words, " ")
+}
+
+func (opts *TraceOptions) match(direction packets.Direction, id packets.VarInt, parsed bool) bool {
+	if (opts.Direction != 0 && opts.Direction != direction) || (opts.ParsedOnly && !parsed) {
+		return false
+	}
+	if len(opts.PacketIDs) == 0 {
+		return true
+	}
+	for _, traced := range opts.PacketIDs {
+		if traced == id {
+			return true
+		}
+	}
+	return false
+}
+
+// single line of trace file
+type traceRecord struct {
+	Time     time.Time       `json:"time"`
+	Elapsed  float64         `json:"elapsed"`            // seconds since start of trace
+	Size     int             `json:"size,omitempty"`     // size of packet read from connection, 0 for injected packets
+	Injected bool            `json:"injected,omitempty"` // created by proxy, see Handler.InjectPackets
+	Dropped  bool            `json:"dropped,omitempty"`
+	Modified bool            `json:"modified,omitempty"` // fields were changed by filter
+	Packet   json.RawMessage `json:"packet"`             // see packets.MarshalPacketJSON
+}
+
+type packetTracer struct {
+	TraceOptions
+	path    string
+	started time.Time
+
+	mu  sync.Mutex          // held while writing to out
+	out *utils.RotatingFile // nil after close
+
+	pendingLock sync.Mutex
+	pending     [][]byte // lines added under handler lock, written by flush
+}
+
+// queues record, it's written to file by flush, outside of handler lock
+func (tracer *packetTracer) add(record *traceRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	tracer.pendingLock.Lock()
+	tracer.pending = append(tracer.pending, append(line, '\n'))
+	tracer.pendingLock.Unlock()
+	return nil
+}
+
+// writes queued records, taking them under mu keeps their order between pumps
+func (tracer *packetTracer) flush() error {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	return tracer.writePending()
+}
+
+func (tracer *packetTracer) writePending() error {
+	tracer.pendingLock.Lock()
+	lines := tracer.pending
+	tracer.pending = nil
+	tracer.pendingLock.Unlock()
+	if tracer.out == nil { // stopped by other goroutine
+		return nil
+	}
+	for _, line := range lines {
+		if _, err := tracer.out.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// records queued before close are written
+func (tracer *packetTracer) close() error {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	if tracer.out == nil {
+		return nil
+	}
+	err := tracer.writePending()
+	if cerr := tracer.out.Close(); err == nil {
+		err = cerr
+	}
+	tracer.out = nil
+	return err
+}
+
+// Trace currently running for player, returned by Handler.TraceStatus
+type TraceStatus struct {
+	Player  string    `json:"player"`
+	Path    string    `json:"path"`
+	Started time.Time `json:"started"`
+	Options string    `json:"options"`
+}
+
+// Starts tracing packets of player to new file in TraceDir, previous trace is stopped. Safe to call from any goroutine.
+func (handler *Handler) StartTrace(opts TraceOptions) (*TraceStatus, error) {
+	if err := os.MkdirAll(TraceDir, 0755); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tracer := &packetTracer{
+		TraceOptions: opts,
+		path:         filepath.Join(TraceDir, fmt.Sprintf("%s_%s.jsonl", handler.Nickname, now.Format("20060102-150405.000"))),
+		started:      now,
+	}
+	out, err := utils.OpenRotatingFile(tracer.path, TraceMaxSize, TraceKeep)
+	if err != nil {
+		return nil, err
+	}
+	tracer.out = out
+	if old := handler.swapTracer(tracer); old != nil {
+		old.close()
+	}
+	handler.Log().WithFields(logrus.Fields{
+		"path":    tracer.path,
+		"options": opts.String(),
+	}).Info("Packet trace started")
+	return tracer.status(handler), nil
+}
+
+// Stops trace started with StartTrace, returns false if player wasn't traced
+func (handler *Handler) StopTrace() bool {
+	tracer := handler.swapTracer(nil)
+	if tracer == nil {
+		return false
+	}
+	if err := tracer.close(); err != nil {
+		handler.Log().WithError(err).Error("Packet trace close error")
+	}
+	handler.Log().WithField("path", tracer.path).Info("Packet trace stopped")
+	return true
+}
+
+// Returns nil if player isn't traced
+func (handler *Handler) TraceStatus() *TraceStatus {
+	if tracer := handler.tracer(); tracer != nil {
+		return tracer.status(handler)
+	}
+	return nil
+}
+
+func (tracer *packetTracer) status(handler *Handler) *TraceStatus {
+	return &TraceStatus{
+		Player:  handler.Nickname,
+		Path:    tracer.path,
+		Started: tracer.started,
+		Options: tracer.TraceOptions.String(),
+	}
+}
+
+func (handler *Handler) tracer() *packetTracer {
+	tracer, _ := handler.trace.Load().(*packetTracer)
+	return tracer
+}
+
+func (handler *Handler) swapTracer(tracer *packetTracer) (old *packetTracer) {
+	handler.traceLock.Lock()
+	defer handler.traceLock.Unlock()
+	old = handler.tracer()
+	handler.trace.Store(tracer)
+	return
+}
+
+// Queues packet record, called under handler lock. Must be called before packet is written,
+// streamed packets are consumed by writing.
+func (handler *Handler) tracePacket(tracer *packetTracer, packet packets.Packet, direction packets.Direction, record traceRecord) {
+	data, err := packets.MarshalPacketJSON(packet, handler.State(), direction)
+	if err == nil {
+		now := time.Now()
+		record.Time, record.Elapsed, record.Packet = now, now.Sub(tracer.started).Seconds(), data
+		err = tracer.add(&record)
+	}
+	if err != nil {
+		handler.stopTraceOnError(tracer, err)
+	}
+}
+
+// Writes records queued by tracePacket, called after handler lock is released
+func (handler *Handler) flushTrace() {
+	if tracer := handler.tracer(); tracer != nil {
+		if err := tracer.flush(); err != nil {
+			handler.stopTraceOnError(tracer, err)
+		}
+	}
+}
+
+func (handler *Handler) stopTraceOnError(tracer *packetTracer, err error) {
+	handler.Log().WithError(err).Error("Packet trace error, stopping trace")
+	handler.traceLock.Lock()
+	if handler.tracer() == tracer { // not replaced by new trace in meantime
+		handler.trace.Store((*packetTracer)(nil))
+	}
+	handler.traceLock.Unlock()
+	tracer.close()
+}
+
+// > admin API
+
+// Admin API of packet tracing, should be served only on private address, eg.
+//
+//	http.Handle("/potoq/trace", potoq.TraceHTTPHandler)
+//
+//	GET    /potoq/trace                                      list of running traces
+//	POST   /potoq/trace?player=nick[&options=cb+parsed+0x0E]  start trace, options as in ParseTraceOptions
+//	DELETE /potoq/trace?player=nick                          stop trace
+var TraceHTTPHandler http.Handler = http.HandlerFunc(serveTraceHTTP)
+
+func serveTraceHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		statuses := []*TraceStatus{}
+		Players.Range(func(handler *Handler) bool {
+			if status := handler.TraceStatus(); status != nil {
+				statuses = append(statuses, status)
+			}
+			return true
+		})
+		writeTraceJSON(w, http.StatusOK, statuses)
+		return
+	}
+
+	nickname := r.FormValue("player")
+	handler := Players.GetByNickname(nickname)
+	if handler == nil {
+		writeTraceJSON(w, http.StatusNotFound, map[string]string{"error": "player not found: " + nickname})
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		opts, err := ParseTraceOptions(strings.Fields(r.FormValue("options")))
+		if err != nil {
+			writeTraceJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		status, err := handler.StartTrace(opts)
+		if err != nil {
+			writeTraceJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeTraceJSON(w, http.StatusOK, status)
+	case http.MethodDelete:
+		if !handler.StopTrace() {
+			writeTraceJSON(w, http.StatusNotFound, map[string]string{"error": "player isn't traced: " + nickname})
+			return
+		}
+		writeTraceJSON(w, http.StatusOK, map[string]string{"stopped": handler.Nickname})
+	default:
+		writeTraceJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+func writeTraceJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,161 @@
+package potoq
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Craftserve/potoq/packets"
+	"gopkg.in/tomb.v1"
+)
+
+func TestParseTraceOptions(t *testing.T) {
+	opts, err := ParseTraceOptions([]string{"SB", "parsed", "0x03,0x0E", "20"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := opts.String(); s != "sb parsed 0x03,0x0E,0x14" {
+		t.Fatalf("options: %s", s)
+	}
+	if !opts.match(packets.ServerBound, 0x0E, true) || opts.match(packets.ServerBound, 0x0E, false) ||
+		opts.match(packets.ClientBound, 0x0E, true) || opts.match(packets.ServerBound, 0x04, true) {
+		t.Fatalf("invalid matching of %s", opts)
+	}
+	if _, err := ParseTraceOptions([]string{"0x1000"}); err == nil {
+		t.Fatal("invalid packet id accepted")
+	}
+}
+
+func TestPacketTrace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	TraceDir = dir
+	defer func() { TraceDir = "trace" }()
+
+	var stream bytes.Buffer
+	w := packets.NewPacketWriter(&stream, packets.NoCompression)
+	for _, msg := range []string{"hello", "drop me", "premium"} {
+		w.WritePacket(&packets.ChatMessagePacketSB{Message: msg}, false)
+	}
+	w.WritePacket(&packets.HeldItemChangePacketSB{Slot: 1}, true)
+
+	handler := &Handler{
+		Nickname:  "tester",
+		UpstreamW: packets.NewPacketWriter(bufio.NewWriter(ioutil.Discard), packets.NoCompression),
+	}
+	handler.setState(packets.PLAY)
+	handler.AddPacketFilter(&packets.ChatMessagePacketSB{}, func(handler *Handler, packet packets.Packet) error {
+		p := packet.(*packets.ChatMessagePacketSB)
+		p.Message = strings.Replace(p.Message, "premium", "cukier", -1)
+		if strings.HasPrefix(p.Message, "drop") {
+			return ErrDropPacket
+		}
+		return nil
+	})
+	status, err := handler.StartTrace(TraceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.pump(packets.NewPacketReader(&stream, packets.NoCompression), packets.ServerBound, &tomb.Tomb{})
+	if !handler.StopTrace() || handler.TraceStatus() != nil {
+		t.Fatal("trace not stopped")
+	}
+
+	data, err := ioutil.ReadFile(status.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("%d trace lines:\n%s", len(lines), data)
+	}
+	var records []traceRecord
+	for _, line := range lines {
+		var record traceRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("%s: %s", err, line)
+		}
+		records = append(records, record)
+	}
+	if records[0].Dropped || records[0].Modified || records[0].Size == 0 {
+		t.Fatalf("untouched packet: %s", lines[0])
+	}
+	if !records[1].Dropped {
+		t.Fatalf("dropped packet: %s", lines[1])
+	}
+	if !records[2].Modified || !strings.Contains(string(records[2].Packet), `"Message":"cukier"`) {
+		t.Fatalf("modified packet: %s", lines[2])
+	}
+	if !strings.Contains(string(records[3].Packet), `"raw":`) {
+		t.Fatalf("unparsed packet: %s", lines[3])
+	}
+}
+
+// fails every write, counts attempts
+type failingPacketWriter struct {
+	writes int
+}
+
+func (w *failingPacketWriter) WritePacket(packet packets.Packet, flush bool) error {
+	w.writes++
+	return errors.New("write failed")
+}
+
+func (w *failingPacketWriter) Flush() error {
+	return nil
+}
+
+// records are queued under handler lock and written to file after it's released
+func TestTraceWrittenOutsideLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	TraceDir = dir
+	defer func() { TraceDir = "trace" }()
+
+	failing := &failingPacketWriter{}
+	handler := &Handler{Nickname: "locked", DownstreamW: failing}
+	handler.setState(packets.PLAY)
+	status, err := handler.StartTrace(TraceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer handler.StopTrace()
+	lines := func() int {
+		data, err := ioutil.ReadFile(status.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(data), "\n")
+	}
+
+	handler.lock.Lock()
+	forwardOnce(t, handler, serializePacket(t, &packets.ChatMessagePacketSB{Message: "traced"}, packets.NoCompression), packets.NoCompression)
+	cmd := &injectPacketCommand{
+		Direction: packets.ClientBound,
+		Payload:   []packets.Packet{packets.NewIngameKickTxt("first"), packets.NewIngameKickTxt("second")},
+	}
+	err = cmd.Execute(handler)
+	if n := lines(); n != 0 {
+		t.Fatalf("%d records written under handler lock", n)
+	}
+	handler.lock.Unlock()
+
+	if err == nil || failing.writes != 1 {
+		t.Fatalf("injecting after write error, %d writes: %v", failing.writes, err)
+	}
+	handler.flushTrace()
+	if n := lines(); n != 2 {
+		t.Fatalf("%d records written after unlock", n)
+	}
+}